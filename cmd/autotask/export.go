@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+	"github.com/asachs01/autotask-go/pkg/export"
+)
+
+const exportUsage = `Usage: autotask export -entity <name> [flags]
+
+Streams the entities matching a filter to CSV, JSONL or Parquet. Credentials
+are read from $AUTOTASK_USERNAME, $AUTOTASK_SECRET and
+$AUTOTASK_INTEGRATION_CODE.
+
+Flags:
+`
+
+// runExport exports the entities matching a filter
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), exportUsage)
+		fs.PrintDefaults()
+	}
+	entity := fs.String("entity", "", "entity to export, such as Tickets")
+	filter := fs.String("filter", "", `query filter, such as "status=1"; every entity when empty`)
+	format := fs.String("format", "", "csv, jsonl or parquet; taken from the -out extension, or csv for stdout")
+	columns := fs.String("columns", "", "comma-separated fields to export; every field when empty")
+	labels := fs.Bool("labels", false, "write picklist labels instead of values")
+	out := fs.String("out", "", "output file; stdout when empty")
+	_ = fs.Parse(args)
+
+	if *entity == "" {
+		fs.Usage()
+		return errors.New("missing -entity")
+	}
+
+	opts := export.Options{Format: export.Format(strings.ToLower(*format)), PicklistLabels: *labels}
+	if *columns != "" {
+		opts.Columns = strings.Split(*columns, ",")
+	}
+
+	client := autotask.NewClient(
+		os.Getenv("AUTOTASK_USERNAME"),
+		os.Getenv("AUTOTASK_SECRET"),
+		os.Getenv("AUTOTASK_INTEGRATION_CODE"),
+	)
+	service := autotask.NewBaseEntityService(client, *entity)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var count int
+	var err error
+	if *out == "" {
+		count, err = export.Write(ctx, os.Stdout, &service, *filter, opts)
+	} else {
+		count, err = export.WriteFile(ctx, *out, &service, *filter, opts)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d %s\n", count, *entity)
+	return nil
+}
@@ -0,0 +1,53 @@
+// Command autotask is a command-line companion to the autotask-go library.
+//
+// Usage:
+//
+//	autotask webhook send    sign and POST a sample or saved webhook payload
+//	autotask webhook record  record incoming webhooks to a JSONL file
+//	autotask webhook replay  replay recorded webhooks against a handler
+//	autotask export          export entities to CSV, JSONL or Parquet
+//	autotask import          create entities from CSV or JSON with a mapping
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage: autotask <command> [arguments]
+
+Commands:
+  webhook   send, record and replay webhooks
+  export    export entities to CSV, JSONL or Parquet
+  import    create entities from CSV or JSON with a mapping
+
+Run "autotask <command> -h" for help on a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "webhook":
+		err = runWebhook(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "autotask: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "autotask: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/asachs01/autotask-go/pkg/webhook"
+)
+
+const webhookUsage = `Usage: autotask webhook <send|record|replay> [flags]
+
+  send     sign and POST a sample payload, or a payload read from a file
+  record   listen for webhooks and append them to a JSONL file
+  replay   POST the webhooks in a JSONL file in order
+
+The signing secret defaults to $WEBHOOK_SECRET.
+`
+
+// runWebhook runs a webhook subcommand
+func runWebhook(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, webhookUsage)
+		return errors.New("missing webhook subcommand")
+	}
+
+	switch args[0] {
+	case "send":
+		return webhookSend(args[1:])
+	case "record":
+		return webhookRecord(args[1:])
+	case "replay":
+		return webhookReplay(args[1:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(webhookUsage)
+		return nil
+	}
+	return fmt.Errorf("unknown webhook subcommand %q", args[0])
+}
+
+// webhookSend signs and POSTs one payload
+func webhookSend(args []string) error {
+	fs := flag.NewFlagSet("webhook send", flag.ExitOnError)
+	target := fs.String("url", "http://localhost:8080/webhook", "handler URL")
+	secret := fs.String("secret", os.Getenv("WEBHOOK_SECRET"), "secret used to sign the payload")
+	file := fs.String("file", "", "payload file, or - for stdin; a sample payload is generated when empty")
+	entity := fs.String("entity", webhook.EntityTicket, "entity type of the sample payload")
+	action := fs.String("action", string(webhook.ActionUpdate), "action of the sample payload")
+	id := fs.Int64("id", 1, "entity ID of the sample payload")
+	_ = fs.Parse(args)
+
+	var body []byte
+	var err error
+	switch *file {
+	case "":
+		body, err = webhook.SamplePayload(*entity, webhook.Action(*action), *id)
+	case "-":
+		body, err = io.ReadAll(os.Stdin)
+	default:
+		body, err = os.ReadFile(*file)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load payload: %w", err)
+	}
+
+	sender := webhook.NewSender(*target)
+	sender.SetSecret(*secret)
+	status, err := sender.Send(context.Background(), body)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d %s\n", status, http.StatusText(status))
+	if status >= 300 {
+		return fmt.Errorf("handler responded %d", status)
+	}
+	return nil
+}
+
+// webhookRecord serves an endpoint that records every webhook it receives
+func webhookRecord(args []string) error {
+	fs := flag.NewFlagSet("webhook record", flag.ExitOnError)
+	listen := fs.String("listen", ":8080", "address to listen on")
+	out := fs.String("out", "webhooks.jsonl", "JSONL file to append recordings to")
+	forward := fs.String("forward", "", "URL of a handler to forward webhooks to after recording")
+	_ = fs.Parse(args)
+
+	f, err := os.OpenFile(*out, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", *out, err)
+	}
+	defer f.Close()
+
+	var next http.Handler
+	if *forward != "" {
+		target, err := url.Parse(*forward)
+		if err != nil {
+			return fmt.Errorf("invalid forward URL: %w", err)
+		}
+		next = &httputil.ReverseProxy{Rewrite: func(r *httputil.ProxyRequest) {
+			r.Out.URL = target
+			r.Out.Host = target.Host
+		}}
+	}
+
+	server := &http.Server{Addr: *listen, Handler: webhook.NewRecorder(f, next)}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+
+	fmt.Printf("Recording webhooks on %s to %s\n", *listen, *out)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// webhookReplay POSTs recorded webhooks in order
+func webhookReplay(args []string) error {
+	fs := flag.NewFlagSet("webhook replay", flag.ExitOnError)
+	file := fs.String("file", "webhooks.jsonl", "JSONL file written by webhook record")
+	target := fs.String("url", "http://localhost:8080/webhook", "handler URL")
+	secret := fs.String("secret", os.Getenv("WEBHOOK_SECRET"), "secret used to re-sign payloads; recorded signatures are kept when empty")
+	speed := fs.Float64("speed", 0, "replay speed relative to the recording, such as 1 for real time; 0 sends back to back")
+	_ = fs.Parse(args)
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", *file, err)
+	}
+	recordings, err := webhook.ReadRecordings(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sender := webhook.NewSender(*target)
+	sender.SetSecret(*secret)
+	results, err := sender.Replay(ctx, recordings, *speed)
+
+	failed := 0
+	for i, result := range results {
+		switch {
+		case result.Err != nil:
+			failed++
+			fmt.Printf("%d: %v\n", i+1, result.Err)
+		case result.StatusCode >= 300:
+			failed++
+			fmt.Printf("%d: %d %s\n", i+1, result.StatusCode, http.StatusText(result.StatusCode))
+		}
+	}
+	fmt.Printf("Replayed %d of %d webhooks, %d failed\n", len(results), len(recordings), failed)
+
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d webhooks failed", failed)
+	}
+	return nil
+}
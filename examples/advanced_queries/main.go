@@ -53,7 +53,7 @@ func main() {
 	fmt.Printf("Found %d tickets assigned to specific resources\n", len(ticketResponse2.Items))
 	for i, t := range ticketResponse2.Items {
 		if i < 3 { // Only print the first 3
-			fmt.Printf("  - %s (ID: %d, Assigned To: %d)\n", t.Title, t.ID, t.AssignedResourceID)
+			fmt.Printf("  - %s (ID: %d, Assigned To: %d)\n", t.Title, t.ID, t.AssignedResourceID.ValueOr(0))
 		}
 	}
 
@@ -72,7 +72,7 @@ func main() {
 	for i, t := range ticketResponse3.Items {
 		if i < 3 { // Only print the first 3
 			fmt.Printf("  - %s (ID: %d, Priority: %d, Assigned To: %d)\n",
-				t.Title, t.ID, t.Priority, t.AssignedResourceID)
+				t.Title, t.ID, t.Priority, t.AssignedResourceID.ValueOr(0))
 		}
 	}
 
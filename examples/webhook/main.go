@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,6 +11,7 @@ import (
 	"time"
 
 	"github.com/asachs01/autotask-go/pkg/autotask"
+	"github.com/asachs01/autotask-go/pkg/webhook"
 )
 
 func main() {
@@ -25,34 +25,48 @@ func main() {
 	// Enable debug mode
 	client.SetDebugMode(true)
 
-	// Set webhook secret for verification
-	client.Webhooks().SetWebhookSecret(os.Getenv("WEBHOOK_SECRET"))
+	// Create a receiver that verifies the X-Hook-Signature header with the
+	// webhook's secret key
+	receiver := webhook.NewReceiver(os.Getenv("WEBHOOK_SECRET"))
 
-	// Register webhook handlers for different event types
-	client.Webhooks().RegisterHandler("ticket.created", handleTicketCreated)
-	client.Webhooks().RegisterHandler("ticket.updated", handleTicketUpdated)
-	client.Webhooks().RegisterHandler("ticket.deleted", handleTicketDeleted)
+	// Log every event, recover from handler panics and bound handler time
+	receiver.Use(
+		webhook.LoggingMiddleware(nil),
+		webhook.RecoverMiddleware(),
+		webhook.TimeoutMiddleware(10*time.Second),
+	)
+
+	// Register typed webhook handlers for ticket events
+	receiver.OnTicketCreated(handleTicketCreated)
+	receiver.OnTicketUpdated(handleTicketUpdated)
+	receiver.OnTicketDeleted(handleTicketDeleted)
 
-	// Create a webhook (if needed)
-	// This registers your webhook URL with Autotask
-	// Note: You only need to do this once, not on every startup
+	// Create or update the webhook subscription (if needed)
+	// This registers your webhook URL with Autotask and is safe to repeat
 	if os.Getenv("REGISTER_WEBHOOK") == "true" {
 		ctx := context.Background()
 		webhookURL := os.Getenv("WEBHOOK_URL")
-		events := []string{"ticket.created", "ticket.updated", "ticket.deleted"}
 
 		fmt.Println("Registering webhook at:", webhookURL)
-		err := client.Webhooks().CreateWebhook(ctx, webhookURL, events)
+		results, err := autotask.EnsureWebhooks(ctx, client, []autotask.WebhookSpec{{
+			Entity:           webhook.EntityTicket,
+			Name:             "autotask-go webhook example",
+			URL:              webhookURL,
+			SecretKey:        os.Getenv("WEBHOOK_SECRET"),
+			Create:           true,
+			Update:           true,
+			Delete:           true,
+			SubscribedFields: []string{"Status", "Priority", "AssignedResourceID"},
+			DisplayFields:    []string{"Title", "TicketNumber"},
+		}})
 		if err != nil {
-			log.Fatalf("Failed to create webhook: %v", err)
+			log.Fatalf("Failed to register webhook: %v", err)
 		}
-		fmt.Println("Webhook registered successfully")
+		fmt.Printf("Webhook %d registered (changed: %v)\n", results[0].WebhookID, results[0].Changed())
 	}
 
 	// Set up HTTP server to handle webhook callbacks
-	http.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) {
-		client.Webhooks().HandleWebhook(w, r)
-	})
+	http.Handle("/webhook", receiver)
 
 	// Start the HTTP server
 	server := &http.Server{
@@ -88,28 +102,23 @@ func main() {
 	fmt.Println("Server gracefully stopped")
 }
 
-// Handler for ticket.created events
-func handleTicketCreated(event *autotask.WebhookEvent) error {
+// Handler for ticket create events
+func handleTicketCreated(ctx context.Context, event *webhook.TicketEvent) error {
 	fmt.Printf("Ticket created: ID=%d\n", event.EntityID)
 
-	// You can unmarshal the event.Data into a specific struct if needed
-	var ticketData map[string]interface{}
-	if err := json.Unmarshal(event.Data, &ticketData); err != nil {
-		return fmt.Errorf("failed to parse ticket data: %w", err)
-	}
-
-	fmt.Printf("Ticket details: %+v\n", ticketData)
+	// The fields sent with the event are decoded into event.Ticket
+	fmt.Printf("Ticket details: %+v\n", *event.Ticket)
 	return nil
 }
 
-// Handler for ticket.updated events
-func handleTicketUpdated(event *autotask.WebhookEvent) error {
-	fmt.Printf("Ticket updated: ID=%d\n", event.EntityID)
+// Handler for ticket update events
+func handleTicketUpdated(ctx context.Context, event *webhook.TicketEvent) error {
+	fmt.Printf("Ticket updated: ID=%d fields=%v\n", event.EntityID, event.FieldNames())
 	return nil
 }
 
-// Handler for ticket.deleted events
-func handleTicketDeleted(event *autotask.WebhookEvent) error {
+// Handler for ticket delete events
+func handleTicketDeleted(ctx context.Context, event *webhook.TicketEvent) error {
 	fmt.Printf("Ticket deleted: ID=%d\n", event.EntityID)
 	return nil
 }
@@ -463,17 +463,23 @@ func TestEntityPagination(t *testing.T) {
 	// Setup test server
 	server, _, entityService := setupTestServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Return response based on URL
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-
+		var body string
 		switch r.URL.Path {
 		case "/pagination-test":
-			// Handle pagination test
-			if _, err := w.Write([]byte(`{"items": []}`)); err != nil {
-				t.Errorf("Failed to write response: %v", err)
-			}
+			body = `{"items": [{"id": 1}], "pageDetails": {"pageNumber": 1}}`
+		case "/next-page":
+			body = `{"items": [{"id": 2}], "pageDetails": {"pageNumber": 2}}`
+		case "/prev-page":
+			body = `{"items": [{"id": 1}], "pageDetails": {"pageNumber": 1}}`
 		default:
 			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Errorf("Failed to write response: %v", err)
 		}
 	}))
 	defer server.Close()
@@ -1,3 +1,8 @@
+// Package logger provides the JSON logger used by the internal client.
+//
+// Deprecated: the public client logs through log/slog; see
+// autotask.Client.SetLogger. This package is kept for the internal client
+// only and uses the autotask.LogLevel scale rather than its own.
 package logger
 
 import (
@@ -10,20 +15,6 @@ import (
 	"github.com/asachs01/autotask-go/pkg/autotask"
 )
 
-// LogLevel represents the level of logging
-type LogLevel int
-
-const (
-	// LogLevelDebug represents debug level logging
-	LogLevelDebug LogLevel = iota
-	// LogLevelInfo represents info level logging
-	LogLevelInfo
-	// LogLevelWarn represents warning level logging
-	LogLevelWarn
-	// LogLevelError represents error level logging
-	LogLevelError
-)
-
 // LogEntry represents a structured log entry
 type LogEntry struct {
 	Timestamp time.Time              `json:"timestamp"`
@@ -33,6 +24,8 @@ type LogEntry struct {
 }
 
 // Logger handles logging for the Autotask client
+//
+// Deprecated: use a *slog.Logger with autotask.Client.SetLogger instead.
 type Logger struct {
 	level     autotask.LogLevel
 	logger    *log.Logger
@@ -133,3 +133,51 @@ func (t *Telemetry) RecordRateLimit(ctx context.Context, waitTime time.Duration)
 	)
 	rateLimitWaitHistogram.Record(ctx, float64(waitTime.Milliseconds()))
 }
+
+// StartWebhookSpan starts a new span for processing a webhook event
+func (t *Telemetry) StartWebhookSpan(ctx context.Context, entityType, action string) (context.Context, trace.Span) {
+	if !t.enabled {
+		return ctx, nil
+	}
+
+	ctx, span := t.tracer.Start(ctx, fmt.Sprintf("autotask.webhook.%s.%s", entityType, action))
+	span.SetAttributes(
+		attribute.String("webhook.entity_type", entityType),
+		attribute.String("webhook.action", action),
+	)
+
+	return ctx, span
+}
+
+// EndWebhookSpan ends a webhook span and records webhook metrics
+func (t *Telemetry) EndWebhookSpan(ctx context.Context, span trace.Span, entityType, action string, duration time.Duration, err error) {
+	if !t.enabled {
+		return
+	}
+
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+	} else {
+		span.SetStatus(codes.Ok, "success")
+	}
+	span.End()
+
+	attrs := metric.WithAttributes(
+		attribute.String("entity_type", entityType),
+		attribute.String("action", action),
+		attribute.Bool("error", err != nil),
+	)
+
+	eventCounter, _ := t.meter.Int64Counter(
+		"autotask.webhook.events",
+		metric.WithDescription("Number of webhook events processed"),
+	)
+	eventCounter.Add(ctx, 1, attrs)
+
+	durationHistogram, _ := t.meter.Float64Histogram(
+		"autotask.webhook.duration",
+		metric.WithDescription("Webhook processing time in milliseconds"),
+	)
+	durationHistogram.Record(ctx, float64(duration.Milliseconds()), attrs)
+}
@@ -0,0 +1,212 @@
+package autotask
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// DefaultBulkConcurrency is the number of requests a bulk operation runs at once by default
+const DefaultBulkConcurrency = 4
+
+// ErrBulkSkipped is the error of items that were not attempted because an
+// earlier item failed and StopOnError was set
+var ErrBulkSkipped = errors.New("skipped after an earlier failure")
+
+// BulkOptions controls a bulk operation
+type BulkOptions struct {
+	// Concurrency is the number of requests in flight at once. Requests are
+	// still subject to the client's rate limiter.
+	Concurrency int
+
+	// StopOnError stops starting new items after the first failure. Items
+	// that were not started get ErrBulkSkipped.
+	StopOnError bool
+
+	// OnProgress is called after each item finishes, one call at a time,
+	// with the number of items finished so far
+	OnProgress func(done, total int, result BulkResult)
+}
+
+// BulkResult is the outcome of one item of a bulk operation
+type BulkResult struct {
+	// Index is the position of the item in the input
+	Index int
+
+	// ID is the entity ID: the new ID for creates, and the target ID for
+	// updates and deletes
+	ID int64
+
+	// Err is the error for the item, or nil when it succeeded
+	Err error
+}
+
+// BulkError is returned by bulk operations when some items failed
+type BulkError struct {
+	Entity string
+	Total  int
+	Failed []BulkResult
+}
+
+// Error summarizes the failures
+func (e *BulkError) Error() string {
+	msg := fmt.Sprintf("%d of %d %s failed", len(e.Failed), e.Total, e.Entity)
+	if len(e.Failed) > 0 {
+		msg += fmt.Sprintf(": item %d: %v", e.Failed[0].Index, e.Failed[0].Err)
+	}
+	return msg
+}
+
+// Unwrap returns the item errors
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, result := range e.Failed {
+		errs[i] = result.Err
+	}
+	return errs
+}
+
+// BulkCreate creates entities with one request each, since the Autotask
+// REST API has no batch endpoint. It returns a result for every entity, in
+// input order, and a *BulkError when any failed.
+func BulkCreate(ctx context.Context, service EntityService, entities []interface{}, opts BulkOptions) ([]BulkResult, error) {
+	entityName := service.GetEntityName()
+	return runBulk(ctx, entityName, len(entities), opts, func(ctx context.Context, i int) (int64, error) {
+		return createEntity(ctx, service.GetClient(), entityName, entityName, nil, entities[i])
+	})
+}
+
+// BulkUpdate updates entities with one request each. Each entity must carry
+// its id, as a struct field or map key. It returns a result for every
+// entity, in input order, and a *BulkError when any failed.
+func BulkUpdate(ctx context.Context, service EntityService, entities []interface{}, opts BulkOptions) ([]BulkResult, error) {
+	return runBulk(ctx, service.GetEntityName(), len(entities), opts, func(ctx context.Context, i int) (int64, error) {
+		id, err := entityID(entities[i])
+		if err != nil {
+			return 0, err
+		}
+		_, err = service.Update(ctx, id, entities[i])
+		return id, err
+	})
+}
+
+// BulkDelete deletes entities by ID with one request each. It returns a
+// result for every ID, in input order, and a *BulkError when any failed.
+func BulkDelete(ctx context.Context, service EntityService, ids []int64, opts BulkOptions) ([]BulkResult, error) {
+	return runBulk(ctx, service.GetEntityName(), len(ids), opts, func(ctx context.Context, i int) (int64, error) {
+		return ids[i], service.Delete(ctx, ids[i])
+	})
+}
+
+// runBulk runs do for each of n items on a bounded worker pool
+func runBulk(ctx context.Context, entityName string, n int, opts BulkOptions, do func(ctx context.Context, i int) (int64, error)) ([]BulkResult, error) {
+	ctx = withOperation(ctx, entityName, "bulk")
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBulkConcurrency
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	results := make([]BulkResult, n)
+	for i := range results {
+		results[i] = BulkResult{Index: i, Err: ErrBulkSkipped}
+	}
+
+	var (
+		mu     sync.Mutex
+		done   int
+		failed bool
+		wg     sync.WaitGroup
+	)
+	indexes := make(chan int)
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				mu.Lock()
+				stop := opts.StopOnError && failed
+				mu.Unlock()
+				if stop || ctx.Err() != nil {
+					continue
+				}
+
+				id, err := do(ctx, i)
+
+				mu.Lock()
+				results[i] = BulkResult{Index: i, ID: id, Err: err}
+				done++
+				if err != nil {
+					failed = true
+				}
+				if opts.OnProgress != nil {
+					opts.OnProgress(done, n, results[i])
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		mu.Lock()
+		stop := opts.StopOnError && failed
+		mu.Unlock()
+		if stop || ctx.Err() != nil {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		for i := range results {
+			if results[i].Err == ErrBulkSkipped {
+				results[i].Err = err
+			}
+		}
+	}
+
+	var bulkErr *BulkError
+	for _, result := range results {
+		if result.Err != nil {
+			if bulkErr == nil {
+				bulkErr = &BulkError{Entity: entityName, Total: n}
+			}
+			bulkErr.Failed = append(bulkErr.Failed, result)
+		}
+	}
+	if bulkErr != nil {
+		return results, bulkErr
+	}
+	return results, nil
+}
+
+// entityID returns the id of an entity given as a struct or map
+func entityID(entity interface{}) (int64, error) {
+	values, err := toFieldMap(entity)
+	if err != nil {
+		return 0, err
+	}
+	var id int64
+	switch v := lookupFieldValue(values, "id").(type) {
+	case float64:
+		id = int64(v)
+	case int64:
+		id = v
+	case int:
+		id = int64(v)
+	case string:
+		id, _ = strconv.ParseInt(v, 10, 64)
+	}
+	if id == 0 {
+		return 0, fmt.Errorf("entity has no id")
+	}
+	return id, nil
+}
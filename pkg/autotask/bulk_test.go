@@ -0,0 +1,135 @@
+package autotask
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBulkCreate(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	var inFlight, maxInFlight int32
+	var nextID int64 = 100
+	server.AddHandler("/TestEntities", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"itemId": atomic.AddInt64(&nextID, 1)})
+	})
+
+	service := NewBaseEntityService(server.NewTestClient(), "TestEntities")
+	var entityService EntityService = &service
+
+	entities := make([]interface{}, 6)
+	for i := range entities {
+		entities[i] = map[string]interface{}{"name": "Entity"}
+	}
+
+	var progress []int
+	results, err := BulkCreate(context.Background(), entityService, entities, BulkOptions{
+		Concurrency: 2,
+		OnProgress: func(done, total int, result BulkResult) {
+			AssertEqual(t, 6, total, "total should match")
+			progress = append(progress, done)
+		},
+	})
+
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 6, len(results), "should have a result per entity")
+	for i, result := range results {
+		AssertEqual(t, i, result.Index, "results should be in input order")
+		AssertNil(t, result.Err, "item should succeed")
+		AssertTrue(t, result.ID > 100, "item should have its new ID")
+	}
+	AssertEqual(t, 6, len(progress), "progress should be reported per item")
+	AssertEqual(t, 6, progress[5], "last progress should count every item")
+	AssertTrue(t, atomic.LoadInt32(&maxInFlight) <= 2, "concurrency should be bounded")
+}
+
+func TestBulkDeletePartialFailure(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/TestEntities/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/2") {
+			server.RespondWithError(w, http.StatusNotFound, "not found", nil)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	service := NewBaseEntityService(server.NewTestClient(), "TestEntities")
+	var entityService EntityService = &service
+
+	results, err := BulkDelete(context.Background(), entityService, []int64{1, 2, 3}, BulkOptions{})
+
+	var bulkErr *BulkError
+	AssertTrue(t, errors.As(err, &bulkErr), "error should be a BulkError")
+	AssertEqual(t, 3, bulkErr.Total, "total should match")
+	AssertEqual(t, 1, len(bulkErr.Failed), "one item should fail")
+	AssertEqual(t, int64(2), bulkErr.Failed[0].ID, "failed item should keep its ID")
+	AssertNil(t, results[0].Err, "other items should continue")
+	AssertNil(t, results[2].Err, "other items should continue")
+}
+
+func TestBulkStopOnError(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	var mu sync.Mutex
+	calls := 0
+	server.AddHandler("/TestEntities/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		server.RespondWithError(w, http.StatusBadRequest, "invalid", nil)
+	})
+
+	service := NewBaseEntityService(server.NewTestClient(), "TestEntities")
+	var entityService EntityService = &service
+
+	results, err := BulkDelete(context.Background(), entityService, []int64{1, 2, 3, 4}, BulkOptions{Concurrency: 1, StopOnError: true})
+
+	AssertNotNil(t, err, "error should not be nil")
+	AssertEqual(t, 1, calls, "no items should start after the failure")
+	AssertFalse(t, errors.Is(results[0].Err, ErrBulkSkipped), "first item should have its own error")
+	for _, result := range results[1:] {
+		AssertTrue(t, errors.Is(result.Err, ErrBulkSkipped), "later items should be skipped")
+	}
+}
+
+func TestBulkUpdateMissingID(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/TestEntities/", func(w http.ResponseWriter, r *http.Request) {
+		AssertEqual(t, "/TestEntities/7", r.URL.Path, "entity should be patched by ID")
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"item": map[string]interface{}{"id": 7}})
+	})
+
+	service := NewBaseEntityService(server.NewTestClient(), "TestEntities")
+	var entityService EntityService = &service
+
+	results, err := BulkUpdate(context.Background(), entityService, []interface{}{
+		map[string]interface{}{"id": 7, "name": "Updated"},
+		map[string]interface{}{"name": "No ID"},
+	}, BulkOptions{})
+
+	AssertNotNil(t, err, "error should not be nil")
+	AssertNil(t, results[0].Err, "entity with an ID should be updated")
+	AssertEqual(t, int64(7), results[0].ID, "ID should be reported")
+	AssertNotNil(t, results[1].Err, "entity without an ID should fail")
+}
@@ -0,0 +1,476 @@
+package autotask
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default settings for the response cache
+const (
+	DefaultCacheTTL  = 5 * time.Minute
+	DefaultCacheSize = 10000
+)
+
+// Cache stores API response bodies for the client's response cache. Keys
+// start with the lowercase entity name and a slash, such as "companies/12"
+// or "companies/query?{...}", so an entity's entries can be dropped by
+// prefix. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored for key, if it hasn't expired
+	Get(key string) ([]byte, bool)
+
+	// Set stores value for key until ttl has passed
+	Set(key string, value []byte, ttl time.Duration)
+
+	// Delete removes key
+	Delete(key string)
+
+	// DeletePrefix removes every key starting with prefix
+	DeletePrefix(prefix string)
+}
+
+// CacheConfig configures the response cache
+type CacheConfig struct {
+	// Backend stores the responses. Nil uses an LRUCache of DefaultCacheSize entries.
+	Backend Cache
+
+	// TTL is how long responses are reused
+	TTL time.Duration
+
+	// EntityTTL overrides TTL for individual entities, such as a longer TTL
+	// for Resources. A negative TTL leaves the entity uncached.
+	EntityTTL map[string]time.Duration
+}
+
+// DefaultCacheConfig returns a default cache configuration
+func DefaultCacheConfig() *CacheConfig {
+	return &CacheConfig{
+		TTL: DefaultCacheTTL,
+	}
+}
+
+// cacheBypassKey is the context key for skipping cached responses
+type cacheBypassKey struct{}
+
+// WithoutCache returns a copy of ctx whose reads go to the API even when a
+// cached response exists. The fresh response still refreshes the cache.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+// cacheBypassed reports whether ctx skips cached responses
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassKey{}).(bool)
+	return bypass
+}
+
+// cacheState holds the client's response cache settings
+type cacheState struct {
+	mu        sync.RWMutex
+	backend   Cache
+	ttl       time.Duration
+	entityTTL map[string]time.Duration
+
+	// generation changes on every invalidation so a read that raced a
+	// write doesn't store what it fetched
+	generation atomic.Uint64
+}
+
+// SetCache caches the responses of Get and read-only queries. Entries are
+// dropped when this client creates, updates or deletes the entity, or when
+// InvalidateCache is called. A nil config disables the cache.
+func (c *client) SetCache(config *CacheConfig) {
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+
+	if config == nil {
+		c.cache.backend = nil
+		return
+	}
+
+	backend := config.Backend
+	if backend == nil {
+		backend = NewLRUCache(DefaultCacheSize)
+	}
+	ttl := config.TTL
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	entityTTL := make(map[string]time.Duration, len(config.EntityTTL))
+	for entity, d := range config.EntityTTL {
+		entityTTL[strings.ToLower(entity)] = d
+	}
+
+	c.cache.backend = backend
+	c.cache.ttl = ttl
+	c.cache.entityTTL = entityTTL
+	c.cache.generation.Add(1)
+}
+
+// InvalidateCache drops the cached responses for an entity: the entity with
+// the given ID and every query on the entity type. An ID of zero drops
+// everything cached for the entity type.
+func (c *client) InvalidateCache(entityName string, id int64) {
+	backend, _ := c.cacheSettings(entityName)
+	if backend == nil {
+		return
+	}
+	c.cache.generation.Add(1)
+
+	entity := strings.ToLower(entityName)
+	if id <= 0 {
+		backend.DeletePrefix(entity + "/")
+		return
+	}
+	backend.Delete(fmt.Sprintf("%s/%d", entity, id))
+	backend.DeletePrefix(fmt.Sprintf("%s/%d/", entity, id))
+	backend.DeletePrefix(entity + "/query")
+}
+
+// cacheEnabled reports whether responses are cached
+func (c *client) cacheEnabled() bool {
+	c.cache.mu.RLock()
+	defer c.cache.mu.RUnlock()
+	return c.cache.backend != nil
+}
+
+// cacheSettings returns the cache backend and the TTL for an entity. A nil
+// backend means the entity isn't cached.
+func (c *client) cacheSettings(entityName string) (Cache, time.Duration) {
+	c.cache.mu.RLock()
+	defer c.cache.mu.RUnlock()
+
+	ttl := c.cache.ttl
+	if d, ok := c.cache.entityTTL[strings.ToLower(entityName)]; ok {
+		ttl = d
+	}
+	return c.cache.backend, ttl
+}
+
+// cached wraps rt so reads are served from the cache and writes invalidate it
+func (c *client) cached(rt RoundTrip) RoundTrip {
+	return func(req *http.Request) (*http.Response, error) {
+		segments := c.cachePath(req)
+		if len(segments) == 0 {
+			return rt(req)
+		}
+
+		if isWriteMethod(req.Method) && !isQueryRequest(req) {
+			resp, err := rt(req)
+			if err == nil && resp.StatusCode < http.StatusMultipleChoices {
+				c.invalidatePath(req, segments)
+			}
+			return resp, err
+		}
+
+		backend, ttl := c.cacheSettings(segments[0])
+		if backend == nil || ttl < 0 {
+			return rt(req)
+		}
+		key, ok, err := cacheKey(req, segments)
+		if err != nil || !ok {
+			return rt(req)
+		}
+
+		ctx := req.Context()
+		if !cacheBypassed(ctx) {
+			if body, ok := backend.Get(key); ok {
+				loggerFromContext(ctx).DebugContext(ctx, "Cache hit", append(requestLogAttrs(ctx), "key", key)...)
+				return cachedResponse(req, body), nil
+			}
+		}
+
+		generation := c.cache.generation.Load()
+		resp, err := rt(req)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			return resp, err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		if c.cache.generation.Load() == generation {
+			backend.Set(key, body, ttl)
+		}
+		return resp, nil
+	}
+}
+
+// cachedItems adds the entities among ids that have a cached Get to found
+// and returns the other IDs, with the cache generation they were read at
+func (c *client) cachedItems(ctx context.Context, entityName string, ids []int64, found map[int64]json.RawMessage) ([]int64, uint64) {
+	generation := c.cache.generation.Load()
+	backend, ttl := c.cacheSettings(entityName)
+	if backend == nil || ttl < 0 || cacheBypassed(ctx) {
+		return ids, generation
+	}
+
+	entity := strings.ToLower(entityName)
+	missing := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if body, ok := backend.Get(fmt.Sprintf("%s/%d", entity, id)); ok {
+			var cached struct {
+				Item json.RawMessage `json:"item"`
+			}
+			if json.Unmarshal(body, &cached) == nil && len(cached.Item) > 0 && string(cached.Item) != "null" {
+				found[id] = cached.Item
+				continue
+			}
+		}
+		missing = append(missing, id)
+	}
+	return missing, generation
+}
+
+// storeItems caches entities fetched by a batched Get as the responses of
+// their Gets, unless the cache was invalidated since generation
+func (c *client) storeItems(entityName string, items map[int64]json.RawMessage, generation uint64) {
+	backend, ttl := c.cacheSettings(entityName)
+	if backend == nil || ttl < 0 || c.cache.generation.Load() != generation {
+		return
+	}
+
+	entity := strings.ToLower(entityName)
+	for id, data := range items {
+		body, err := json.Marshal(struct {
+			Item json.RawMessage `json:"item"`
+		}{data})
+		if err == nil {
+			backend.Set(fmt.Sprintf("%s/%d", entity, id), body, ttl)
+		}
+	}
+}
+
+// cachePath returns the lowercase path segments of req below the API base URL
+func (c *client) cachePath(req *http.Request) []string {
+	p := req.URL.Path
+	if c.baseURL != nil {
+		base := strings.TrimSuffix(c.baseURL.Path, "/")
+		if len(p) >= len(base) && strings.EqualFold(p[:len(base)], base) {
+			p = p[len(base):]
+		}
+	}
+	p = strings.Trim(strings.ToLower(p), "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// invalidatePath drops the cached responses affected by a successful write
+// to the path of req
+func (c *client) invalidatePath(req *http.Request, segments []string) {
+	entity := segments[0]
+	switch {
+	case len(segments) > 1:
+		if id, err := strconv.ParseInt(segments[1], 10, 64); err == nil {
+			c.InvalidateCache(entity, id)
+		} else {
+			c.InvalidateCache(entity, 0)
+		}
+	case req.Method == http.MethodPost:
+		// A create only changes query results
+		if backend, _ := c.cacheSettings(entity); backend != nil {
+			c.cache.generation.Add(1)
+			backend.DeletePrefix(entity + "/query")
+		}
+	default:
+		// Updates sent to the collection carry the ID in the body
+		c.InvalidateCache(entity, 0)
+	}
+
+	// Child writes such as Tickets/1/Notes also change the child entity
+	if info := operationFromContext(req.Context()); info.entity != "" && !strings.EqualFold(info.entity, entity) {
+		c.InvalidateCache(info.entity, 0)
+	}
+}
+
+// cacheKey returns the cache key of a read: the entity and ID for a Get,
+// and the path with the normalized search for a query. Other reads, such as
+// child collections and pages, aren't cached.
+func cacheKey(req *http.Request, segments []string) (string, bool, error) {
+	if !isQueryRequest(req) {
+		if req.Method != http.MethodGet || len(segments) != 2 {
+			return "", false, nil
+		}
+		if _, err := strconv.ParseInt(segments[1], 10, 64); err != nil {
+			return "", false, nil
+		}
+		return segments[0] + "/" + segments[1], true, nil
+	}
+
+	var search []byte
+	switch req.Method {
+	case http.MethodGet:
+		search = []byte(req.URL.Query().Get("search"))
+	case http.MethodPost:
+		if req.Body != nil && req.Body != http.NoBody {
+			body, err := io.ReadAll(req.Body)
+			_ = req.Body.Close()
+			if err != nil {
+				return "", false, fmt.Errorf("failed to read query body: %w", err)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			search = body
+		}
+	default:
+		return "", false, nil
+	}
+
+	normalized, err := normalizeSearch(search)
+	if err != nil {
+		return "", false, nil
+	}
+	return strings.Join(segments, "/") + "?" + normalized, true, nil
+}
+
+// normalizeSearch re-encodes search JSON so equivalent searches share a key
+func normalizeSearch(search []byte) (string, error) {
+	search = bytes.TrimSpace(search)
+	if len(search) == 0 {
+		return "", nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(search, &v); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// cachedResponse builds the response for a cache hit
+func cachedResponse(req *http.Request, body []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// LRUCache is an in-memory Cache that evicts the least recently used entry
+// once it holds its maximum number of entries
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// lruEntry is a value stored in an LRUCache
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUCache returns an LRU cache holding up to size entries
+func NewLRUCache(size int) *LRUCache {
+	if size < 1 {
+		size = 1
+	}
+	return &LRUCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the value stored for key, if it hasn't expired
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Set stores value for key until ttl has passed
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := time.Now().Add(ttl)
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value = value
+		entry.expires = expires
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Delete removes key
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+}
+
+// DeletePrefix removes every key starting with prefix
+func (c *LRUCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(elem)
+		}
+	}
+}
+
+// Len returns the number of entries, including expired ones not yet evicted
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove drops an entry. It must be called with c.mu held.
+func (c *LRUCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*lruEntry).key)
+}
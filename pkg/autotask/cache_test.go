@@ -0,0 +1,93 @@
+package autotask
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestResponseCache(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	gets, queries := 0, 0
+	server.AddHandler("/Companies/", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/Companies/query":
+			queries++
+			server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"items": []map[string]interface{}{{"id": 1}}})
+		case r.Method == http.MethodGet:
+			gets++
+			server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 1, "companyName": "Acme"}})
+		default:
+			server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 1}})
+		}
+	})
+
+	client := server.NewTestClient()
+	client.SetCache(DefaultCacheConfig())
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Companies().Get(ctx, 1)
+		AssertNil(t, err, "error should be nil")
+	}
+	AssertEqual(t, 1, gets, "repeated gets should be served from the cache")
+
+	var result ListResponse
+	AssertNil(t, client.Companies().Query(ctx, "companyName eq Acme", &result), "error should be nil")
+	AssertNil(t, client.Companies().Query(ctx, "companyName eq Acme", &result), "error should be nil")
+	AssertEqual(t, 1, queries, "repeated queries should be served from the cache")
+
+	_, err := client.Companies().Get(WithoutCache(ctx), 1)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 2, gets, "WithoutCache should skip the cache")
+
+	_, err = client.Companies().Update(ctx, 1, map[string]interface{}{"id": 1, "companyName": "Acme Ltd"})
+	AssertNil(t, err, "error should be nil")
+	_, err = client.Companies().Get(ctx, 1)
+	AssertNil(t, err, "error should be nil")
+	AssertNil(t, client.Companies().Query(ctx, "companyName eq Acme", &result), "error should be nil")
+	AssertEqual(t, 3, gets, "update should invalidate the entity")
+	AssertEqual(t, 2, queries, "update should invalidate queries")
+
+	client.InvalidateCache("companies", 1)
+	_, err = client.Companies().Get(ctx, 1)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 4, gets, "InvalidateCache should drop the entity")
+
+	client.SetCache(nil)
+	_, err = client.Companies().Get(ctx, 1)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 5, gets, "disabled cache should not be used")
+}
+
+func TestNormalizeSearch(t *testing.T) {
+	a, err := normalizeSearch([]byte(`{"MaxRecords": 500, "filter": [{"op":"eq","field":"id","value":1}]}`))
+	AssertNil(t, err, "error should be nil")
+	b, err := normalizeSearch([]byte(`{"filter":[{"field":"id","value":1,"op":"eq"}],"MaxRecords":500}`))
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, a, b, "equivalent searches should share a key")
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("companies/1", []byte("a"), time.Minute)
+	cache.Set("companies/2", []byte("b"), time.Minute)
+	_, _ = cache.Get("companies/1")
+	cache.Set("companies/3", []byte("c"), time.Minute)
+
+	_, ok := cache.Get("companies/2")
+	AssertFalse(t, ok, "least recently used entry should be evicted")
+	_, ok = cache.Get("companies/1")
+	AssertTrue(t, ok, "recently used entry should be kept")
+
+	cache.Set("companies/4", []byte("d"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	_, ok = cache.Get("companies/4")
+	AssertFalse(t, ok, "expired entry should not be returned")
+
+	cache.DeletePrefix("companies/")
+	AssertEqual(t, 0, cache.Len(), "prefix delete should drop matching entries")
+}
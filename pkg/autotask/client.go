@@ -7,11 +7,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -49,8 +50,34 @@ type client struct {
 	// Rate limiter
 	rateLimiter *RateLimiter
 
-	// Logger
-	logger *Logger
+	// Logger. slogger is the logger used for output: the one set with
+	// SetLogger or an adapter over logger. It is swapped atomically so it
+	// can change while requests are in flight.
+	logger  *Logger
+	slogger atomic.Pointer[slog.Logger]
+
+	// Middleware applied around every request
+	middleware   []Middleware
+	middlewareMu sync.RWMutex
+
+	// Resources validated for impersonation
+	impersonation impersonationCache
+
+	// Entity field metadata
+	fieldInfo fieldInfoCache
+
+	// Dry-run setting and recorded writes
+	dryRun dryRunState
+
+	// Response cache for reads
+	cache cacheState
+
+	// Identical reads in flight and Gets waiting to be batched
+	flights    flightGroup
+	getBatches GetBatcher
+
+	// Tenant time zone used to show API date-times
+	timeZone atomic.Pointer[time.Location]
 
 	// Entity clients
 	companiesService          *companiesService
@@ -63,6 +90,13 @@ type client struct {
 	timeEntriesService        *timeEntriesService
 	contractsService          *contractsService
 	configurationItemsService *configurationItemsService
+
+	// Webhook subscription clients
+	companyWebhooksService           *webhookSubscriptionService
+	contactWebhooksService           *webhookSubscriptionService
+	ticketWebhooksService            *webhookSubscriptionService
+	ticketNoteWebhooksService        *webhookSubscriptionService
+	configurationItemWebhooksService *webhookSubscriptionService
 }
 
 // NewClient returns a new Autotask API client
@@ -80,6 +114,11 @@ func NewClient(username, secret, integrationCode string) Client {
 		rateLimiter:     NewRateLimiter(60),       // Default to 60 requests per minute
 		logger:          New(LogLevelInfo, false), // Default to info level, debug off
 	}
+	c.slogger.Store(c.legacyLogger())
+	c.middleware = []Middleware{
+		RateLimitMiddleware(c.rateLimiter),
+		LoggingMiddleware(nil),
+	}
 
 	// Initialize services
 	c.companiesService = &companiesService{
@@ -112,6 +151,11 @@ func NewClient(username, secret, integrationCode string) Client {
 	c.configurationItemsService = &configurationItemsService{
 		BaseEntityService: NewBaseEntityService(c, "ConfigurationItems"),
 	}
+	c.companyWebhooksService = newWebhookSubscriptionService(c, "Company")
+	c.contactWebhooksService = newWebhookSubscriptionService(c, "Contact")
+	c.ticketWebhooksService = newWebhookSubscriptionService(c, "Ticket")
+	c.ticketNoteWebhooksService = newWebhookSubscriptionService(c, "TicketNote")
+	c.configurationItemWebhooksService = newWebhookSubscriptionService(c, "ConfigurationItem")
 
 	return c
 }
@@ -127,10 +171,68 @@ func (c *client) SetDebugMode(debug bool) {
 }
 
 // SetLogOutput sets the output writer for the logger
-func (c *client) SetLogOutput(output *os.File) {
+func (c *client) SetLogOutput(output io.Writer) {
 	c.logger.SetOutput(output)
 }
 
+// SetLogger replaces the client's logger. Level filtering is then up to the
+// logger's handler; SetLogLevel and SetDebugMode no longer apply. A nil
+// logger restores the built-in one.
+func (c *client) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		c.SetLogHandler(nil)
+		return
+	}
+	c.SetLogHandler(logger.Handler())
+}
+
+// SetLogHandler routes the client's logs to the given slog handler. A nil
+// handler restores the built-in logger.
+func (c *client) SetLogHandler(handler slog.Handler) {
+	if handler == nil {
+		c.slogger.Store(c.legacyLogger())
+		return
+	}
+	c.slogger.Store(slog.New(contextHandler{handler}))
+}
+
+// log returns the logger used for client output
+func (c *client) log() *slog.Logger {
+	if logger := c.slogger.Load(); logger != nil {
+		return logger
+	}
+	c.slogger.CompareAndSwap(nil, c.legacyLogger())
+	return c.slogger.Load()
+}
+
+// legacyLogger returns an slog adapter over the built-in logger, which
+// follows SetLogLevel, SetDebugMode and SetLogOutput
+func (c *client) legacyLogger() *slog.Logger {
+	logger := c.logger
+	if logger == nil {
+		logger = New(LogLevelInfo, false)
+	}
+	return slog.New(contextHandler{logger.Handler()})
+}
+
+// SetTimeZone sets the tenant's time zone. The API returns date-times in
+// UTC; convert them with TimeZone to show them as the tenant sees them. A nil
+// location is UTC.
+func (c *client) SetTimeZone(loc *time.Location) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	c.timeZone.Store(loc)
+}
+
+// TimeZone returns the tenant's time zone, UTC unless set with SetTimeZone
+func (c *client) TimeZone() *time.Location {
+	if loc := c.timeZone.Load(); loc != nil {
+		return loc
+	}
+	return time.UTC
+}
+
 // GetZoneInfo gets the zone information for the Autotask account
 func (c *client) GetZoneInfo() (*ZoneInfo, error) {
 	c.zoneMutex.Lock()
@@ -142,9 +244,7 @@ func (c *client) GetZoneInfo() (*ZoneInfo, error) {
 
 	// Build URL with user parameter
 	zoneURL := fmt.Sprintf("%s?user=%s", BaseZoneInfoURL, url.QueryEscape(c.username))
-	c.logger.Debug("Requesting zone info", map[string]interface{}{
-		"url": zoneURL,
-	})
+	c.log().Debug("Requesting zone info", "url", zoneURL)
 
 	req, err := http.NewRequest("GET", zoneURL, nil)
 	if err != nil {
@@ -165,31 +265,28 @@ func (c *client) GetZoneInfo() (*ZoneInfo, error) {
 	// Set API integration code
 	req.Header.Set("ApiIntegrationCode", c.integrationCode)
 
-	// Log request headers
-	headers := make(map[string]string)
-	for key, values := range req.Header {
-		headers[key] = values[0]
-	}
-	c.logger.LogRequest(req.Method, req.URL.String(), headers)
+	c.log().Debug("HTTP Request",
+		"method", req.Method,
+		"url", req.URL.String(),
+		"headers", headersForLog(req.Header),
+	)
 
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
-			c.logger.Error("Failed to close response body", map[string]interface{}{
-				"error": cerr.Error(),
-			})
+			c.log().Error("Failed to close response body", "error", cerr)
 		}
 	}()
 
-	// Log response headers
-	respHeaders := make(map[string]string)
-	for key, values := range resp.Header {
-		respHeaders[key] = values[0]
-	}
-	c.logger.LogResponse(resp.StatusCode, respHeaders)
+	c.log().Debug("HTTP Response",
+		"status_code", resp.StatusCode,
+		"duration", time.Since(start),
+		"headers", headersForLog(resp.Header),
+	)
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, c.handleErrorResponse(resp)
@@ -200,9 +297,7 @@ func (c *client) GetZoneInfo() (*ZoneInfo, error) {
 	if err != nil {
 		return nil, err
 	}
-	c.logger.Debug("Zone info response", map[string]interface{}{
-		"body": string(body),
-	})
+	c.log().Debug("Zone info response", "body", string(body))
 
 	// Create a new reader with the same body for json.Decoder
 	bodyReader := bytes.NewReader(body)
@@ -212,17 +307,13 @@ func (c *client) GetZoneInfo() (*ZoneInfo, error) {
 		return nil, err
 	}
 
-	c.logger.Debug("Parsed zone info", map[string]interface{}{
-		"zone_info": zoneInfo,
-	})
+	c.log().Debug("Parsed zone info", "zone_info", zoneInfo)
 
 	c.zoneInfo = &zoneInfo
 	// Add API version to base URL, ensuring lowercase
 	baseURL := strings.Replace(zoneInfo.URL, "ATServicesRest", "atservicesrest", 1)
 	baseURL = fmt.Sprintf("%sv1.0/", baseURL)
-	c.logger.Debug("Using base URL", map[string]interface{}{
-		"base_url": baseURL,
-	})
+	c.log().Debug("Using base URL", "base_url", baseURL)
 	c.baseURL, err = url.Parse(baseURL)
 	if err != nil {
 		return nil, err
@@ -275,40 +366,100 @@ func (c *client) NewRequest(ctx context.Context, method, urlStr string, body int
 	// Set API integration code
 	req.Header.Set("ApiIntegrationCode", c.integrationCode)
 
-	// Log request headers
-	headers := make(map[string]string)
-	for key, values := range req.Header {
-		headers[key] = values[0]
+	// Apply per-request headers carried in the context
+	for key, values := range headersFromContext(ctx) {
+		req.Header[key] = append([]string(nil), values...)
+	}
+
+	// Attribute writes to the impersonated resource, if any
+	if err := c.applyImpersonation(ctx, req); err != nil {
+		return nil, err
 	}
-	c.logger.LogRequest(req.Method, req.URL.String(), headers)
 
 	return req, nil
 }
 
+// headersKey is the context key for per-request headers
+type headersKey struct{}
+
+// WithHeader returns a copy of ctx that sets the given header on every
+// request created with it, in addition to the client's standard headers.
+func WithHeader(ctx context.Context, key, value string) context.Context {
+	headers := headersFromContext(ctx).Clone()
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	headers.Set(key, value)
+	return context.WithValue(ctx, headersKey{}, headers)
+}
+
+// headersFromContext returns the per-request headers carried by ctx
+func headersFromContext(ctx context.Context) http.Header {
+	if ctx == nil {
+		return nil
+	}
+	headers, _ := ctx.Value(headersKey{}).(http.Header)
+	return headers
+}
+
+// Use appends middleware to the client's chain. Middleware added with Use
+// runs inside the existing chain, closest to the HTTP transport.
+func (c *client) Use(middleware ...Middleware) {
+	c.middlewareMu.Lock()
+	defer c.middlewareMu.Unlock()
+	c.middleware = append(c.middleware, middleware...)
+}
+
+// SetMiddleware replaces the client's chain, including the built-in rate
+// limiting and logging middleware. The first middleware is the outermost.
+func (c *client) SetMiddleware(middleware ...Middleware) {
+	c.middlewareMu.Lock()
+	defer c.middlewareMu.Unlock()
+	c.middleware = append([]Middleware(nil), middleware...)
+}
+
+// Middleware returns a copy of the client's middleware chain
+func (c *client) Middleware() []Middleware {
+	c.middlewareMu.RLock()
+	defer c.middlewareMu.RUnlock()
+	return append([]Middleware(nil), c.middleware...)
+}
+
 // Do sends an API request and returns the API response
 func (c *client) Do(req *http.Request, v interface{}) (*http.Response, error) {
-	// Apply rate limiting
-	c.rateLimiter.Wait()
+	// Tag the request so every log line for it can be correlated
+	ctx := req.Context()
+	if RequestIDFromContext(ctx) == "" {
+		ctx = WithRequestID(ctx, newRequestID())
+	}
+	logger := c.log()
+	ctx = withLogger(ctx, logger)
+	req = req.WithContext(ctx)
+
+	rt := chain(c.httpClient.Do, c.Middleware()...)
+	if isWriteMethod(req.Method) && !isQueryRequest(req) && c.isDryRun(ctx) {
+		// Record the write instead of sending it
+		rt = c.planMutation
+	} else {
+		if c.coalescingEnabled() {
+			rt = c.coalesced(rt)
+		}
+		if c.cacheEnabled() {
+			rt = c.cached(rt)
+		}
+	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := rt(req)
 	if err != nil {
 		return nil, err
 	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
-			c.logger.Error("Failed to close response body", map[string]interface{}{
-				"error": cerr.Error(),
-			})
+			logger.ErrorContext(ctx, "Failed to close response body",
+				append(requestLogAttrs(ctx), "error", cerr)...)
 		}
 	}()
 
-	// Log response headers
-	respHeaders := make(map[string]string)
-	for key, values := range resp.Header {
-		respHeaders[key] = values[0]
-	}
-	c.logger.LogResponse(resp.StatusCode, respHeaders)
-
 	// Handle successful responses
 	if resp.StatusCode == http.StatusNoContent {
 		// For 204 No Content responses, there's no body to parse
@@ -326,9 +477,7 @@ func (c *client) Do(req *http.Request, v interface{}) (*http.Response, error) {
 	}
 
 	// Log the response body for debugging
-	c.logger.Debug("Response body", map[string]interface{}{
-		"body": string(body),
-	})
+	logger.DebugContext(ctx, "Response body", append(requestLogAttrs(ctx), "body", string(body))...)
 
 	// If v is nil, we don't need to parse the response
 	if v == nil {
@@ -352,15 +501,17 @@ func (c *client) Do(req *http.Request, v interface{}) (*http.Response, error) {
 // handleErrorResponse handles error responses from the API
 func (c *client) handleErrorResponse(resp *http.Response) error {
 	var errorResp ErrorResponse
-	errorResp.Response = resp
 	data, err := io.ReadAll(resp.Body)
 	if err == nil && data != nil {
 		if unmarshalErr := json.Unmarshal(data, &errorResp); unmarshalErr != nil {
 			// If we can't unmarshal the error response, just log it and continue
 			// We'll still return the error response with the status code
-			c.logger.LogError(unmarshalErr)
+			c.log().ErrorContext(resp.Request.Context(), "Failed to parse error response",
+				append(requestLogAttrs(resp.Request.Context()), "error", unmarshalErr)...)
 		}
 	}
+	// Set the response after unmarshaling so the body can't overwrite it
+	errorResp.Response = resp
 	return &errorResp
 }
 
@@ -413,3 +564,28 @@ func (c *client) Contracts() ContractsService {
 func (c *client) ConfigurationItems() ConfigurationItemsService {
 	return c.configurationItemsService
 }
+
+// CompanyWebhooks returns the company webhook subscription service
+func (c *client) CompanyWebhooks() WebhookSubscriptionService {
+	return c.companyWebhooksService
+}
+
+// ContactWebhooks returns the contact webhook subscription service
+func (c *client) ContactWebhooks() WebhookSubscriptionService {
+	return c.contactWebhooksService
+}
+
+// TicketWebhooks returns the ticket webhook subscription service
+func (c *client) TicketWebhooks() WebhookSubscriptionService {
+	return c.ticketWebhooksService
+}
+
+// TicketNoteWebhooks returns the ticket note webhook subscription service
+func (c *client) TicketNoteWebhooks() WebhookSubscriptionService {
+	return c.ticketNoteWebhooksService
+}
+
+// ConfigurationItemWebhooks returns the configuration item webhook subscription service
+func (c *client) ConfigurationItemWebhooks() WebhookSubscriptionService {
+	return c.configurationItemWebhooksService
+}
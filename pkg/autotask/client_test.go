@@ -15,25 +15,6 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// FilterItem represents a single condition in an Autotask API filter
-type FilterItem struct {
-	Field string      `json:"field"`
-	Op    string      `json:"op"`
-	Value interface{} `json:"value"`
-}
-
-// FilterCondition represents a filter condition that can contain multiple items
-type FilterCondition struct {
-	Op    string       `json:"op"`
-	Items []FilterItem `json:"items"`
-}
-
-// QueryParams represents the parameters for an Autotask API query
-type QueryParams struct {
-	MaxRecords int               `json:"MaxRecords"`
-	Filter     []FilterCondition `json:"filter"` // Note: lowercase 'filter' to match API
-}
-
 // AssertNotEqual asserts that two values are not equal
 func AssertNotEqual(t *testing.T, expected, actual interface{}, message string) {
 	t.Helper()
@@ -285,7 +266,8 @@ type testClient struct {
 }
 
 func newTestClient(serverURL string) *client {
-	u, err := url.Parse(serverURL)
+	// Root requests at the API path, as the zone's base URL does
+	u, err := url.Parse(serverURL + "/ATServicesRest/V1.0/")
 	if err != nil {
 		panic(err)
 	}
@@ -345,16 +327,17 @@ func TestQueryWithEmptyFilter(t *testing.T) {
 		assert.Equal(t, http.MethodPost, r.Method)
 		assert.Equal(t, "/ATServicesRest/V1.0/Companies/query", r.URL.Path)
 
-		var requestBody map[string]interface{}
+		var requestBody QueryParams
 		err := json.NewDecoder(r.Body).Decode(&requestBody)
 		require.NoError(t, err)
 
-		filter := requestBody["Filter"].(map[string]interface{})
-		assert.Equal(t, "gt", filter["Op"])
-		assert.Equal(t, "id", filter["Field"])
-		assert.Equal(t, float64(0), filter["Value"])
-		assert.Equal(t, false, filter["UDF"])
-		assert.Empty(t, filter["Items"])
+		assert.Equal(t, 500, requestBody.MaxRecords)
+		require.Len(t, requestBody.Filter, 1)
+		require.Len(t, requestBody.Filter[0].Items, 1)
+		item := requestBody.Filter[0].Items[0]
+		assert.Equal(t, "gt", item.Op)
+		assert.Equal(t, "id", item.Field)
+		assert.Equal(t, float64(0), item.Value)
 
 		response := map[string]interface{}{
 			"items": []map[string]interface{}{
@@ -378,20 +361,20 @@ func TestQueryWithDateFilter(t *testing.T) {
 		assert.Equal(t, http.MethodPost, r.Method)
 		assert.Equal(t, "/ATServicesRest/V1.0/Companies/query", r.URL.Path)
 
-		var requestBody map[string]interface{}
+		var requestBody QueryParams
 		err := json.NewDecoder(r.Body).Decode(&requestBody)
 		require.NoError(t, err)
 
-		filter := requestBody["Filter"].(map[string]interface{})
-		assert.Equal(t, "gt", filter["Op"])
-		assert.Equal(t, "lastActivityDate", filter["Field"])
-		assert.NotEmpty(t, filter["Value"])
-		assert.Equal(t, false, filter["UDF"])
-		assert.Empty(t, filter["Items"])
+		require.Len(t, requestBody.Filter, 1)
+		require.Len(t, requestBody.Filter[0].Items, 1)
+		item := requestBody.Filter[0].Items[0]
+		assert.Equal(t, "gt", item.Op)
+		assert.Equal(t, "lastActivityDate", item.Field)
+		assert.Equal(t, "2023-01-01T05:00:00.000Z", item.Value, "date should be sent in UTC")
 
 		response := map[string]interface{}{
 			"items": []map[string]interface{}{
-				{"id": 1, "name": "Company 1", "lastActivityDate": "2023-01-01T00:00:00Z"},
+				{"id": 1, "name": "Company 1", "lastActivityDate": "2023-01-01T06:00:00Z"},
 				{"id": 2, "name": "Company 2", "lastActivityDate": "2023-01-02T00:00:00Z"},
 			},
 		}
@@ -401,7 +384,7 @@ func TestQueryWithDateFilter(t *testing.T) {
 
 	client := newTestClient(server.URL)
 	ctx := context.Background()
-	date := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	date := time.Date(2023, 1, 1, 0, 0, 0, 0, time.FixedZone("EST", -5*60*60))
 	result, err := client.QueryWithDateFilter(ctx, "Companies", "lastActivityDate", date)
 	require.NoError(t, err)
 	assert.Len(t, result, 2)
@@ -0,0 +1,404 @@
+package autotask
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultGetBatchSize is the most IDs a batched Get query asks for
+const DefaultGetBatchSize = 200
+
+// flightGroup tracks the reads in flight so identical ones can share a response
+type flightGroup struct {
+	mu      sync.Mutex
+	enabled bool
+	calls   map[string]*flight
+}
+
+// flight is a read in progress and, once done, its outcome
+type flight struct {
+	ctx  context.Context
+	done chan struct{}
+
+	statusCode int
+	status     string
+	header     http.Header
+	body       []byte
+	err        error
+}
+
+// SetCoalescing turns request coalescing on or off. While it is on,
+// identical Gets and queries made at the same time send one request and
+// share its response.
+func (c *client) SetCoalescing(enabled bool) {
+	c.flights.mu.Lock()
+	defer c.flights.mu.Unlock()
+	c.flights.enabled = enabled
+}
+
+// coalescingEnabled reports whether identical reads share a request
+func (c *client) coalescingEnabled() bool {
+	c.flights.mu.Lock()
+	defer c.flights.mu.Unlock()
+	return c.flights.enabled
+}
+
+// coalesced wraps rt so identical reads in flight at the same time share one request
+func (c *client) coalesced(rt RoundTrip) RoundTrip {
+	return func(req *http.Request) (*http.Response, error) {
+		key, ok := coalesceKey(req, c.cachePath(req))
+		if !ok {
+			return rt(req)
+		}
+
+		c.flights.mu.Lock()
+		if f, ok := c.flights.calls[key]; ok {
+			c.flights.mu.Unlock()
+			return f.wait(req, rt)
+		}
+		f := &flight{ctx: req.Context(), done: make(chan struct{})}
+		if c.flights.calls == nil {
+			c.flights.calls = make(map[string]*flight)
+		}
+		c.flights.calls[key] = f
+		c.flights.mu.Unlock()
+
+		resp, err := rt(req)
+		if err == nil {
+			f.statusCode, f.status, f.header = resp.StatusCode, resp.Status, resp.Header.Clone()
+			f.body, err = io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			if err != nil {
+				err = fmt.Errorf("failed to read response body: %w", err)
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(f.body))
+		}
+		f.err = err
+
+		c.flights.mu.Lock()
+		delete(c.flights.calls, key)
+		c.flights.mu.Unlock()
+		close(f.done)
+
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+// wait returns the shared response of a flight. If the request that was
+// sent failed because its caller gave up, req is sent on its own.
+func (f *flight) wait(req *http.Request, rt RoundTrip) (*http.Response, error) {
+	select {
+	case <-f.done:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	if f.err != nil {
+		if f.ctx.Err() != nil {
+			return rt(req)
+		}
+		return nil, f.err
+	}
+	return &http.Response{
+		Status:        f.status,
+		StatusCode:    f.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        f.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(f.body)),
+		ContentLength: int64(len(f.body)),
+		Request:       req,
+	}, nil
+}
+
+// coalesceKey returns the key shared by identical reads: the cache key for
+// Gets and queries, and the URL for other GET requests
+func coalesceKey(req *http.Request, segments []string) (string, bool) {
+	if len(segments) == 0 || (isWriteMethod(req.Method) && !isQueryRequest(req)) {
+		return "", false
+	}
+	if key, ok, err := cacheKey(req, segments); err == nil && ok {
+		return req.Method + " " + key, true
+	}
+	if req.Method == http.MethodGet {
+		return req.Method + " " + req.URL.String(), true
+	}
+	return "", false
+}
+
+// getBatchKey is the context key for Gets that must not be batched
+type getBatchKey struct{}
+
+// withoutGetBatching returns a copy of ctx whose Gets are sent on their own
+func withoutGetBatching(ctx context.Context) context.Context {
+	return context.WithValue(ctx, getBatchKey{}, true)
+}
+
+// getBatchingDisabled reports whether Gets made with ctx are sent on their own
+func getBatchingDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(getBatchKey{}).(bool)
+	return disabled
+}
+
+// requestScoped reports whether ctx carries state that applies only to the
+// requests made with it: WithHeader headers, impersonation, dry-run, a
+// request ID or log attributes. Such Gets can't share a request with others.
+func requestScoped(ctx context.Context) bool {
+	_, impersonated := ImpersonationFromContext(ctx)
+	_, dryRun := dryRunFromContext(ctx)
+	return impersonated || dryRun ||
+		len(headersFromContext(ctx)) > 0 ||
+		RequestIDFromContext(ctx) != "" ||
+		len(logAttrsFromContext(ctx)) > 0
+}
+
+// getBatcher is implemented by clients that can combine concurrent Gets
+type getBatcher interface {
+	batchGet(ctx context.Context, entityName string, id int64) (item interface{}, batched bool, err error)
+}
+
+// itemCache is implemented by clients whose response cache can serve and
+// store the entities fetched by batched Gets
+type itemCache interface {
+	// cachedItems adds the cached entities among ids to found and returns
+	// the IDs that weren't cached, with the cache generation they were read at
+	cachedItems(ctx context.Context, entityName string, ids []int64, found map[int64]json.RawMessage) (missing []int64, generation uint64)
+
+	// storeItems caches fetched entities unless the cache was invalidated
+	// since generation
+	storeItems(entityName string, items map[int64]json.RawMessage, generation uint64)
+}
+
+// GetBatcher combines Gets for the same entity made within a window of each
+// other into a single "id in (...)" query. A batch of one ID is sent as a
+// plain Get. Batches of several IDs are served from the client's response
+// cache where possible and fill it with what they fetch.
+//
+// Gets whose context carries per-request state, such as WithHeader headers,
+// impersonation, dry-run, a request ID or log attributes, are sent on their
+// own so that state is neither lost nor applied to other callers' Gets.
+type GetBatcher struct {
+	client Client
+
+	mu      sync.Mutex
+	window  time.Duration
+	size    int
+	pending map[string]*getBatch
+}
+
+// getBatch collects the IDs of concurrent Gets for one entity
+type getBatch struct {
+	entityName string
+	ctx        context.Context
+	waiters    map[int64][]chan getResult
+	timer      *time.Timer
+}
+
+// getResult is the outcome of one batched Get
+type getResult struct {
+	data json.RawMessage
+	err  error
+}
+
+// NewGetBatcher returns a batcher that fetches entities with c. A Get waits
+// up to window before it is sent. A zero window sends every Get on its own,
+// and a maxSize below one uses DefaultGetBatchSize.
+func NewGetBatcher(c Client, window time.Duration, maxSize int) *GetBatcher {
+	b := &GetBatcher{client: c}
+	b.SetWindow(window)
+	b.SetMaxSize(maxSize)
+	return b
+}
+
+// SetWindow sets how long a Get waits to be batched with others
+func (b *GetBatcher) SetWindow(window time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.window = window
+}
+
+// SetMaxSize sets the most IDs fetched by one query. A maxSize below one
+// uses DefaultGetBatchSize.
+func (b *GetBatcher) SetMaxSize(maxSize int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if maxSize < 1 {
+		maxSize = DefaultGetBatchSize
+	}
+	b.size = maxSize
+}
+
+// Get returns the body of an entity, or nil when it doesn't exist
+func (b *GetBatcher) Get(ctx context.Context, entityName string, id int64) (json.RawMessage, error) {
+	if data, batched, err := b.get(ctx, entityName, id); batched {
+		return data, err
+	}
+	return b.getOne(ctx, entityName, id)
+}
+
+// get adds a Get to the entity's pending batch and waits for its result. It
+// reports batched as false when the Get must be sent on its own.
+func (b *GetBatcher) get(ctx context.Context, entityName string, id int64) (json.RawMessage, bool, error) {
+	if getBatchingDisabled(ctx) || requestScoped(ctx) {
+		return nil, false, nil
+	}
+
+	// Gets that skip the cache are batched apart from those that don't
+	key := strings.ToLower(entityName)
+	if cacheBypassed(ctx) {
+		key += " uncached"
+	}
+	result := make(chan getResult, 1)
+
+	b.mu.Lock()
+	if b.window <= 0 {
+		b.mu.Unlock()
+		return nil, false, nil
+	}
+	batch, ok := b.pending[key]
+	if !ok {
+		batch = &getBatch{
+			entityName: entityName,
+			ctx:        context.WithoutCancel(ctx),
+			waiters:    make(map[int64][]chan getResult),
+		}
+		if b.pending == nil {
+			b.pending = make(map[string]*getBatch)
+		}
+		b.pending[key] = batch
+		batch.timer = time.AfterFunc(b.window, func() { b.flush(key, batch) })
+	}
+	batch.waiters[id] = append(batch.waiters[id], result)
+	if len(batch.waiters) >= b.size && batch.timer.Stop() {
+		delete(b.pending, key)
+		go b.flush(key, batch)
+	}
+	b.mu.Unlock()
+
+	select {
+	case r := <-result:
+		return r.data, true, r.err
+	case <-ctx.Done():
+		return nil, true, ctx.Err()
+	}
+}
+
+// flush fetches a batch of IDs and hands each waiter its entity
+func (b *GetBatcher) flush(key string, batch *getBatch) {
+	b.mu.Lock()
+	if b.pending[key] == batch {
+		delete(b.pending, key)
+	}
+	size := b.size
+	b.mu.Unlock()
+
+	ids := make([]int64, 0, len(batch.waiters))
+	for id := range batch.waiters {
+		ids = append(ids, id)
+	}
+
+	var found map[int64]json.RawMessage
+	var err error
+	if len(ids) == 1 {
+		var data json.RawMessage
+		if data, err = b.getOne(batch.ctx, batch.entityName, ids[0]); data != nil {
+			found = map[int64]json.RawMessage{ids[0]: data}
+		}
+	} else {
+		found, err = b.query(batch.ctx, batch.entityName, ids, size)
+	}
+
+	// Each waiter gets its own copy so callers can't see each other's changes
+	for id, waiters := range batch.waiters {
+		for _, waiter := range waiters {
+			r := getResult{err: err}
+			if data, ok := found[id]; ok && err == nil {
+				r.data = append(json.RawMessage(nil), data...)
+			}
+			waiter <- r
+		}
+	}
+}
+
+// getOne fetches one entity with a plain Get
+func (b *GetBatcher) getOne(ctx context.Context, entityName string, id int64) (json.RawMessage, error) {
+	service := NewBaseEntityService(b.client, entityName)
+	item, err := service.Get(withoutGetBatching(ctx), id)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	return json.Marshal(item)
+}
+
+// query fetches several entities with "in" queries, using the client's
+// cached Gets for the IDs it has and caching the entities it fetches
+func (b *GetBatcher) query(ctx context.Context, entityName string, ids []int64, size int) (map[int64]json.RawMessage, error) {
+	found := make(map[int64]json.RawMessage, len(ids))
+	cache, _ := b.client.(itemCache)
+	var generation uint64
+	if cache != nil {
+		ids, generation = cache.cachedItems(ctx, entityName, ids, found)
+	}
+	if len(ids) == 0 {
+		return found, nil
+	}
+
+	items, err := QueryByIDs[json.RawMessage](ctx, b.client, entityName, ids, size)
+	if err != nil {
+		return nil, err
+	}
+	fetched := make(map[int64]json.RawMessage, len(items))
+	for _, data := range items {
+		var ref struct {
+			ID int64 `json:"id"`
+		}
+		if json.Unmarshal(data, &ref) == nil {
+			found[ref.ID] = data
+			fetched[ref.ID] = data
+		}
+	}
+	if cache != nil {
+		cache.storeItems(entityName, fetched, generation)
+	}
+	return found, nil
+}
+
+// SetGetBatching combines Gets for the same entity made within window of
+// each other into a single "id in (...)" query of up to maxSize IDs. A Get
+// waits up to window before it is sent. A zero window turns batching off,
+// and a maxSize below one uses DefaultGetBatchSize. See GetBatcher for the
+// Gets that are always sent on their own.
+func (c *client) SetGetBatching(window time.Duration, maxSize int) {
+	c.getBatches.mu.Lock()
+	c.getBatches.client = c
+	c.getBatches.mu.Unlock()
+
+	c.getBatches.SetWindow(window)
+	c.getBatches.SetMaxSize(maxSize)
+}
+
+// batchGet adds a Get to the entity's pending batch and waits for its
+// result. It reports batched as false when the Get isn't batched.
+func (c *client) batchGet(ctx context.Context, entityName string, id int64) (interface{}, bool, error) {
+	data, batched, err := c.getBatches.get(ctx, entityName, id)
+	if !batched || err != nil || data == nil {
+		return nil, batched, err
+	}
+
+	var item interface{}
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, true, fmt.Errorf("failed to decode %s %d: %w", entityName, id, err)
+	}
+	return item, true, nil
+}
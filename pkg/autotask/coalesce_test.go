@@ -0,0 +1,196 @@
+package autotask
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalescing(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	var sent atomic.Int32
+	release := make(chan struct{})
+	server.AddHandler("/Companies/", func(w http.ResponseWriter, r *http.Request) {
+		sent.Add(1)
+		<-release
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 1, "companyName": "Acme"}})
+	})
+
+	client := server.NewTestClient()
+	client.SetCoalescing(true)
+	ctx := context.Background()
+
+	const callers = 10
+	var wg sync.WaitGroup
+	items := make([]interface{}, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			items[i], errs[i] = client.Companies().Get(ctx, 1)
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	AssertEqual(t, int32(1), sent.Load(), "identical gets should share one request")
+	for i := 0; i < callers; i++ {
+		AssertNil(t, errs[i], "error should be nil")
+		AssertEqual(t, "Acme", items[i].(map[string]interface{})["companyName"], "every caller should get the response")
+	}
+}
+
+func TestGetBatching(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	var queries, gets atomic.Int32
+	server.AddHandler("/Companies/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Companies/query" {
+			gets.Add(1)
+			server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 9}})
+			return
+		}
+		queries.Add(1)
+		var search EntityQueryParams
+		if err := json.Unmarshal([]byte(r.URL.Query().Get("search")), &search); err != nil {
+			t.Errorf("invalid search: %v", err)
+		}
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"items": []map[string]interface{}{
+			{"id": 1, "companyName": "One"},
+			{"id": 2, "companyName": "Two"},
+		}})
+	})
+
+	client := server.NewTestClient()
+	client.SetGetBatching(20*time.Millisecond, 0)
+	ctx := context.Background()
+
+	ids := []int64{1, 2, 2, 3}
+	var wg sync.WaitGroup
+	items := make([]interface{}, len(ids))
+	errs := make([]error, len(ids))
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id int64) {
+			defer wg.Done()
+			items[i], errs[i] = client.Companies().Get(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	AssertEqual(t, int32(1), queries.Load(), "concurrent gets should be sent as one query")
+	AssertEqual(t, int32(0), gets.Load(), "no plain gets should be sent")
+	for i := range ids {
+		AssertNil(t, errs[i], "error should be nil")
+	}
+	AssertEqual(t, "One", items[0].(map[string]interface{})["companyName"], "each caller should get its entity")
+	AssertEqual(t, "Two", items[2].(map[string]interface{})["companyName"], "duplicate IDs should share the fetch")
+	AssertNil(t, items[3], "missing entity should be nil")
+
+	item, err := client.Companies().Get(ctx, 9)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, float64(9), item.(map[string]interface{})["id"], "lone get should be returned")
+	AssertEqual(t, int32(1), gets.Load(), "lone get should be sent as a plain get")
+}
+
+func TestGetBatchingRequestScoped(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	var queries, gets atomic.Int32
+	server.AddHandler("/Companies/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/Companies/query" {
+			queries.Add(1)
+			server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"items": []interface{}{}})
+			return
+		}
+		gets.Add(1)
+		AssertEqual(t, "yes", r.Header.Get("X-Trace"), "per-request header should be sent")
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 1}})
+	})
+
+	client := server.NewTestClient()
+	client.SetGetBatching(20*time.Millisecond, 0)
+	ctx := WithHeader(context.Background(), "X-Trace", "yes")
+
+	var wg sync.WaitGroup
+	for _, id := range []int64{1, 2} {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			_, err := client.Companies().Get(ctx, id)
+			AssertNil(t, err, "error should be nil")
+		}(id)
+	}
+	wg.Wait()
+
+	AssertEqual(t, int32(0), queries.Load(), "gets with per-request state should not be batched")
+	AssertEqual(t, int32(2), gets.Load(), "each get should be sent on its own")
+}
+
+func TestGetBatchingCache(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	var gets atomic.Int32
+	var searches []string
+	var mu sync.Mutex
+	server.AddHandler("/Companies/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Companies/query" {
+			gets.Add(1)
+			server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 1, "companyName": "One"}})
+			return
+		}
+		mu.Lock()
+		searches = append(searches, r.URL.Query().Get("search"))
+		mu.Unlock()
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"items": []map[string]interface{}{
+			{"id": 2, "companyName": "Two"},
+			{"id": 3, "companyName": "Three"},
+		}})
+	})
+
+	client := server.NewTestClient()
+	client.SetCache(DefaultCacheConfig())
+	client.SetGetBatching(20*time.Millisecond, 0)
+	ctx := context.Background()
+
+	// Company 1 is cached by a plain get
+	_, err := client.Companies().Get(ctx, 1)
+	AssertNil(t, err, "error should be nil")
+
+	var wg sync.WaitGroup
+	items := make([]interface{}, 3)
+	for i, id := range []int64{1, 2, 3} {
+		wg.Add(1)
+		go func(i int, id int64) {
+			defer wg.Done()
+			items[i], _ = client.Companies().Get(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	AssertEqual(t, 1, len(searches), "batch should send one query")
+	var search struct {
+		Filter []QueryFilter `json:"filter"`
+	}
+	AssertNil(t, json.Unmarshal([]byte(searches[0]), &search), "search should be valid")
+	AssertEqual(t, 2, len(search.Filter[0].Value.([]interface{})), "cached ID should not be queried")
+	AssertEqual(t, "One", items[0].(map[string]interface{})["companyName"], "cached entity should be returned")
+
+	// Entities fetched by the batch are cached for plain gets
+	client.SetGetBatching(0, 0)
+	item, err := client.Companies().Get(ctx, 3)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, "Three", item.(map[string]interface{})["companyName"], "batched entity should be cached")
+	AssertEqual(t, int32(1), gets.Load(), "cached entities should not be fetched again")
+}
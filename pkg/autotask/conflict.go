@@ -0,0 +1,155 @@
+package autotask
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// modificationDateFields are compared by UpdateIfUnchanged when no fields
+// are named, in order of preference
+var modificationDateFields = []string{"lastActivityDate", "lastModifiedDate", "lastModifiedDateTime", "lastActivityDateTime"}
+
+// FieldConflict is a field that changed since an entity was read
+type FieldConflict struct {
+	Field    string
+	Expected interface{}
+	Actual   interface{}
+}
+
+// ConflictError is returned by UpdateIfUnchanged when the entity changed
+// since the expected snapshot was read
+type ConflictError struct {
+	Entity string
+	ID     int64
+	Fields []FieldConflict
+}
+
+// Error lists the fields that changed
+func (e *ConflictError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = fmt.Sprintf("%s expected %v, got %v", f.Field, formatConflictValue(f.Expected), formatConflictValue(f.Actual))
+	}
+	return fmt.Sprintf("%s %d changed since it was read: %s", e.Entity, e.ID, strings.Join(parts, "; "))
+}
+
+// formatConflictValue formats a field value for a ConflictError
+func formatConflictValue(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprint(v)
+}
+
+// UpdateIfUnchanged applies patch to the entity with the given ID only if
+// the named fields still hold the values in expected, the snapshot the
+// change was based on. With no fields named, the entity's last activity or
+// modification date is compared. The entity is read again just before the
+// patch is sent; the API has no conditional update, so this narrows the
+// window for lost updates rather than closing it. When a field changed
+// nothing is sent and a *ConflictError lists the differences.
+func UpdateIfUnchanged[T any](ctx context.Context, service EntityService, id int64, expected T, patch *Patch[T], fields ...string) error {
+	if len(fields) == 0 {
+		name, ok := modificationDateField[T]()
+		if !ok {
+			return fmt.Errorf("%s has no modification date field; name the fields to compare", service.GetEntityName())
+		}
+		fields = []string{name}
+	}
+	fields, err := resolveFields[T](fields)
+	if err != nil {
+		return err
+	}
+
+	item, err := service.Get(WithoutCache(ctx), id)
+	if err != nil {
+		return fmt.Errorf("failed to read %s %d: %w", service.GetEntityName(), id, err)
+	}
+	if item == nil {
+		return fmt.Errorf("%s %d not found", service.GetEntityName(), id)
+	}
+	var current T
+	if err := decodeItem(item, &current); err != nil {
+		return fmt.Errorf("failed to decode %s %d: %w", service.GetEntityName(), id, err)
+	}
+
+	conflicts, err := compareFields(expected, current, fields)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return &ConflictError{Entity: service.GetEntityName(), ID: id, Fields: conflicts}
+	}
+
+	return ApplyPatch(ctx, service, id, patch)
+}
+
+// modificationDateField returns the name of T's last activity or modification date field
+func modificationDateField[T any]() (string, bool) {
+	fields := patchFieldsOf(reflect.TypeOf((*T)(nil)).Elem())
+	for _, name := range modificationDateFields {
+		if field, ok := fields[strings.ToLower(name)]; ok {
+			return field.name, true
+		}
+	}
+	return "", false
+}
+
+// resolveFields returns the JSON names of the named fields of T, matched
+// case-insensitively, and an error for names T doesn't have. Names are
+// returned as given when T isn't a struct.
+func resolveFields[T any](names []string) ([]string, error) {
+	fields := patchFieldsOf(reflect.TypeOf((*T)(nil)).Elem())
+	if fields == nil {
+		return names, nil
+	}
+
+	resolved := make([]string, len(names))
+	for i, name := range names {
+		field, ok := fields[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %s", name)
+		}
+		resolved[i] = field.name
+	}
+	return resolved, nil
+}
+
+// compareFields returns the named fields whose JSON values differ between
+// expected and actual
+func compareFields(expected, actual interface{}, fields []string) ([]FieldConflict, error) {
+	before, err := toFieldMap(expected)
+	if err != nil {
+		return nil, err
+	}
+	after, err := toFieldMap(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []FieldConflict
+	for _, name := range fields {
+		a := lookupFieldValue(before, name)
+		b := lookupFieldValue(after, name)
+		if !jsonEqual(a, b) {
+			conflicts = append(conflicts, FieldConflict{Field: name, Expected: a, Actual: b})
+		}
+	}
+	return conflicts, nil
+}
+
+// jsonEqual reports whether two decoded JSON values are equal
+func jsonEqual(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	x, errA := json.Marshal(a)
+	y, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(x) == string(y)
+}
@@ -0,0 +1,64 @@
+package autotask
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUpdateIfUnchanged(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	current := map[string]interface{}{"id": 10, "title": "Printer", "status": 1, "lastActivityDate": "2025-03-01T10:00:00Z"}
+	patches := 0
+	server.AddHandler("/Tickets/10", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			patches++
+			server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"itemId": 10})
+			return
+		}
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: current})
+	})
+
+	client := server.NewTestClient()
+	ctx := context.Background()
+
+	expected := Ticket{ID: 10, Title: "Printer", Status: 1, LastActivityDate: testDateTime("2025-03-01T10:00:00.000Z")}
+	patch := NewPatch[Ticket]().Set("status", 5)
+
+	err := UpdateIfUnchanged(ctx, client.Tickets(), 10, expected, patch)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 1, patches, "patch should be sent when nothing changed")
+
+	current["lastActivityDate"] = "2025-03-01T11:00:00Z"
+	current["status"] = 8
+
+	err = UpdateIfUnchanged(ctx, client.Tickets(), 10, expected, patch)
+	var conflict *ConflictError
+	AssertTrue(t, errors.As(err, &conflict), "error should be a ConflictError")
+	AssertEqual(t, 1, len(conflict.Fields), "only the compared field should be reported")
+	AssertEqual(t, "lastActivityDate", conflict.Fields[0].Field, "modification date should be compared by default")
+	AssertTrue(t, strings.Contains(err.Error(), "2025-03-01T11:00:00.000Z"), "error should show the current value")
+	AssertEqual(t, 1, patches, "patch should not be sent on conflict")
+
+	err = UpdateIfUnchanged(ctx, client.Tickets(), 10, expected, patch, "title")
+	AssertNil(t, err, "unchanged named fields should allow the patch")
+	AssertEqual(t, 2, patches, "patch should be sent")
+
+	err = UpdateIfUnchanged(ctx, client.Tickets(), 10, expected, patch, "title", "status")
+	AssertTrue(t, errors.As(err, &conflict), "changed named field should conflict")
+	AssertEqual(t, "status", conflict.Fields[0].Field, "changed field should be reported")
+	AssertEqual(t, float64(1), conflict.Fields[0].Expected, "expected value should be reported")
+	AssertEqual(t, float64(8), conflict.Fields[0].Actual, "actual value should be reported")
+
+	err = UpdateIfUnchanged(ctx, client.Tickets(), 10, expected, patch, "Status")
+	AssertTrue(t, errors.As(err, &conflict), "field names should match regardless of case")
+	AssertEqual(t, "status", conflict.Fields[0].Field, "field should be reported by its JSON name")
+
+	err = UpdateIfUnchanged(ctx, client.Tickets(), 10, expected, patch, "titel")
+	AssertTrue(t, err != nil && strings.Contains(err.Error(), "unknown field titel"), "unknown field should be an error")
+	AssertEqual(t, 2, patches, "patch should not be sent for an unknown field")
+}
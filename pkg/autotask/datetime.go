@@ -0,0 +1,184 @@
+package autotask
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Formats sent to the API for date-times and dates
+const (
+	DateTimeLayout = "2006-01-02T15:04:05.000Z"
+	DateLayout     = "2006-01-02"
+)
+
+// dateTimeLayouts are the formats the API emits for date-times. Values
+// without a zone are UTC.
+var dateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	DateLayout,
+}
+
+// DateTime is an Autotask date-time. The API stores date-times in UTC;
+// parsed values are always UTC and are sent as DateTimeLayout. Use In with
+// the client's TimeZone to show them in the tenant's time zone.
+type DateTime struct {
+	time.Time
+}
+
+// NewDateTime returns t as a DateTime in UTC
+func NewDateTime(t time.Time) DateTime {
+	return DateTime{Time: t.UTC()}
+}
+
+// ParseDateTime parses a date-time in any format the API emits
+func ParseDateTime(value string) (DateTime, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return NewDateTime(t), nil
+		}
+	}
+	return DateTime{}, fmt.Errorf("invalid date-time %q", value)
+}
+
+// DateIn returns the calendar date of d in loc
+func (d DateTime) DateIn(loc *time.Location) Date {
+	return DateOf(d.In(loc))
+}
+
+// String formats d as DateTimeLayout, or "" for the zero DateTime
+func (d DateTime) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.UTC().Format(DateTimeLayout)
+}
+
+// MarshalJSON encodes d as DateTimeLayout, or null for the zero DateTime
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a date-time in any format the API emits. Null and
+// "" decode to the zero DateTime.
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalDateString(data)
+	if err != nil || value == "" {
+		*d = DateTime{}
+		return err
+	}
+	parsed, err := ParseDateTime(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Date is an Autotask date without a time of day, such as a time entry's
+// dateWorked. It is a calendar date in the tenant's time zone.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewDate returns the given date
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOf returns the calendar date of t in its own location
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// ParseDate parses a date in any format the API emits. The time of day, if
+// any, is ignored.
+func ParseDate(value string) (Date, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return DateOf(t), nil
+		}
+	}
+	return Date{}, fmt.Errorf("invalid date %q", value)
+}
+
+// In returns midnight at the start of d in loc
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// AddDays returns d moved by the given number of days
+func (d Date) AddDays(days int) Date {
+	return DateOf(d.In(time.UTC).AddDate(0, 0, days))
+}
+
+// Before reports whether d is before other
+func (d Date) Before(other Date) bool {
+	return d.In(time.UTC).Before(other.In(time.UTC))
+}
+
+// After reports whether d is after other
+func (d Date) After(other Date) bool {
+	return d.In(time.UTC).After(other.In(time.UTC))
+}
+
+// IsZero reports whether d is the zero Date
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// String formats d as DateLayout, or "" for the zero Date
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.In(time.UTC).Format(DateLayout)
+}
+
+// MarshalJSON encodes d as DateLayout, or null for the zero Date
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a date in any format the API emits. Null and ""
+// decode to the zero Date.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalDateString(data)
+	if err != nil || value == "" {
+		*d = Date{}
+		return err
+	}
+	parsed, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// unmarshalDateString decodes a JSON string or null
+func unmarshalDateString(data []byte) (string, error) {
+	if string(data) == "null" {
+		return "", nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "", fmt.Errorf("date must be a string: %w", err)
+	}
+	return value, nil
+}
@@ -0,0 +1,99 @@
+package autotask
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseDateTime(t *testing.T) {
+	want := time.Date(2025, 3, 1, 10, 15, 2, 0, time.UTC)
+
+	for _, value := range []string{
+		"2025-03-01T10:15:02Z",
+		"2025-03-01T10:15:02.000Z",
+		"2025-03-01T10:15:02.0000000Z",
+		"2025-03-01T10:15:02",
+		"2025-03-01T05:15:02-05:00",
+		"2025-03-01 10:15:02",
+	} {
+		dt, err := ParseDateTime(value)
+		AssertNil(t, err, "error should be nil for "+value)
+		AssertTrue(t, dt.Equal(want), "date-time should match for "+value)
+		AssertEqual(t, time.UTC, dt.Location(), "date-time should be UTC for "+value)
+	}
+
+	dt, err := ParseDateTime("2025-03-01")
+	AssertNil(t, err, "date-only value should parse")
+	AssertEqual(t, "2025-03-01T00:00:00.000Z", dt.String(), "date-only value should be midnight UTC")
+
+	_, err = ParseDateTime("yesterday")
+	AssertNotNil(t, err, "invalid date-time should be rejected")
+}
+
+func TestDateTimeJSON(t *testing.T) {
+	var entry TimeEntry
+	err := json.Unmarshal([]byte(`{"dateWorked":"2025-03-01T00:00:00","startDateTime":"2025-03-01T09:00:00.1234567Z","endDateTime":null}`), &entry)
+	AssertNil(t, err, "error should be nil")
+
+	AssertEqual(t, NewDate(2025, time.March, 1), entry.DateWorked.ValueOr(Date{}), "date should be parsed")
+	start, ok := entry.StartDateTime.Get()
+	AssertTrue(t, ok, "start should be set")
+	AssertEqual(t, 9, start.Hour(), "start should be parsed")
+	AssertTrue(t, entry.EndDateTime.IsNull(), "null should be kept")
+
+	local := NewDateTime(time.Date(2025, 3, 1, 4, 0, 0, 0, time.FixedZone("EST", -5*60*60)))
+	data, err := json.Marshal(TimeEntry{
+		DateWorked:    NullableOf(NewDate(2025, time.March, 1)),
+		StartDateTime: NullableOf(local),
+	})
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, `{"dateWorked":"2025-03-01","startDateTime":"2025-03-01T09:00:00.000Z"}`, string(data), "dates should be sent in the API format and UTC")
+
+	var zero DateTime
+	AssertNil(t, json.Unmarshal([]byte(`""`), &zero), "empty string should decode")
+	AssertTrue(t, zero.IsZero(), "empty string should be the zero date-time")
+}
+
+func TestDateTimeTimeZone(t *testing.T) {
+	client := NewClient("user", "secret", "code")
+	AssertEqual(t, time.UTC, client.TimeZone(), "time zone should default to UTC")
+
+	tenant := time.FixedZone("AEDT", 11*60*60)
+	client.SetTimeZone(tenant)
+
+	dt, err := ParseDateTime("2025-03-01T20:00:00Z")
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 7, dt.In(client.TimeZone()).Hour(), "date-time should convert to the tenant's time zone")
+	AssertEqual(t, NewDate(2025, time.March, 2), dt.DateIn(client.TimeZone()), "date should be the tenant's calendar date")
+
+	day := NewDate(2025, time.March, 2)
+	AssertEqual(t, "2025-03-01T13:00:00.000Z", NewDateTime(day.In(tenant)).String(), "tenant midnight should convert to UTC")
+	AssertEqual(t, NewDate(2025, time.March, 1), day.AddDays(-1), "days should be added")
+	AssertTrue(t, day.After(day.AddDays(-1)), "dates should compare")
+}
+
+func TestSetTimeZoneConcurrent(t *testing.T) {
+	client := NewClient("user", "secret", "code")
+	tenant := time.FixedZone("AEDT", 11*60*60)
+
+	// Setting the time zone while it is read must not race
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			AssertNotNil(t, client.TimeZone(), "time zone should never be nil")
+		}()
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				client.SetTimeZone(tenant)
+			} else {
+				client.SetTimeZone(nil)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
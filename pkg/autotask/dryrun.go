@@ -0,0 +1,260 @@
+package autotask
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dryRunKey is the context key for the per-request dry-run setting
+type dryRunKey struct{}
+
+// WithDryRun returns a copy of ctx that turns dry-run mode on or off for
+// requests made with it, overriding the client setting. In dry-run mode
+// creates, updates and deletes are validated and recorded instead of sent.
+func WithDryRun(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, enabled)
+}
+
+// dryRunFromContext returns the dry-run setting carried by ctx, if any
+func dryRunFromContext(ctx context.Context) (enabled, ok bool) {
+	if ctx == nil {
+		return false, false
+	}
+	enabled, ok = ctx.Value(dryRunKey{}).(bool)
+	return enabled, ok
+}
+
+// PlannedMutation is a write that dry-run mode recorded instead of sending
+type PlannedMutation struct {
+	// Entity and Operation name the service call, such as Tickets and update
+	Entity    string
+	Operation string
+
+	// Method, URL, Headers and Body are the request that would have been
+	// sent. Credential headers are redacted.
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    json.RawMessage
+
+	// ID is the target entity for updates and deletes. Creates get a
+	// synthetic negative ID, which is also returned in the response.
+	ID int64
+
+	// Err is set when the write failed validation and would be rejected
+	Err error
+
+	Time time.Time
+}
+
+// DryRunReport lists the writes recorded in dry-run mode, in the order they were made
+type DryRunReport struct {
+	Mutations []PlannedMutation
+}
+
+// Counts returns the number of planned writes by entity and operation,
+// keyed like "Tickets update"
+func (r DryRunReport) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, m := range r.Mutations {
+		counts[m.Entity+" "+m.Operation]++
+	}
+	return counts
+}
+
+// Failed returns the planned writes that failed validation
+func (r DryRunReport) Failed() []PlannedMutation {
+	var failed []PlannedMutation
+	for _, m := range r.Mutations {
+		if m.Err != nil {
+			failed = append(failed, m)
+		}
+	}
+	return failed
+}
+
+// String summarizes the report with one line per entity and operation,
+// followed by each validation failure
+func (r DryRunReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d planned writes", len(r.Mutations))
+
+	counts := r.Counts()
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\n  %s: %d", key, counts[key])
+	}
+
+	for _, m := range r.Failed() {
+		fmt.Fprintf(&b, "\n  invalid %s %s %d: %v", m.Entity, m.Operation, m.ID, m.Err)
+	}
+	return b.String()
+}
+
+// dryRunState holds the client's dry-run setting and recorded writes
+type dryRunState struct {
+	mu        sync.Mutex
+	enabled   bool
+	mutations []PlannedMutation
+	nextID    int64
+}
+
+// SetDryRun turns dry-run mode on or off for every request that doesn't
+// override it with WithDryRun
+func (c *client) SetDryRun(enabled bool) {
+	c.dryRun.mu.Lock()
+	defer c.dryRun.mu.Unlock()
+	c.dryRun.enabled = enabled
+}
+
+// DryRunReport returns the writes recorded in dry-run mode so far
+func (c *client) DryRunReport() DryRunReport {
+	c.dryRun.mu.Lock()
+	defer c.dryRun.mu.Unlock()
+	return DryRunReport{Mutations: append([]PlannedMutation(nil), c.dryRun.mutations...)}
+}
+
+// ResetDryRunReport clears the recorded writes
+func (c *client) ResetDryRunReport() {
+	c.dryRun.mu.Lock()
+	defer c.dryRun.mu.Unlock()
+	c.dryRun.mutations = nil
+}
+
+// isDryRun reports whether a write made with ctx should be recorded instead of sent
+func (c *client) isDryRun(ctx context.Context) bool {
+	if enabled, ok := dryRunFromContext(ctx); ok {
+		return enabled
+	}
+	c.dryRun.mu.Lock()
+	defer c.dryRun.mu.Unlock()
+	return c.dryRun.enabled
+}
+
+// isQueryRequest reports whether req is a query sent with POST, which reads
+// rather than writes
+func isQueryRequest(req *http.Request) bool {
+	p := strings.ToLower(strings.TrimSuffix(req.URL.Path, "/"))
+	return strings.HasSuffix(p, "/query") || strings.HasSuffix(p, "/query/count")
+}
+
+// planMutation validates and records a write, and returns the response the
+// API would give for it without sending it
+func (c *client) planMutation(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	info := operationFromContext(ctx)
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, fmt.Errorf("failed to read dry-run request body: %w", err)
+		}
+	}
+	body = bytes.TrimSpace(body)
+
+	mutation := PlannedMutation{
+		Entity:    info.entity,
+		Operation: info.operation,
+		Method:    req.Method,
+		URL:       req.URL.String(),
+		Headers:   headersForLog(req.Header),
+		Time:      time.Now().UTC(),
+	}
+	if len(body) > 0 {
+		mutation.Body = json.RawMessage(body)
+	}
+
+	var values map[string]interface{}
+	if len(body) > 0 {
+		_ = json.Unmarshal(body, &values)
+	}
+
+	if req.Method == http.MethodPost {
+		c.dryRun.mu.Lock()
+		c.dryRun.nextID--
+		mutation.ID = c.dryRun.nextID
+		c.dryRun.mu.Unlock()
+	} else {
+		mutation.ID, _ = strconv.ParseInt(path.Base(req.URL.Path), 10, 64)
+	}
+
+	if values != nil && info.entity != "" {
+		mutation.Err = c.validateMutation(ctx, info.entity, values, req.Method == http.MethodPost)
+	}
+
+	c.dryRun.mu.Lock()
+	c.dryRun.mutations = append(c.dryRun.mutations, mutation)
+	c.dryRun.mu.Unlock()
+
+	logger := loggerFromContext(ctx)
+	if mutation.Err != nil {
+		logger.WarnContext(ctx, "Dry run: request would be rejected",
+			append(requestLogAttrs(ctx), "method", mutation.Method, "url", mutation.URL, "body", string(body), "error", mutation.Err)...)
+		return nil, mutation.Err
+	}
+	logger.InfoContext(ctx, "Dry run: request not sent",
+		append(requestLogAttrs(ctx), "method", mutation.Method, "url", mutation.URL, "headers", mutation.Headers, "body", string(body))...)
+
+	return dryRunResponse(req, mutation, values)
+}
+
+// validateMutation checks the fields of a planned create or update against
+// the entity metadata
+func (c *client) validateMutation(ctx context.Context, entityName string, values map[string]interface{}, create bool) error {
+	fields, err := c.GetFieldInfo(ctx, entityName)
+	if err != nil {
+		return fmt.Errorf("failed to validate dry-run %s: %w", entityName, err)
+	}
+	return validationErrorOrNil(entityName, validateFields(fields, values, create))
+}
+
+// dryRunResponse builds the response for a planned write: the item with its
+// ID for creates and updates, and no content for deletes
+func dryRunResponse(req *http.Request, mutation PlannedMutation, values map[string]interface{}) (*http.Response, error) {
+	resp := &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       http.NoBody,
+		Request:    req,
+	}
+
+	if req.Method == http.MethodDelete {
+		resp.Status = "204 No Content"
+		resp.StatusCode = http.StatusNoContent
+		return resp, nil
+	}
+
+	item := make(map[string]interface{}, len(values)+1)
+	for name, value := range values {
+		item[name] = value
+	}
+	if mutation.ID != 0 {
+		item["id"] = mutation.ID
+	}
+	data, err := json.Marshal(map[string]interface{}{"itemId": mutation.ID, "item": item})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal dry-run response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.ContentLength = int64(len(data))
+	return resp, nil
+}
@@ -0,0 +1,101 @@
+package autotask
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/Tickets/entityInformation/fields", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, ticketFields)
+	})
+	server.AddHandler("/Tickets", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("%s should not be sent in dry-run mode", r.Method)
+	})
+	server.AddHandler("/Tickets/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/Tickets/query" {
+			server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"items": []map[string]interface{}{{"id": 10}}})
+			return
+		}
+		if r.Method != http.MethodGet {
+			t.Errorf("%s should not be sent in dry-run mode", r.Method)
+			return
+		}
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 10, "title": "Printer"}})
+	})
+
+	client := server.NewTestClient()
+	client.SetDryRun(true)
+	ctx := context.Background()
+
+	created, err := client.Tickets().Create(ctx, map[string]interface{}{"title": "New ticket", "status": 1})
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, float64(-1), created.(map[string]interface{})["id"], "create should get a synthetic ID")
+
+	_, err = client.Tickets().Update(ctx, 10, map[string]interface{}{"id": 10, "status": 5})
+	AssertNil(t, err, "error should be nil")
+
+	_, err = client.Tickets().Update(ctx, 11, map[string]interface{}{"id": 11, "status": 42})
+	var validationErr *ValidationError
+	AssertTrue(t, errors.As(err, &validationErr), "invalid update should fail validation")
+
+	_, err = BulkDelete(ctx, client.Tickets(), []int64{12, 13}, BulkOptions{})
+	AssertNil(t, err, "error should be nil")
+
+	_, err = client.Tickets().Get(ctx, 10)
+	AssertNil(t, err, "reads should still be sent")
+
+	req, err := client.NewRequest(ctx, http.MethodPost, "Tickets/query", NewEntityQueryParams(NewQueryFilter("id", OperatorEquals, 10)))
+	AssertNil(t, err, "error should be nil")
+	var found struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	_, err = client.Do(req, &found)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 1, len(found.Items), "queries sent with POST should still be sent")
+
+	report := client.DryRunReport()
+	AssertEqual(t, 5, len(report.Mutations), "every write should be recorded")
+	AssertEqual(t, http.MethodPost, report.Mutations[0].Method, "method should be recorded")
+	AssertTrue(t, strings.HasSuffix(report.Mutations[0].URL, "/Tickets"), "URL should be recorded")
+	AssertEqual(t, "[REDACTED]", report.Mutations[0].Headers["Secret"], "credentials should be redacted")
+	AssertTrue(t, strings.Contains(string(report.Mutations[0].Body), "New ticket"), "body should be recorded")
+	AssertEqual(t, int64(10), report.Mutations[1].ID, "update target should be recorded")
+	AssertEqual(t, 2, report.Counts()["Tickets delete"], "deletes should be counted")
+	AssertEqual(t, 1, len(report.Failed()), "validation failure should be reported")
+	AssertTrue(t, strings.Contains(report.String(), "5 planned writes"), "summary should count writes")
+
+	client.ResetDryRunReport()
+	AssertEqual(t, 0, len(client.DryRunReport().Mutations), "report should be cleared")
+}
+
+func TestWithDryRun(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	sent := 0
+	server.AddHandler("/Companies/", func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client := server.NewTestClient()
+	ctx := context.Background()
+
+	err := client.Companies().Delete(WithDryRun(ctx, true), 1)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 0, sent, "context dry run should not send the delete")
+
+	client.SetDryRun(true)
+	err = client.Companies().Delete(WithDryRun(ctx, false), 2)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 1, sent, "context should override the client setting")
+
+	AssertEqual(t, 1, len(client.DryRunReport().Mutations), "only the dry-run delete should be recorded")
+}
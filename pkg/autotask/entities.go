@@ -2,89 +2,117 @@ package autotask
 
 // Company represents an Autotask company
 type Company struct {
-	ID                      int64  `json:"id,omitempty"`
-	CompanyName             string `json:"companyName,omitempty"`
-	CompanyNumber           string `json:"companyNumber,omitempty"`
-	Phone                   string `json:"phone,omitempty"`
-	WebAddress              string `json:"webAddress,omitempty"`
-	Active                  bool   `json:"active,omitempty"`
-	Address1                string `json:"address1,omitempty"`
-	Address2                string `json:"address2,omitempty"`
-	City                    string `json:"city,omitempty"`
-	State                   string `json:"state,omitempty"`
-	PostalCode              string `json:"postalCode,omitempty"`
-	Country                 string `json:"country,omitempty"`
-	TerritoryID             int64  `json:"territoryID,omitempty"`
-	AccountNumber           string `json:"accountNumber,omitempty"`
-	TaxRegionID             int64  `json:"taxRegionID,omitempty"`
-	ParentCompanyID         int64  `json:"parentCompanyID,omitempty"`
-	CompanyType             int    `json:"companyType,omitempty"`
-	BillToCompanyID         int64  `json:"billToCompanyID,omitempty"`
-	BillToAddress1          string `json:"billToAddress1,omitempty"`
-	BillToAddress2          string `json:"billToAddress2,omitempty"`
-	BillToCity              string `json:"billToCity,omitempty"`
-	BillToState             string `json:"billToState,omitempty"`
-	BillToZipCode           string `json:"billToZipCode,omitempty"`
-	BillToCountryID         int64  `json:"billToCountryID,omitempty"`
-	BillToAttention         string `json:"billToAttention,omitempty"`
-	BillToAddressToUse      int    `json:"billToAddressToUse,omitempty"`
-	InvoiceMethod           int    `json:"invoiceMethod,omitempty"`
-	InvoiceNonContractItems bool   `json:"invoiceNonContractItems,omitempty"`
-	InvoiceTemplateID       int64  `json:"invoiceTemplateID,omitempty"`
-	QuoteTemplateID         int64  `json:"quoteTemplateID,omitempty"`
-	TaxID                   string `json:"taxID,omitempty"`
-	TaxExempt               bool   `json:"taxExempt,omitempty"`
-	CreatedDate             string `json:"createdDate,omitempty"`
-	LastActivityDate        string `json:"lastActivityDate,omitempty"`
-	DateStamp               string `json:"dateStamp,omitempty"`
+	ID                      int64              `json:"id,omitempty"`
+	CompanyName             string             `json:"companyName,omitempty"`
+	CompanyNumber           string             `json:"companyNumber,omitempty"`
+	Phone                   string             `json:"phone,omitempty"`
+	WebAddress              string             `json:"webAddress,omitempty"`
+	Active                  Nullable[bool]     `json:"active,omitzero"`
+	Address1                string             `json:"address1,omitempty"`
+	Address2                string             `json:"address2,omitempty"`
+	City                    string             `json:"city,omitempty"`
+	State                   string             `json:"state,omitempty"`
+	PostalCode              string             `json:"postalCode,omitempty"`
+	Country                 string             `json:"country,omitempty"`
+	TerritoryID             int64              `json:"territoryID,omitempty"`
+	AccountNumber           string             `json:"accountNumber,omitempty"`
+	TaxRegionID             int64              `json:"taxRegionID,omitempty"`
+	ParentCompanyID         Nullable[int64]    `json:"parentCompanyID,omitzero"`
+	CompanyType             int                `json:"companyType,omitempty"`
+	BillToCompanyID         int64              `json:"billToCompanyID,omitempty"`
+	BillToAddress1          string             `json:"billToAddress1,omitempty"`
+	BillToAddress2          string             `json:"billToAddress2,omitempty"`
+	BillToCity              string             `json:"billToCity,omitempty"`
+	BillToState             string             `json:"billToState,omitempty"`
+	BillToZipCode           string             `json:"billToZipCode,omitempty"`
+	BillToCountryID         int64              `json:"billToCountryID,omitempty"`
+	BillToAttention         string             `json:"billToAttention,omitempty"`
+	BillToAddressToUse      int                `json:"billToAddressToUse,omitempty"`
+	InvoiceMethod           int                `json:"invoiceMethod,omitempty"`
+	InvoiceNonContractItems Nullable[bool]     `json:"invoiceNonContractItems,omitzero"`
+	InvoiceTemplateID       int64              `json:"invoiceTemplateID,omitempty"`
+	QuoteTemplateID         int64              `json:"quoteTemplateID,omitempty"`
+	TaxID                   string             `json:"taxID,omitempty"`
+	TaxExempt               Nullable[bool]     `json:"taxExempt,omitzero"`
+	CreatedDate             Nullable[DateTime] `json:"createdDate,omitzero"`
+	LastActivityDate        Nullable[DateTime] `json:"lastActivityDate,omitzero"`
+	DateStamp               Nullable[DateTime] `json:"dateStamp,omitzero"`
 }
 
 // Ticket represents an Autotask ticket
 type Ticket struct {
-	ID                      int64  `json:"id,omitempty"`
-	TicketNumber            string `json:"ticketNumber,omitempty"`
-	Title                   string `json:"title,omitempty"`
-	Description             string `json:"description,omitempty"`
-	Status                  int    `json:"status,omitempty"`
-	Priority                int    `json:"priority,omitempty"`
-	DueDateTime             string `json:"dueDateTime,omitempty"`
-	CreateDate              string `json:"createDate,omitempty"`
-	LastActivityDate        string `json:"lastActivityDate,omitempty"`
-	CompanyID               int64  `json:"companyID,omitempty"`
-	ContactID               int64  `json:"contactID,omitempty"`
-	AccountID               int64  `json:"accountID,omitempty"`
-	QueueID                 int64  `json:"queueID,omitempty"`
-	AssignedResourceID      int64  `json:"assignedResourceID,omitempty"`
-	AssignedResourceRoleID  int64  `json:"assignedResourceRoleID,omitempty"`
-	TicketType              int    `json:"ticketType,omitempty"`
-	IssueType               int    `json:"issueType,omitempty"`
-	SubIssueType            int    `json:"subIssueType,omitempty"`
-	ServiceLevelAgreementID int64  `json:"serviceLevelAgreementID,omitempty"`
-	Source                  int    `json:"source,omitempty"`
-	CreatorResourceID       int64  `json:"creatorResourceID,omitempty"`
-	CompletedDate           string `json:"completedDate,omitempty"`
+	ID                      int64              `json:"id,omitempty"`
+	TicketNumber            string             `json:"ticketNumber,omitempty"`
+	Title                   string             `json:"title,omitempty"`
+	Description             string             `json:"description,omitempty"`
+	Status                  int                `json:"status,omitempty"`
+	Priority                int                `json:"priority,omitempty"`
+	DueDateTime             Nullable[DateTime] `json:"dueDateTime,omitzero"`
+	CreateDate              Nullable[DateTime] `json:"createDate,omitzero"`
+	LastActivityDate        Nullable[DateTime] `json:"lastActivityDate,omitzero"`
+	CompanyID               int64              `json:"companyID,omitempty"`
+	ContactID               Nullable[int64]    `json:"contactID,omitzero"`
+	AccountID               int64              `json:"accountID,omitempty"`
+	QueueID                 int64              `json:"queueID,omitempty"`
+	AssignedResourceID      Nullable[int64]    `json:"assignedResourceID,omitzero"`
+	AssignedResourceRoleID  Nullable[int64]    `json:"assignedResourceRoleID,omitzero"`
+	TicketType              int                `json:"ticketType,omitempty"`
+	IssueType               int                `json:"issueType,omitempty"`
+	SubIssueType            int                `json:"subIssueType,omitempty"`
+	ServiceLevelAgreementID int64              `json:"serviceLevelAgreementID,omitempty"`
+	Source                  int                `json:"source,omitempty"`
+	CreatorResourceID       int64              `json:"creatorResourceID,omitempty"`
+	CompletedDate           Nullable[DateTime] `json:"completedDate,omitzero"`
+	Resolution              string             `json:"resolution,omitempty"`
+}
+
+// TicketNote represents a note on an Autotask ticket
+type TicketNote struct {
+	ID                int64              `json:"id,omitempty"`
+	TicketID          int64              `json:"ticketID,omitempty"`
+	Title             string             `json:"title,omitempty"`
+	Description       string             `json:"description,omitempty"`
+	NoteType          int                `json:"noteType,omitempty"`
+	Publish           int                `json:"publish,omitempty"`
+	CreatorResourceID int64              `json:"creatorResourceID,omitempty"`
+	CreateDateTime    Nullable[DateTime] `json:"createDateTime,omitzero"`
+	LastActivityDate  Nullable[DateTime] `json:"lastActivityDate,omitzero"`
+}
+
+// TicketAttachment represents a file or link attached to an Autotask ticket
+type TicketAttachment struct {
+	ID                   int64              `json:"id,omitempty"`
+	ParentID             int64              `json:"parentID,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	FullPath             string             `json:"fullPath,omitempty"`
+	AttachmentType       string             `json:"attachmentType,omitempty"`
+	ContentType          string             `json:"contentType,omitempty"`
+	FileSize             int64              `json:"fileSize,omitempty"`
+	Publish              int                `json:"publish,omitempty"`
+	AttachDate           Nullable[DateTime] `json:"attachDate,omitzero"`
+	AttachedByResourceID int64              `json:"attachedByResourceID,omitempty"`
 }
 
 // Contact represents an Autotask contact
 type Contact struct {
-	ID               int64  `json:"id,omitempty"`
-	FirstName        string `json:"firstName,omitempty"`
-	LastName         string `json:"lastName,omitempty"`
-	CompanyID        int64  `json:"companyID,omitempty"`
-	Email            string `json:"emailAddress,omitempty"`
-	Phone            string `json:"phone,omitempty"`
-	MobilePhone      string `json:"mobilePhone,omitempty"`
-	Title            string `json:"title,omitempty"`
-	Active           bool   `json:"active,omitempty"`
-	Address1         string `json:"address1,omitempty"`
-	Address2         string `json:"address2,omitempty"`
-	City             string `json:"city,omitempty"`
-	State            string `json:"state,omitempty"`
-	PostalCode       string `json:"postalCode,omitempty"`
-	Country          string `json:"country,omitempty"`
-	PrimaryContact   bool   `json:"isPrimaryContact,omitempty"`
-	LastActivityDate string `json:"lastActivityDate,omitempty"`
-	CreatedDate      string `json:"createDate,omitempty"`
+	ID               int64              `json:"id,omitempty"`
+	FirstName        string             `json:"firstName,omitempty"`
+	LastName         string             `json:"lastName,omitempty"`
+	CompanyID        int64              `json:"companyID,omitempty"`
+	Email            string             `json:"emailAddress,omitempty"`
+	Phone            string             `json:"phone,omitempty"`
+	MobilePhone      string             `json:"mobilePhone,omitempty"`
+	Title            string             `json:"title,omitempty"`
+	Active           Nullable[bool]     `json:"active,omitzero"`
+	Address1         string             `json:"address1,omitempty"`
+	Address2         string             `json:"address2,omitempty"`
+	City             string             `json:"city,omitempty"`
+	State            string             `json:"state,omitempty"`
+	PostalCode       string             `json:"postalCode,omitempty"`
+	Country          string             `json:"country,omitempty"`
+	PrimaryContact   Nullable[bool]     `json:"isPrimaryContact,omitzero"`
+	LastActivityDate Nullable[DateTime] `json:"lastActivityDate,omitzero"`
+	CreatedDate      Nullable[DateTime] `json:"createDate,omitzero"`
 }
 
 // Resource represents a resource in Autotask
@@ -98,100 +126,101 @@ type Resource struct {
 
 // Project represents a project in Autotask
 type Project struct {
-	ID                    int64   `json:"id,omitempty"`
-	ProjectName           string  `json:"projectName,omitempty"`
-	Description           string  `json:"description,omitempty"`
-	CompanyID             int64   `json:"companyID,omitempty"`
-	Status                int     `json:"status,omitempty"`
-	ProjectNumber         string  `json:"projectNumber,omitempty"`
-	Type                  int     `json:"type,omitempty"`
-	StartDate             string  `json:"startDate,omitempty"`
-	EndDate               string  `json:"endDate,omitempty"`
-	EstimatedHours        float64 `json:"estimatedHours,omitempty"`
-	ProjectLeadResourceID int64   `json:"projectLeadResourceID,omitempty"`
-	CompletedPercentage   float64 `json:"completedPercentage,omitempty"`
-	DepartmentID          int64   `json:"departmentID,omitempty"`
-	ContractID            int64   `json:"contractID,omitempty"`
-	CreatorResourceID     int64   `json:"creatorResourceID,omitempty"`
-	CreateDate            string  `json:"createDate,omitempty"`
-	LastActivityDate      string  `json:"lastActivityDate,omitempty"`
+	ID                    int64              `json:"id,omitempty"`
+	ProjectName           string             `json:"projectName,omitempty"`
+	Description           string             `json:"description,omitempty"`
+	CompanyID             int64              `json:"companyID,omitempty"`
+	Status                int                `json:"status,omitempty"`
+	ProjectNumber         string             `json:"projectNumber,omitempty"`
+	Type                  int                `json:"type,omitempty"`
+	StartDate             Nullable[DateTime] `json:"startDate,omitzero"`
+	EndDate               Nullable[DateTime] `json:"endDate,omitzero"`
+	EstimatedHours        float64            `json:"estimatedHours,omitempty"`
+	ProjectLeadResourceID Nullable[int64]    `json:"projectLeadResourceID,omitzero"`
+	CompletedPercentage   float64            `json:"completedPercentage,omitempty"`
+	DepartmentID          int64              `json:"departmentID,omitempty"`
+	ContractID            int64              `json:"contractID,omitempty"`
+	CreatorResourceID     int64              `json:"creatorResourceID,omitempty"`
+	CreateDate            Nullable[DateTime] `json:"createDate,omitzero"`
+	LastActivityDate      Nullable[DateTime] `json:"lastActivityDate,omitzero"`
 }
 
 // Task represents a task in Autotask
 type Task struct {
-	ID                 int64   `json:"id,omitempty"`
-	TaskNumber         string  `json:"taskNumber,omitempty"`
-	Title              string  `json:"title,omitempty"`
-	Description        string  `json:"description,omitempty"`
-	Status             int     `json:"status,omitempty"`
-	Priority           int     `json:"priority,omitempty"`
-	ProjectID          int64   `json:"projectID,omitempty"`
-	AssignedResourceID int64   `json:"assignedResourceID,omitempty"`
-	StartDate          string  `json:"startDate,omitempty"`
-	EndDate            string  `json:"endDate,omitempty"`
-	EstimatedHours     float64 `json:"estimatedHours,omitempty"`
-	RemainingHours     float64 `json:"remainingHours,omitempty"`
-	CompletedDate      string  `json:"completedDate,omitempty"`
-	CreateDate         string  `json:"createDate,omitempty"`
-	LastActivityDate   string  `json:"lastActivityDate,omitempty"`
-	PhaseID            int64   `json:"phaseID,omitempty"`
-	TaskType           int     `json:"taskType,omitempty"`
-	CreatorResourceID  int64   `json:"creatorResourceID,omitempty"`
+	ID                 int64              `json:"id,omitempty"`
+	TaskNumber         string             `json:"taskNumber,omitempty"`
+	Title              string             `json:"title,omitempty"`
+	Description        string             `json:"description,omitempty"`
+	Status             int                `json:"status,omitempty"`
+	Priority           int                `json:"priority,omitempty"`
+	ProjectID          int64              `json:"projectID,omitempty"`
+	AssignedResourceID Nullable[int64]    `json:"assignedResourceID,omitzero"`
+	StartDate          Nullable[DateTime] `json:"startDate,omitzero"`
+	EndDate            Nullable[DateTime] `json:"endDate,omitzero"`
+	EstimatedHours     float64            `json:"estimatedHours,omitempty"`
+	RemainingHours     float64            `json:"remainingHours,omitempty"`
+	CompletedDate      Nullable[DateTime] `json:"completedDate,omitzero"`
+	CreateDate         Nullable[DateTime] `json:"createDate,omitzero"`
+	LastActivityDate   Nullable[DateTime] `json:"lastActivityDate,omitzero"`
+	PhaseID            int64              `json:"phaseID,omitempty"`
+	TaskType           int                `json:"taskType,omitempty"`
+	CreatorResourceID  int64              `json:"creatorResourceID,omitempty"`
 }
 
 // TimeEntry represents a time entry in Autotask
 type TimeEntry struct {
-	ID               int64   `json:"id,omitempty"`
-	ResourceID       int64   `json:"resourceID,omitempty"`
-	TicketID         int64   `json:"ticketID,omitempty"`
-	TaskID           int64   `json:"taskID,omitempty"`
-	Type             int     `json:"type,omitempty"`
-	DateWorked       string  `json:"dateWorked,omitempty"`
-	StartDateTime    string  `json:"startDateTime,omitempty"`
-	EndDateTime      string  `json:"endDateTime,omitempty"`
-	HoursWorked      float64 `json:"hoursWorked,omitempty"`
-	HoursToBill      float64 `json:"hoursToBill,omitempty"`
-	SummaryNotes     string  `json:"summaryNotes,omitempty"`
-	InternalNotes    string  `json:"internalNotes,omitempty"`
-	NonBillable      bool    `json:"nonBillable,omitempty"`
-	CreateDate       string  `json:"createDate,omitempty"`
-	LastModifiedDate string  `json:"lastModifiedDate,omitempty"`
+	ID               int64              `json:"id,omitempty"`
+	ResourceID       int64              `json:"resourceID,omitempty"`
+	TicketID         int64              `json:"ticketID,omitempty"`
+	TaskID           int64              `json:"taskID,omitempty"`
+	RoleID           int64              `json:"roleID,omitempty"`
+	Type             int                `json:"type,omitempty"`
+	DateWorked       Nullable[Date]     `json:"dateWorked,omitzero"`
+	StartDateTime    Nullable[DateTime] `json:"startDateTime,omitzero"`
+	EndDateTime      Nullable[DateTime] `json:"endDateTime,omitzero"`
+	HoursWorked      float64            `json:"hoursWorked,omitempty"`
+	HoursToBill      float64            `json:"hoursToBill,omitempty"`
+	SummaryNotes     string             `json:"summaryNotes,omitempty"`
+	InternalNotes    string             `json:"internalNotes,omitempty"`
+	NonBillable      Nullable[bool]     `json:"nonBillable,omitzero"`
+	CreateDate       Nullable[DateTime] `json:"createDate,omitzero"`
+	LastModifiedDate Nullable[DateTime] `json:"lastModifiedDate,omitzero"`
 }
 
 // Contract represents a contract in Autotask
 type Contract struct {
-	ID                      int64   `json:"id,omitempty"`
-	ContractName            string  `json:"contractName,omitempty"`
-	ContractNumber          string  `json:"contractNumber,omitempty"`
-	CompanyID               int64   `json:"companyID,omitempty"`
-	Status                  int     `json:"status,omitempty"`
-	ServiceLevelAgreementID int64   `json:"serviceLevelAgreementID,omitempty"`
-	StartDate               string  `json:"startDate,omitempty"`
-	EndDate                 string  `json:"endDate,omitempty"`
-	ContractType            int     `json:"contractType,omitempty"`
-	IsDefaultContract       bool    `json:"isDefaultContract,omitempty"`
-	SetupFee                float64 `json:"setupFee,omitempty"`
-	EstimatedHours          float64 `json:"estimatedHours,omitempty"`
-	CreatorResourceID       int64   `json:"creatorResourceID,omitempty"`
-	CreateDate              string  `json:"createDate,omitempty"`
-	LastActivityDate        string  `json:"lastActivityDate,omitempty"`
+	ID                      int64              `json:"id,omitempty"`
+	ContractName            string             `json:"contractName,omitempty"`
+	ContractNumber          string             `json:"contractNumber,omitempty"`
+	CompanyID               int64              `json:"companyID,omitempty"`
+	Status                  int                `json:"status,omitempty"`
+	ServiceLevelAgreementID int64              `json:"serviceLevelAgreementID,omitempty"`
+	StartDate               Nullable[Date]     `json:"startDate,omitzero"`
+	EndDate                 Nullable[Date]     `json:"endDate,omitzero"`
+	ContractType            int                `json:"contractType,omitempty"`
+	IsDefaultContract       Nullable[bool]     `json:"isDefaultContract,omitzero"`
+	SetupFee                float64            `json:"setupFee,omitempty"`
+	EstimatedHours          float64            `json:"estimatedHours,omitempty"`
+	CreatorResourceID       int64              `json:"creatorResourceID,omitempty"`
+	CreateDate              Nullable[DateTime] `json:"createDate,omitzero"`
+	LastActivityDate        Nullable[DateTime] `json:"lastActivityDate,omitzero"`
 }
 
 // ConfigurationItem represents a configuration item in Autotask
 type ConfigurationItem struct {
-	ID                    int64  `json:"id,omitempty"`
-	CompanyID             int64  `json:"companyID,omitempty"`
-	ConfigurationItemType int    `json:"configurationItemType,omitempty"`
-	ReferenceTitle        string `json:"referenceTitle,omitempty"`
-	ReferenceNumber       string `json:"referenceNumber,omitempty"`
-	SerialNumber          string `json:"serialNumber,omitempty"`
-	InstallDate           string `json:"installDate,omitempty"`
-	ProductID             int64  `json:"productID,omitempty"`
-	Status                int    `json:"status,omitempty"`
-	Location              string `json:"location,omitempty"`
-	Active                bool   `json:"active,omitempty"`
-	CreateDate            string `json:"createDate,omitempty"`
-	LastModifiedDate      string `json:"lastModifiedDate,omitempty"`
+	ID                    int64              `json:"id,omitempty"`
+	CompanyID             int64              `json:"companyID,omitempty"`
+	ConfigurationItemType int                `json:"configurationItemType,omitempty"`
+	ReferenceTitle        string             `json:"referenceTitle,omitempty"`
+	ReferenceNumber       string             `json:"referenceNumber,omitempty"`
+	SerialNumber          string             `json:"serialNumber,omitempty"`
+	InstallDate           Nullable[DateTime] `json:"installDate,omitzero"`
+	ProductID             int64              `json:"productID,omitempty"`
+	Status                int                `json:"status,omitempty"`
+	Location              string             `json:"location,omitempty"`
+	Active                Nullable[bool]     `json:"active,omitzero"`
+	CreateDate            Nullable[DateTime] `json:"createDate,omitzero"`
+	LastModifiedDate      Nullable[DateTime] `json:"lastModifiedDate,omitzero"`
 }
 
 // Response types
@@ -35,6 +35,13 @@ func NewBaseEntityService(client Client, entityName string) BaseEntityService {
 
 // Get gets an entity by ID.
 func (s *BaseEntityService) Get(ctx context.Context, id int64) (interface{}, error) {
+	ctx = withOperation(ctx, s.EntityName, "get")
+	if batcher, ok := s.Client.(getBatcher); ok {
+		if item, batched, err := batcher.batchGet(ctx, s.EntityName, id); batched {
+			return item, err
+		}
+	}
+
 	url := fmt.Sprintf("%s/%d", s.EntityName, id)
 	req, err := s.Client.NewRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -52,6 +59,7 @@ func (s *BaseEntityService) Get(ctx context.Context, id int64) (interface{}, err
 
 // Query queries entities with a filter.
 func (s *BaseEntityService) Query(ctx context.Context, filter string, result interface{}) error {
+	ctx = withOperation(ctx, s.EntityName, "query")
 	// Parse the filter string into a query filter or filter group
 	var queryFilter interface{}
 
@@ -119,6 +127,7 @@ func (s *BaseEntityService) Query(ctx context.Context, filter string, result int
 
 // Create creates a new entity.
 func (s *BaseEntityService) Create(ctx context.Context, entity interface{}) (interface{}, error) {
+	ctx = withOperation(ctx, s.EntityName, "create")
 	url := s.EntityName
 	req, err := s.Client.NewRequest(ctx, http.MethodPost, url, entity)
 	if err != nil {
@@ -136,6 +145,7 @@ func (s *BaseEntityService) Create(ctx context.Context, entity interface{}) (int
 
 // Update updates an existing entity.
 func (s *BaseEntityService) Update(ctx context.Context, id int64, entity interface{}) (interface{}, error) {
+	ctx = withOperation(ctx, s.EntityName, "update")
 	url := fmt.Sprintf("%s/%d", s.EntityName, id)
 	req, err := s.Client.NewRequest(ctx, http.MethodPatch, url, entity)
 	if err != nil {
@@ -153,6 +163,7 @@ func (s *BaseEntityService) Update(ctx context.Context, id int64, entity interfa
 
 // Delete deletes an entity by ID.
 func (s *BaseEntityService) Delete(ctx context.Context, id int64) error {
+	ctx = withOperation(ctx, s.EntityName, "delete")
 	url := fmt.Sprintf("%s/%d", s.EntityName, id)
 	req, err := s.Client.NewRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
@@ -165,6 +176,7 @@ func (s *BaseEntityService) Delete(ctx context.Context, id int64) error {
 
 // Count counts entities matching a filter.
 func (s *BaseEntityService) Count(ctx context.Context, filter string) (int, error) {
+	ctx = withOperation(ctx, s.EntityName, "count")
 	// Convert the filter string to the required JSON format
 	var fieldName string
 	switch s.EntityName {
@@ -213,6 +225,7 @@ func (s *BaseEntityService) Count(ctx context.Context, filter string) (int, erro
 
 // Pagination handles paginated results.
 func (s *BaseEntityService) Pagination(ctx context.Context, url string, result interface{}) error {
+	ctx = withOperation(ctx, s.EntityName, "page")
 	req, err := s.Client.NewRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return err
@@ -222,39 +235,48 @@ func (s *BaseEntityService) Pagination(ctx context.Context, url string, result i
 	return err
 }
 
-// BatchCreate creates multiple entities in a single request.
+// BatchCreate creates multiple entities and stores their new IDs in result
+// in the shape of a query response, {"items":[{"id":...}]}. The Autotask
+// REST API has no batch endpoint, so each entity is created with its own
+// request.
+//
+// Deprecated: use BulkCreate, which reports the outcome of every item.
 func (s *BaseEntityService) BatchCreate(ctx context.Context, entities []interface{}, result interface{}) error {
-	url := s.EntityName + "/batch"
-	req, err := s.Client.NewRequest(ctx, http.MethodPost, url, entities)
-	if err != nil {
-		return err
-	}
-
-	_, err = s.Client.Do(req, result)
-	return err
+	results, err := BulkCreate(ctx, s, entities, BulkOptions{})
+	return storeBulkIDs(results, result, err)
 }
 
-// BatchUpdate updates multiple entities in a single request.
+// BatchUpdate updates multiple entities, each of which must carry its id,
+// and stores the updated IDs in result like BatchCreate. Each entity is
+// updated with its own request.
+//
+// Deprecated: use BulkUpdate, which reports the outcome of every item.
 func (s *BaseEntityService) BatchUpdate(ctx context.Context, entities []interface{}, result interface{}) error {
-	url := s.EntityName + "/batch"
-	req, err := s.Client.NewRequest(ctx, http.MethodPatch, url, entities)
-	if err != nil {
-		return err
-	}
+	results, err := BulkUpdate(ctx, s, entities, BulkOptions{})
+	return storeBulkIDs(results, result, err)
+}
 
-	_, err = s.Client.Do(req, result)
+// BatchDelete deletes multiple entities, each with its own request.
+//
+// Deprecated: use BulkDelete, which reports the outcome of every item.
+func (s *BaseEntityService) BatchDelete(ctx context.Context, ids []int64) error {
+	_, err := BulkDelete(ctx, s, ids, BulkOptions{})
 	return err
 }
 
-// BatchDelete deletes multiple entities in a single request.
-func (s *BaseEntityService) BatchDelete(ctx context.Context, ids []int64) error {
-	url := s.EntityName + "/batch"
-	req, err := s.Client.NewRequest(ctx, http.MethodDelete, url, ids)
-	if err != nil {
-		return err
+// storeBulkIDs stores the IDs of the successful items in result
+func storeBulkIDs(results []BulkResult, result interface{}, err error) error {
+	if result != nil {
+		items := make([]map[string]int64, 0, len(results))
+		for _, r := range results {
+			if r.Err == nil {
+				items = append(items, map[string]int64{"id": r.ID})
+			}
+		}
+		if decodeErr := decodeItem(map[string]interface{}{"items": items}, result); decodeErr != nil && err == nil {
+			return decodeErr
+		}
 	}
-
-	_, err = s.Client.Do(req, nil)
 	return err
 }
 
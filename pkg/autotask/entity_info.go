@@ -0,0 +1,126 @@
+package autotask
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// FieldInfo describes a field of an Autotask entity as reported by the
+// entityInformation/fields endpoint
+type FieldInfo struct {
+	Name                     string          `json:"name"`
+	DataType                 string          `json:"dataType"`
+	Length                   int             `json:"length"`
+	IsRequired               bool            `json:"isRequired"`
+	IsReadOnly               bool            `json:"isReadOnly"`
+	IsQueryable              bool            `json:"isQueryable"`
+	IsReference              bool            `json:"isReference"`
+	ReferenceEntityType      string          `json:"referenceEntityType"`
+	IsPickList               bool            `json:"isPickList"`
+	PicklistValues           []PicklistValue `json:"picklistValues"`
+	PicklistParentValueField string          `json:"picklistParentValueField"`
+	IsSupportedWebhookField  bool            `json:"isSupportedWebhookField"`
+}
+
+// PicklistValue represents one option of a picklist field
+type PicklistValue struct {
+	Value          string `json:"value"`
+	Label          string `json:"label"`
+	IsDefaultValue bool   `json:"isDefaultValue"`
+	SortOrder      int    `json:"sortOrder"`
+	ParentValue    string `json:"parentValue"`
+	IsActive       bool   `json:"isActive"`
+	IsSystem       bool   `json:"isSystem"`
+}
+
+// LookupPicklistValue finds the picklist option whose label or value matches
+// labelOrValue, ignoring case
+func (f FieldInfo) LookupPicklistValue(labelOrValue string) (PicklistValue, bool) {
+	for _, v := range f.PicklistValues {
+		if v.Value == labelOrValue {
+			return v, true
+		}
+	}
+	for _, v := range f.PicklistValues {
+		if strings.EqualFold(v.Label, labelOrValue) {
+			return v, true
+		}
+	}
+	return PicklistValue{}, false
+}
+
+// DefaultPicklistValue returns the picklist option marked as the default
+func (f FieldInfo) DefaultPicklistValue() (PicklistValue, bool) {
+	for _, v := range f.PicklistValues {
+		if v.IsDefaultValue && v.IsActive {
+			return v, true
+		}
+	}
+	return PicklistValue{}, false
+}
+
+// FindField returns the field with the given name, ignoring case
+func FindField(fields []FieldInfo, name string) (FieldInfo, bool) {
+	for _, f := range fields {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return FieldInfo{}, false
+}
+
+// fieldInfoCache holds entity field metadata, which rarely changes
+type fieldInfoCache struct {
+	mu     sync.Mutex
+	fields map[string][]FieldInfo
+}
+
+// get returns the cached fields for an entity
+func (c *fieldInfoCache) get(entityName string) ([]FieldInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fields, ok := c.fields[entityName]
+	return fields, ok
+}
+
+// set caches the fields for an entity
+func (c *fieldInfoCache) set(entityName string, fields []FieldInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fields == nil {
+		c.fields = make(map[string][]FieldInfo)
+	}
+	c.fields[entityName] = fields
+}
+
+// GetFieldInfo returns the field metadata for an entity. Results are cached
+// for the lifetime of the client.
+func (c *client) GetFieldInfo(ctx context.Context, entityName string) ([]FieldInfo, error) {
+	if fields, ok := c.fieldInfo.get(entityName); ok {
+		return fields, nil
+	}
+
+	ctx = withOperation(ctx, entityName, "field_info")
+	req, err := c.NewRequest(ctx, http.MethodGet, entityName+"/entityInformation/fields", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Fields []FieldInfo `json:"fields"`
+	}
+	if _, err := c.Do(req, &response); err != nil {
+		return nil, fmt.Errorf("failed to get field info for %s: %w", entityName, err)
+	}
+
+	c.fieldInfo.set(entityName, response.Fields)
+	return response.Fields, nil
+}
+
+// GetFieldInfo returns the field metadata for the entity
+func (s *BaseEntityService) GetFieldInfo(ctx context.Context) ([]FieldInfo, error) {
+	return s.Client.GetFieldInfo(ctx, s.EntityName)
+}
@@ -3,8 +3,10 @@ package autotask
 import (
 	"context"
 	"encoding/json"
-	"io"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -356,39 +358,23 @@ func TestBatchCreate(t *testing.T) {
 	server := NewMockServer(t)
 	defer server.Close()
 
-	// Add a handler for the batch create endpoint
-	server.AddHandler("/TestEntities/batch", func(w http.ResponseWriter, r *http.Request) {
+	// The API has no batch endpoint, so each entity is posted on its own
+	var mu sync.Mutex
+	nextID := int64(1000)
+	server.AddHandler("/TestEntities", func(w http.ResponseWriter, r *http.Request) {
 		// Verify request method
 		AssertEqual(t, http.MethodPost, r.Method, "method should match")
 
-		// Read request body
-		body, err := io.ReadAll(r.Body)
-		AssertNil(t, err, "error reading request body should be nil")
-
-		var entities []interface{}
-		err = json.Unmarshal(body, &entities)
-		AssertNil(t, err, "error unmarshaling request body should be nil")
-		AssertEqual(t, 2, len(entities), "should have 2 entities")
-
-		// Create response entities with IDs
-		responseEntities := []map[string]interface{}{
-			{
-				"id":   float64(1001),
-				"name": "Entity 1",
-			},
-			{
-				"id":   float64(1002),
-				"name": "Entity 2",
-			},
-		}
+		var entity map[string]interface{}
+		err := json.NewDecoder(r.Body).Decode(&entity)
+		AssertNil(t, err, "error decoding request body should be nil")
 
-		// Create response
-		response := map[string]interface{}{
-			"items": responseEntities,
-		}
+		mu.Lock()
+		nextID++
+		id := nextID
+		mu.Unlock()
 
-		// Send response
-		server.RespondWithJSON(w, http.StatusOK, response)
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"itemId": id})
 	})
 
 	// Create a client
@@ -425,41 +411,18 @@ func TestBatchUpdate(t *testing.T) {
 	server := NewMockServer(t)
 	defer server.Close()
 
-	// Add a handler for the batch update endpoint
-	server.AddHandler("/TestEntities/batch", func(w http.ResponseWriter, r *http.Request) {
+	// Each entity is patched on its own
+	var mu sync.Mutex
+	var updated []string
+	server.AddHandler("/TestEntities/", func(w http.ResponseWriter, r *http.Request) {
 		// Verify request method
 		AssertEqual(t, http.MethodPatch, r.Method, "method should match")
 
-		// Read request body
-		body, err := io.ReadAll(r.Body)
-		AssertNil(t, err, "error reading request body should be nil")
-
-		var entities []interface{}
-		err = json.Unmarshal(body, &entities)
-		AssertNil(t, err, "error unmarshaling request body should be nil")
-		AssertEqual(t, 2, len(entities), "should have 2 entities")
-
-		// Create response entities with updated values
-		responseEntities := []map[string]interface{}{
-			{
-				"id":      float64(1001),
-				"name":    "Updated Entity 1",
-				"updated": true,
-			},
-			{
-				"id":      float64(1002),
-				"name":    "Updated Entity 2",
-				"updated": true,
-			},
-		}
-
-		// Create response
-		response := map[string]interface{}{
-			"items": responseEntities,
-		}
+		mu.Lock()
+		updated = append(updated, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+		mu.Unlock()
 
-		// Send response
-		server.RespondWithJSON(w, http.StatusOK, response)
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"item": map[string]interface{}{"id": 1}})
 	})
 
 	// Create a client
@@ -489,6 +452,8 @@ func TestBatchUpdate(t *testing.T) {
 	items, ok := result["items"].([]interface{})
 	AssertTrue(t, ok, "result should have items")
 	AssertEqual(t, 2, len(items), "should have 2 items")
+	sort.Strings(updated)
+	AssertEqual(t, "1001,1002", strings.Join(updated, ","), "each entity should be patched by ID")
 }
 
 func TestBatchDelete(t *testing.T) {
@@ -496,18 +461,16 @@ func TestBatchDelete(t *testing.T) {
 	server := NewMockServer(t)
 	defer server.Close()
 
-	// Add a handler for the batch delete endpoint
-	server.AddHandler("/TestEntities/batch", func(w http.ResponseWriter, r *http.Request) {
+	// Each entity is deleted on its own
+	var mu sync.Mutex
+	var deleted []string
+	server.AddHandler("/TestEntities/", func(w http.ResponseWriter, r *http.Request) {
 		// Verify request method
 		AssertEqual(t, http.MethodDelete, r.Method, "method should match")
 
-		// Read request body
-		var ids []int64
-		err := json.NewDecoder(r.Body).Decode(&ids)
-		AssertNil(t, err, "error decoding request body should be nil")
-		AssertLen(t, ids, 2, "should have 2 ids")
-		AssertEqual(t, int64(1001), ids[0], "first id should match")
-		AssertEqual(t, int64(1002), ids[1], "second id should match")
+		mu.Lock()
+		deleted = append(deleted, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+		mu.Unlock()
 
 		// Send response
 		w.WriteHeader(http.StatusNoContent)
@@ -528,6 +491,8 @@ func TestBatchDelete(t *testing.T) {
 
 	// Verify no error
 	AssertNil(t, err, "error should be nil")
+	sort.Strings(deleted)
+	AssertEqual(t, "1001,1002", strings.Join(deleted, ","), "each entity should be deleted by ID")
 }
 
 func TestGetNextPage(t *testing.T) {
@@ -667,3 +632,42 @@ func TestGetPreviousPageNoUrl(t *testing.T) {
 		t.Errorf("items should be nil, got %v", items)
 	}
 }
+
+func TestLookupField(t *testing.T) {
+	fields := map[string]int{"companyID": 7}
+
+	v, ok := LookupField(fields, "companyID")
+	AssertTrue(t, ok, "exact name should be found")
+	AssertEqual(t, 7, v, "value should match")
+
+	v, ok = LookupField(fields, "CompanyId")
+	AssertTrue(t, ok, "name should match regardless of case")
+	AssertEqual(t, 7, v, "value should match")
+
+	_, ok = LookupField(fields, "contactID")
+	AssertTrue(t, !ok, "missing field should not be found")
+}
+
+func TestQueryByFieldPages(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/Contacts/query", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+				"items":       []map[string]interface{}{{"id": 3}},
+				"pageDetails": map[string]interface{}{"nextPageUrl": nil},
+			})
+			return
+		}
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"items":       []map[string]interface{}{{"id": 1}, {"id": 2}},
+			"pageDetails": map[string]interface{}{"nextPageUrl": "/Contacts/query?page=2"},
+		})
+	})
+
+	client := server.NewTestClient()
+	items, err := QueryByField[map[string]interface{}](context.Background(), client, "Contacts", "companyID", []interface{}{7}, 0)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 3, len(items), "every page should be fetched")
+}
@@ -0,0 +1,175 @@
+package autotask
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ImpersonationHeader is the header Autotask uses to attribute a write to a resource
+const ImpersonationHeader = "ImpersonationResourceId"
+
+// impersonationCacheTTL is how long a resource validation result is reused
+const impersonationCacheTTL = 10 * time.Minute
+
+// impersonationKey is the context key for the impersonated resource ID
+type impersonationKey struct{}
+
+// WithImpersonation returns a copy of ctx that attributes create, update and
+// delete calls made with it to the given resource. The client checks that
+// the resource exists and is active before sending the write.
+func WithImpersonation(ctx context.Context, resourceID int64) context.Context {
+	return context.WithValue(ctx, impersonationKey{}, resourceID)
+}
+
+// ImpersonationFromContext returns the impersonated resource ID carried by ctx
+func ImpersonationFromContext(ctx context.Context) (int64, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	id, ok := ctx.Value(impersonationKey{}).(int64)
+	return id, ok && id > 0
+}
+
+// withoutImpersonation returns a copy of ctx with impersonation cleared
+func withoutImpersonation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, impersonationKey{}, int64(0))
+}
+
+// ImpersonationError is returned when a write can't be attributed to the requested resource
+type ImpersonationError struct {
+	ResourceID int64
+	Reason     string
+	Err        error
+}
+
+func (e *ImpersonationError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("cannot impersonate resource %d: %s: %v", e.ResourceID, e.Reason, e.Err)
+	}
+	return fmt.Sprintf("cannot impersonate resource %d: %s", e.ResourceID, e.Reason)
+}
+
+// Unwrap returns the underlying error
+func (e *ImpersonationError) Unwrap() error {
+	return e.Err
+}
+
+// impersonationEntry is a cached resource validation result
+type impersonationEntry struct {
+	err     error
+	expires time.Time
+}
+
+// impersonationCache remembers which resources may be impersonated
+type impersonationCache struct {
+	mu      sync.Mutex
+	entries map[int64]impersonationEntry
+}
+
+// get returns the cached validation result for a resource
+func (c *impersonationCache) get(resourceID int64) (impersonationEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[resourceID]
+	if !ok || time.Now().After(entry.expires) {
+		return impersonationEntry{}, false
+	}
+	return entry, true
+}
+
+// set caches the validation result for a resource
+func (c *impersonationCache) set(resourceID int64, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[int64]impersonationEntry)
+	}
+	c.entries[resourceID] = impersonationEntry{err: err, expires: time.Now().Add(impersonationCacheTTL)}
+}
+
+// isWriteMethod reports whether method creates, changes or removes data
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// applyImpersonation sets the impersonation header on write requests made
+// with an impersonating context, after validating the resource. Queries are
+// POSTs but only read data, so they are sent as the API user.
+func (c *client) applyImpersonation(ctx context.Context, req *http.Request) error {
+	resourceID, ok := ImpersonationFromContext(ctx)
+	if !ok || !isWriteMethod(req.Method) || isQueryRequest(req) {
+		return nil
+	}
+
+	if err := c.validateImpersonation(ctx, resourceID); err != nil {
+		return err
+	}
+
+	req.Header.Set(ImpersonationHeader, strconv.FormatInt(resourceID, 10))
+	return nil
+}
+
+// validateImpersonation checks that a resource exists and is active, using
+// the cached result when available
+func (c *client) validateImpersonation(ctx context.Context, resourceID int64) error {
+	if entry, ok := c.impersonation.get(resourceID); ok {
+		return entry.err
+	}
+
+	item, err := c.Resources().Get(withoutImpersonation(ctx), resourceID)
+	if err != nil {
+		if errResp, ok := err.(*ErrorResponse); ok && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+			err = &ImpersonationError{ResourceID: resourceID, Reason: "resource not found"}
+			c.impersonation.set(resourceID, err)
+			return err
+		}
+		// Don't cache transient failures
+		return &ImpersonationError{ResourceID: resourceID, Reason: "failed to look up resource", Err: err}
+	}
+
+	err = checkImpersonationResource(resourceID, item)
+	c.impersonation.set(resourceID, err)
+	return err
+}
+
+// checkImpersonationResource verifies a resource returned by the API can be impersonated
+func checkImpersonationResource(resourceID int64, item interface{}) error {
+	if item == nil {
+		return &ImpersonationError{ResourceID: resourceID, Reason: "resource not found"}
+	}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return &ImpersonationError{ResourceID: resourceID, Reason: "failed to read resource", Err: err}
+	}
+
+	var resource struct {
+		ID       int64 `json:"id"`
+		IsActive *bool `json:"isActive"`
+		Active   *bool `json:"active"`
+	}
+	if err := json.Unmarshal(data, &resource); err != nil {
+		return &ImpersonationError{ResourceID: resourceID, Reason: "failed to read resource", Err: err}
+	}
+
+	active := resource.IsActive
+	if active == nil {
+		active = resource.Active
+	}
+	if active != nil && !*active {
+		return &ImpersonationError{ResourceID: resourceID, Reason: "resource is inactive"}
+	}
+
+	return nil
+}
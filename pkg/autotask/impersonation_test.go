@@ -0,0 +1,94 @@
+package autotask
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestWithImpersonation(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	resourceLookups := 0
+	server.AddHandler("/Resources/42", func(w http.ResponseWriter, r *http.Request) {
+		resourceLookups++
+		AssertEqual(t, "", r.Header.Get(ImpersonationHeader), "resource lookup should not be impersonated")
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 42, "isActive": true}})
+	})
+	server.AddHandler("/TicketNotes", func(w http.ResponseWriter, r *http.Request) {
+		AssertEqual(t, "42", r.Header.Get(ImpersonationHeader), "impersonation header should be sent")
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"itemId": 7}})
+	})
+	server.AddHandler("/TicketNotes/7", func(w http.ResponseWriter, r *http.Request) {
+		AssertEqual(t, "", r.Header.Get(ImpersonationHeader), "reads should not be impersonated")
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 7}})
+	})
+	server.AddHandler("/TicketNotes/query", func(w http.ResponseWriter, r *http.Request) {
+		AssertEqual(t, "", r.Header.Get(ImpersonationHeader), "queries should not be impersonated")
+		server.RespondWithJSON(w, http.StatusOK, ListResponse{Items: []interface{}{}})
+	})
+
+	client := server.NewTestClient()
+	notes := NewBaseEntityService(client, "TicketNotes")
+	ctx := WithImpersonation(context.Background(), 42)
+
+	for i := 0; i < 2; i++ {
+		_, err := notes.Create(ctx, map[string]interface{}{"title": "Note"})
+		AssertNil(t, err, "error should be nil")
+	}
+	_, err := notes.Get(ctx, 7)
+	AssertNil(t, err, "error should be nil")
+
+	// Queries are POSTs but only read, so they are not impersonated either
+	req, err := client.NewRequest(ctx, http.MethodPost, "TicketNotes/query", NewEntityQueryParams(NewQueryFilter("id", OperatorEquals, 7)))
+	AssertNil(t, err, "error should be nil")
+	var found ListResponse
+	_, err = client.Do(req, &found)
+	AssertNil(t, err, "error should be nil")
+
+	AssertEqual(t, 1, resourceLookups, "resource validation should be cached")
+}
+
+func TestWithImpersonationInactiveResource(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/Resources/43", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 43, "isActive": false}})
+	})
+	server.AddHandler("/Resources/44", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: nil})
+	})
+	server.AddHandler("/TimeEntries", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("write should not be sent for an invalid impersonation resource")
+	})
+
+	client := server.NewTestClient()
+
+	_, err := client.TimeEntries().Create(WithImpersonation(context.Background(), 43), map[string]interface{}{})
+	var impErr *ImpersonationError
+	AssertTrue(t, errors.As(err, &impErr), "error should be an *ImpersonationError")
+	AssertEqual(t, "resource is inactive", impErr.Reason, "reason should match")
+
+	_, err = client.TimeEntries().Create(WithImpersonation(context.Background(), 44), map[string]interface{}{})
+	AssertTrue(t, errors.As(err, &impErr), "error should be an *ImpersonationError")
+	AssertEqual(t, "resource not found", impErr.Reason, "reason should match")
+}
+
+func TestWithHeader(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/Companies/1", func(w http.ResponseWriter, r *http.Request) {
+		AssertEqual(t, "audit-7", r.Header.Get("X-Audit-Id"), "context header should be sent")
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 1}})
+	})
+
+	client := server.NewTestClient()
+	ctx := WithHeader(context.Background(), "X-Audit-Id", "audit-7")
+
+	_, err := client.Companies().Get(ctx, 1)
+	AssertNil(t, err, "error should be nil")
+}
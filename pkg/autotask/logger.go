@@ -1,13 +1,22 @@
 package autotask
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"log/slog"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 )
 
 // Logger handles logging for the Autotask client
+//
+// Deprecated: Logger is kept as an adapter over log/slog so existing callers
+// keep working. Configure the client with SetLogger or SetLogHandler instead.
 type Logger struct {
 	level      LogLevel
 	debugMode  bool
@@ -16,6 +25,8 @@ type Logger struct {
 }
 
 // New creates a new logger
+//
+// Deprecated: use a *slog.Logger with Client.SetLogger instead.
 func New(level LogLevel, debugMode bool) *Logger {
 	return &Logger{
 		level:      level,
@@ -40,6 +51,12 @@ func (l *Logger) SetOutput(output io.Writer) {
 	l.output = output
 }
 
+// Handler returns a slog.Handler that writes through this logger, honoring
+// its level, debug mode and output settings.
+func (l *Logger) Handler() slog.Handler {
+	return &legacyHandler{logger: l}
+}
+
 // Debug logs a debug message
 func (l *Logger) Debug(msg string, fields map[string]interface{}) {
 	if !l.debugMode {
@@ -138,3 +155,241 @@ func (l *Logger) getLevelString(level LogLevel) string {
 		return "UNKNOWN"
 	}
 }
+
+// legacyHandler adapts a Logger to the slog.Handler interface
+type legacyHandler struct {
+	logger *Logger
+	attrs  []slog.Attr
+	group  string
+}
+
+// Enabled reports whether the logger would write a record at the given level
+func (h *legacyHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if level < slog.LevelInfo {
+		return h.logger.debugMode
+	}
+	return logLevelFromSlog(level) >= h.logger.level
+}
+
+// Handle formats the record as a legacy log line
+func (h *legacyHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := make(map[string]interface{}, len(h.attrs)+r.NumAttrs())
+	for _, attr := range h.attrs {
+		addLegacyField(fields, "", attr)
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		addLegacyField(fields, h.group, attr)
+		return true
+	})
+	h.logger.log(logLevelFromSlog(r.Level), r.Message, fields)
+	return nil
+}
+
+// WithAttrs returns a handler that includes the given attributes on every record
+func (h *legacyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	prefixed := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	prefixed = append(prefixed, h.attrs...)
+	for _, attr := range attrs {
+		if h.group != "" {
+			attr.Key = h.group + "." + attr.Key
+		}
+		prefixed = append(prefixed, attr)
+	}
+	return &legacyHandler{logger: h.logger, attrs: prefixed, group: h.group}
+}
+
+// WithGroup returns a handler that qualifies subsequent attributes with name
+func (h *legacyHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	group := name
+	if h.group != "" {
+		group = h.group + "." + name
+	}
+	return &legacyHandler{logger: h.logger, attrs: h.attrs, group: group}
+}
+
+// addLegacyField flattens a slog attribute into a legacy fields map
+func addLegacyField(fields map[string]interface{}, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	key := attr.Key
+	if prefix != "" && key != "" {
+		key = prefix + "." + key
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if key == "" {
+			key = prefix
+		}
+		for _, child := range attr.Value.Group() {
+			addLegacyField(fields, key, child)
+		}
+		return
+	}
+
+	fields[key] = attr.Value.Any()
+}
+
+// logLevelFromSlog maps a slog level onto the legacy LogLevel scale
+func logLevelFromSlog(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return LogLevelDebug
+	case level < slog.LevelWarn:
+		return LogLevelInfo
+	case level < slog.LevelError:
+		return LogLevelWarn
+	default:
+		return LogLevelError
+	}
+}
+
+// contextHandler decorates records with the log attributes carried in the
+// caller's context before passing them on to the wrapped handler
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle adds the context's log attributes to the record
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs := logAttrsFromContext(ctx); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a context-aware handler with the given attributes
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a context-aware handler with the given group
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Context keys for logging metadata
+type (
+	logAttrsKey  struct{}
+	requestIDKey struct{}
+	operationKey struct{}
+	attemptKey   struct{}
+)
+
+// operationInfo identifies the entity operation a request belongs to
+type operationInfo struct {
+	entity    string
+	operation string
+}
+
+// WithLogAttrs returns a copy of ctx carrying attrs. Every line the client
+// logs while serving a request made with the returned context includes them.
+func WithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing := logAttrsFromContext(ctx)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, logAttrsKey{}, merged)
+}
+
+// logAttrsFromContext returns the log attributes carried by ctx
+func logAttrsFromContext(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(logAttrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// WithRequestID returns a copy of ctx carrying the given request ID. When no
+// request ID is set the client generates one for each request it sends.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, if any
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// newRequestID generates a random request ID
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// withOperation records the entity and operation a request is made for
+func withOperation(ctx context.Context, entity, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operationInfo{entity: entity, operation: operation})
+}
+
+// operationFromContext returns the entity operation recorded in ctx
+func operationFromContext(ctx context.Context) operationInfo {
+	info, _ := ctx.Value(operationKey{}).(operationInfo)
+	return info
+}
+
+// withAttempt records the attempt number of a request
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// attemptFromContext returns the attempt number recorded in ctx, defaulting to 1
+func attemptFromContext(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptKey{}).(int); ok && attempt > 0 {
+		return attempt
+	}
+	return 1
+}
+
+// requestLogAttrs returns the standard attributes logged for a request
+func requestLogAttrs(ctx context.Context) []any {
+	attrs := make([]any, 0, 4)
+	info := operationFromContext(ctx)
+	if info.entity != "" {
+		attrs = append(attrs, slog.String("entity", info.entity))
+	}
+	if info.operation != "" {
+		attrs = append(attrs, slog.String("operation", info.operation))
+	}
+	if id := RequestIDFromContext(ctx); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	attrs = append(attrs, slog.Int("attempt", attemptFromContext(ctx)))
+	return attrs
+}
+
+// sensitiveHeaders lists the headers that are masked in log output
+var sensitiveHeaders = map[string]bool{
+	"authorization": true,
+	"secret":        true,
+}
+
+// headersForLog flattens headers for logging, masking credentials
+func headersForLog(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for key, values := range header {
+		if len(values) == 0 {
+			continue
+		}
+		if sensitiveHeaders[strings.ToLower(key)] {
+			headers[key] = "[REDACTED]"
+			continue
+		}
+		headers[key] = values[0]
+	}
+	return headers
+}
@@ -2,8 +2,14 @@ package autotask
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"log/slog"
+	"net/http"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -268,3 +274,135 @@ func TestGetLevelString(t *testing.T) {
 		})
 	}
 }
+
+func TestLegacyHandler(t *testing.T) {
+	t.Run("writes through the legacy logger", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		logger := New(LogLevelInfo, false)
+		logger.SetOutput(buffer)
+
+		slog.New(logger.Handler()).With("entity", "Tickets").WithGroup("http").Info("test slog message", "status_code", 200)
+
+		output := buffer.String()
+		if !strings.Contains(output, "INFO") || !strings.Contains(output, "test slog message") || !strings.Contains(output, "entity:Tickets") || !strings.Contains(output, "http.status_code:200") {
+			t.Errorf("Expected legacy formatted message with attributes, got: %s", output)
+		}
+	})
+
+	t.Run("honors level and debug mode", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		logger := New(LogLevelWarn, false)
+		logger.SetOutput(buffer)
+
+		slogger := slog.New(logger.Handler())
+		slogger.Debug("debug message")
+		slogger.Info("info message")
+
+		if output := buffer.String(); output != "" {
+			t.Errorf("Expected no output below the configured level, got: %s", output)
+		}
+
+		logger.SetDebugMode(true)
+		slogger.Debug("debug message")
+
+		if output := buffer.String(); !strings.Contains(output, "DEBUG") {
+			t.Errorf("Expected debug output in debug mode, got: %s", output)
+		}
+	})
+}
+
+func TestClientSetLogHandler(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/Tickets/123", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 123}})
+	})
+
+	buffer := &bytes.Buffer{}
+	client := server.NewTestClient()
+	client.SetLogHandler(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	ctx := WithLogAttrs(context.Background(), slog.String("job", "nightly-sync"))
+	ctx = WithRequestID(ctx, "req-42")
+
+	_, err := client.Tickets().Get(ctx, 123)
+	AssertNil(t, err, "error should be nil")
+
+	var response map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("failed to parse log line %q: %v", line, err)
+		}
+		if record["msg"] == "HTTP Response" {
+			response = record
+		}
+		if record["msg"] == "HTTP Request" {
+			headers := record["headers"].(map[string]interface{})
+			AssertEqual(t, "[REDACTED]", headers["Secret"], "secret header should be redacted")
+			AssertEqual(t, "[REDACTED]", headers["Authorization"], "authorization header should be redacted")
+		}
+	}
+
+	if response == nil {
+		t.Fatalf("expected an HTTP Response log line, got: %s", buffer.String())
+	}
+	AssertEqual(t, "Tickets", response["entity"], "entity attribute should match")
+	AssertEqual(t, "get", response["operation"], "operation attribute should match")
+	AssertEqual(t, "req-42", response["request_id"], "request ID attribute should match")
+	AssertEqual(t, float64(1), response["attempt"], "attempt attribute should match")
+	AssertEqual(t, "nightly-sync", response["job"], "context attribute should match")
+	AssertNotNil(t, response["duration"], "duration attribute should be set")
+}
+
+func TestClientGeneratesRequestID(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/Companies/1", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 1}})
+	})
+
+	buffer := &bytes.Buffer{}
+	client := server.NewTestClient()
+	client.SetLogger(slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	_, err := client.Companies().Get(context.Background(), 1)
+	AssertNil(t, err, "error should be nil")
+
+	AssertContains(t, buffer.String(), "request_id=", "log output should contain a generated request ID")
+	AssertNotContains(t, buffer.String(), "test-secret", "log output should not contain the API secret")
+}
+
+func TestClientSetLoggerConcurrent(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/Companies/1", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 1}})
+	})
+
+	client := server.NewTestClient()
+	discard := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// Swapping the logger while requests are logged must not race
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := client.Companies().Get(context.Background(), 1)
+			AssertNil(t, err, "error should be nil")
+		}()
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				client.SetLogger(discard)
+			} else {
+				client.SetLogger(nil)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,241 @@
+package autotask
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/asachs01/autotask-go/internal/telemetry"
+)
+
+// RoundTrip sends a single API request and returns the raw response
+type RoundTrip func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTrip with cross-cutting behavior such as auditing,
+// custom headers, signing or metrics. A middleware may modify the request,
+// short-circuit the call, or inspect the response before returning it.
+type Middleware func(next RoundTrip) RoundTrip
+
+// chain composes middleware around a RoundTrip. The first middleware is the
+// outermost and sees the request first.
+func chain(rt RoundTrip, middleware ...Middleware) RoundTrip {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			rt = middleware[i](rt)
+		}
+	}
+	return rt
+}
+
+// loggerKey is the context key for the client logger used by middleware
+type loggerKey struct{}
+
+// withLogger attaches the client's logger to ctx for use by middleware
+func withLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// loggerFromContext returns the client logger carried by ctx, falling back to slog's default
+func loggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
+
+// LoggingMiddleware logs each request and response with the request's
+// entity, operation, request ID, attempt and duration. A nil logger logs
+// through the client's configured logger.
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			l := logger
+			if l == nil {
+				l = loggerFromContext(ctx)
+			}
+			l = l.With(requestLogAttrs(ctx)...)
+
+			l.DebugContext(ctx, "HTTP Request",
+				"method", req.Method,
+				"url", req.URL.String(),
+				"headers", headersForLog(req.Header),
+			)
+
+			start := time.Now()
+			resp, err := next(req)
+			duration := time.Since(start)
+			if err != nil {
+				l.ErrorContext(ctx, "HTTP request failed",
+					"method", req.Method,
+					"url", req.URL.String(),
+					"duration", duration,
+					"error", err,
+				)
+				return nil, err
+			}
+
+			l.DebugContext(ctx, "HTTP Response",
+				"status_code", resp.StatusCode,
+				"duration", duration,
+				"headers", headersForLog(resp.Header),
+			)
+			return resp, nil
+		}
+	}
+}
+
+// RateLimitMiddleware waits on the given rate limiter before each request
+func RateLimitMiddleware(limiter *RateLimiter) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(req *http.Request) (*http.Response, error) {
+			if limiter != nil {
+				if wait := limiter.Wait(); wait > 0 {
+					ctx := req.Context()
+					loggerFromContext(ctx).DebugContext(ctx, "Rate limit applied",
+						append(requestLogAttrs(ctx), "wait", wait)...)
+				}
+			}
+			return next(req)
+		}
+	}
+}
+
+// RetryMiddleware retries requests that fail with a retryable status code
+// using exponential backoff. Transport errors and server errors are only
+// retried for idempotent methods and queries, since the server may have
+// applied a create before failing, so a create is never sent twice; 429
+// responses are rejected before processing and are retried for any method. A
+// Retry-After header on the response overrides the computed interval. A nil
+// config uses DefaultRetryConfig.
+func RetryMiddleware(config *RetryConfig) Middleware {
+	if config == nil {
+		config = DefaultRetryConfig()
+	}
+
+	return func(next RoundTrip) RoundTrip {
+		return func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			interval := config.InitialInterval
+
+			for attempt := 1; ; attempt++ {
+				attemptReq, err := requestForAttempt(req, attempt)
+				if err != nil {
+					return nil, err
+				}
+
+				resp, err := next(attemptReq)
+				if attempt >= config.MaxRetries || !shouldRetry(req, resp, err) {
+					return resp, err
+				}
+
+				interval = nextBackoff(config, interval)
+				if retryAfter := retryAfterDelay(resp); retryAfter > 0 {
+					interval = retryAfter
+				}
+
+				// Discard the failed response before trying again
+				if resp != nil {
+					_, _ = io.Copy(io.Discard, resp.Body)
+					_ = resp.Body.Close()
+				}
+
+				loggerFromContext(ctx).DebugContext(ctx, "Retrying request",
+					append(requestLogAttrs(attemptReq.Context()), "wait", interval)...)
+
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(interval):
+				}
+			}
+		}
+	}
+}
+
+// requestForAttempt clones req for the given attempt with a fresh body
+func requestForAttempt(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 {
+		return req.WithContext(withAttempt(req.Context(), attempt)), nil
+	}
+
+	clone := req.Clone(withAttempt(req.Context(), attempt))
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// shouldRetry reports whether a request should be sent again
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body cannot be replayed
+		return false
+	}
+	repeatable := isIdempotent(req.Method) || isQueryRequest(req)
+	if err != nil {
+		return repeatable
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return repeatable && IsRetryable(&ErrorResponse{Response: resp})
+}
+
+// isIdempotent reports whether repeating a request with method has no additional effect
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfterDelay parses the Retry-After header of a response
+func retryAfterDelay(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// TelemetryMiddleware records an OpenTelemetry span and request metrics for
+// each request using the globally registered tracer and meter providers.
+func TelemetryMiddleware() Middleware {
+	t := telemetry.New(true)
+
+	return func(next RoundTrip) RoundTrip {
+		return func(req *http.Request) (*http.Response, error) {
+			ctx, span := t.StartRequestSpan(req.Context(), req.Method, req.URL.String())
+
+			resp, err := next(req.WithContext(ctx))
+
+			statusCode := 0
+			if resp != nil {
+				statusCode = resp.StatusCode
+			}
+			t.EndRequestSpan(ctx, span, req.Method, statusCode, err)
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package autotask
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/Tickets/1", func(w http.ResponseWriter, r *http.Request) {
+		AssertEqual(t, "42", r.Header.Get("ImpersonationResourceId"), "middleware header should be sent")
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 1}})
+	})
+
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next RoundTrip) RoundTrip {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+":before")
+				resp, err := next(req)
+				calls = append(calls, name+":after")
+				return resp, err
+			}
+		}
+	}
+
+	client := server.NewTestClient()
+	client.Use(record("outer"), func(next RoundTrip) RoundTrip {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("ImpersonationResourceId", "42")
+			return next(req)
+		}
+	}, record("inner"))
+
+	_, err := client.Tickets().Get(context.Background(), 1)
+	AssertNil(t, err, "error should be nil")
+
+	expected := []string{"outer:before", "inner:before", "inner:after", "outer:after"}
+	AssertEqual(t, len(expected), len(calls), "all middleware should run")
+	for i := range expected {
+		AssertEqual(t, expected[i], calls[i], "middleware should run in order")
+	}
+	AssertEqual(t, 2+3, len(client.Middleware()), "Use should append to the built-in middleware")
+}
+
+func TestSetMiddlewareShortCircuit(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/Tickets/1", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach the server")
+	})
+
+	client := server.NewTestClient()
+	client.SetMiddleware(func(next RoundTrip) RoundTrip {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"item":{"id":1,"title":"stubbed"}}`)),
+				Request:    req,
+			}, nil
+		}
+	})
+
+	item, err := client.Tickets().Get(context.Background(), 1)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, "stubbed", item.(map[string]interface{})["title"], "stubbed response should be returned")
+	AssertEqual(t, 1, len(client.Middleware()), "SetMiddleware should replace the chain")
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	t.Run("retries retryable status and replays the body", func(t *testing.T) {
+		server := NewMockServer(t)
+		defer server.Close()
+
+		attempts := 0
+		server.AddHandler("/Tickets/1", func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			body, _ := io.ReadAll(r.Body)
+			AssertContains(t, string(body), "Retried", "request body should be replayed")
+			if attempts < 3 {
+				server.RespondWithError(w, http.StatusTooManyRequests, "slow down", nil)
+				return
+			}
+			server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 1}})
+		})
+
+		client := server.NewTestClient()
+		client.SetMiddleware(RetryMiddleware(&RetryConfig{
+			MaxRetries:      3,
+			InitialInterval: time.Millisecond,
+			MaxInterval:     5 * time.Millisecond,
+			Multiplier:      2,
+		}))
+
+		_, err := client.Tickets().Update(context.Background(), 1, map[string]string{"title": "Retried"})
+		AssertNil(t, err, "error should be nil")
+		AssertEqual(t, 3, attempts, "request should be attempted three times")
+	})
+
+	t.Run("returns the last response when retries are exhausted", func(t *testing.T) {
+		server := NewMockServer(t)
+		defer server.Close()
+
+		attempts := 0
+		server.AddHandler("/Tickets/1", func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			server.RespondWithError(w, http.StatusTooManyRequests, "slow down", []string{"rate limited"})
+		})
+
+		client := server.NewTestClient()
+		client.SetMiddleware(RetryMiddleware(&RetryConfig{
+			MaxRetries:      2,
+			InitialInterval: time.Millisecond,
+			MaxInterval:     time.Millisecond,
+			Multiplier:      1,
+		}))
+
+		_, err := client.Tickets().Get(context.Background(), 1)
+		errResp, ok := err.(*ErrorResponse)
+		AssertTrue(t, ok, "error should be an *ErrorResponse")
+		AssertEqual(t, http.StatusTooManyRequests, errResp.Response.StatusCode, "status code should match")
+		AssertEqual(t, 2, attempts, "request should be attempted twice")
+	})
+
+	t.Run("does not retry creates on server errors", func(t *testing.T) {
+		server := NewMockServer(t)
+		defer server.Close()
+
+		creates, queries := 0, 0
+		server.AddHandler("/Tickets", func(w http.ResponseWriter, r *http.Request) {
+			creates++
+			server.RespondWithError(w, http.StatusBadGateway, "bad gateway", nil)
+		})
+		server.AddHandler("/Tickets/query", func(w http.ResponseWriter, r *http.Request) {
+			queries++
+			server.RespondWithError(w, http.StatusBadGateway, "bad gateway", nil)
+		})
+
+		client := server.NewTestClient()
+		client.SetMiddleware(RetryMiddleware(&RetryConfig{
+			MaxRetries:      3,
+			InitialInterval: time.Millisecond,
+			MaxInterval:     time.Millisecond,
+			Multiplier:      1,
+		}))
+		ctx := context.Background()
+
+		req, err := client.NewRequest(ctx, http.MethodPost, "Tickets", map[string]string{"title": "Printer"})
+		AssertNil(t, err, "error should be nil")
+		_, err = client.Do(req, nil)
+		AssertNotNil(t, err, "error should not be nil")
+		AssertEqual(t, 1, creates, "a create the server may have applied should not be sent again")
+
+		req, err = client.NewRequest(ctx, http.MethodPost, "Tickets/query", NewEntityQueryParams(nil))
+		AssertNil(t, err, "error should be nil")
+		_, err = client.Do(req, nil)
+		AssertNotNil(t, err, "error should not be nil")
+		AssertEqual(t, 3, queries, "queries sent with POST should be retried")
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		server := NewMockServer(t)
+		defer server.Close()
+
+		attempts := 0
+		server.AddHandler("/Tickets/1", func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			server.RespondWithError(w, http.StatusBadRequest, "bad", nil)
+		})
+
+		client := server.NewTestClient()
+		client.SetMiddleware(RetryMiddleware(nil))
+
+		_, err := client.Tickets().Get(context.Background(), 1)
+		AssertNotNil(t, err, "error should not be nil")
+		AssertEqual(t, 1, attempts, "request should be attempted once")
+	})
+}
+
+func TestIsIdempotent(t *testing.T) {
+	AssertTrue(t, isIdempotent(http.MethodGet), "GET should be idempotent")
+	AssertTrue(t, isIdempotent(http.MethodDelete), "DELETE should be idempotent")
+	AssertFalse(t, isIdempotent(http.MethodPost), "POST should not be idempotent")
+	AssertFalse(t, isIdempotent(http.MethodPatch), "PATCH should not be idempotent")
+}
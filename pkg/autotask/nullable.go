@@ -0,0 +1,127 @@
+package autotask
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Nullable is an entity field that tells apart a field that is absent, one
+// that is null and one that has a value, including the zero value. With
+// omitzero an absent field is left out of request bodies, a null field is
+// sent as null and a value is always sent, so false and 0 can be set.
+//
+// The zero Nullable is absent. A Nullable is a plain value: copies are
+// independent, and structs holding one can be compared with == when T can.
+type Nullable[T any] struct {
+	value T
+	state nullableState
+}
+
+// nullableState is whether a Nullable is absent, null or holds a value
+type nullableState uint8
+
+const (
+	nullableAbsent nullableState = iota
+	nullableNull
+	nullableSet
+)
+
+// NullableOf returns a Nullable holding v
+func NullableOf[T any](v T) Nullable[T] {
+	return Nullable[T]{value: v, state: nullableSet}
+}
+
+// Null returns a Nullable that is sent as null
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{state: nullableNull}
+}
+
+// NullableFromPtr returns a Nullable holding *p, or null when p is nil
+func NullableFromPtr[T any](p *T) Nullable[T] {
+	if p == nil {
+		return Null[T]()
+	}
+	return NullableOf(*p)
+}
+
+// Get returns the value and whether there is one
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.state == nullableSet
+}
+
+// ValueOr returns the value, or def when the field is absent or null
+func (n Nullable[T]) ValueOr(def T) T {
+	if n.state == nullableSet {
+		return n.value
+	}
+	return def
+}
+
+// Ptr returns a pointer to a copy of the value, or nil when the field is absent or null
+func (n Nullable[T]) Ptr() *T {
+	if n.state == nullableSet {
+		v := n.value
+		return &v
+	}
+	return nil
+}
+
+// IsSet reports whether the field is present, either null or with a value
+func (n Nullable[T]) IsSet() bool {
+	return n.state != nullableAbsent
+}
+
+// IsNull reports whether the field is present and null
+func (n Nullable[T]) IsNull() bool {
+	return n.state == nullableNull
+}
+
+// IsZero reports whether the field is absent, so omitzero leaves it out
+func (n Nullable[T]) IsZero() bool {
+	return n.state == nullableAbsent
+}
+
+// Set stores v
+func (n *Nullable[T]) Set(v T) {
+	*n = NullableOf(v)
+}
+
+// SetNull makes the field null
+func (n *Nullable[T]) SetNull() {
+	*n = Null[T]()
+}
+
+// Unset makes the field absent
+func (n *Nullable[T]) Unset() {
+	*n = Nullable[T]{}
+}
+
+// String formats the value, or "null" when there is none
+func (n Nullable[T]) String() string {
+	if n.state == nullableSet {
+		return fmt.Sprint(n.value)
+	}
+	return "null"
+}
+
+// MarshalJSON encodes the value, or null when there is none
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.state == nullableSet {
+		return json.Marshal(n.value)
+	}
+	return []byte("null"), nil
+}
+
+// UnmarshalJSON decodes a value or null
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		n.SetNull()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	n.Set(v)
+	return nil
+}
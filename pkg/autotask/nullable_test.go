@@ -0,0 +1,68 @@
+package autotask
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNullableJSON(t *testing.T) {
+	var ticket Ticket
+	err := json.Unmarshal([]byte(`{"id":1,"dueDateTime":null,"assignedResourceID":0}`), &ticket)
+	AssertNil(t, err, "error should be nil")
+
+	AssertTrue(t, ticket.DueDateTime.IsSet(), "null field should be set")
+	AssertTrue(t, ticket.DueDateTime.IsNull(), "null field should be null")
+	id, ok := ticket.AssignedResourceID.Get()
+	AssertTrue(t, ok, "zero value should be kept")
+	AssertEqual(t, int64(0), id, "zero value should be kept")
+	AssertFalse(t, ticket.ContactID.IsSet(), "missing field should be absent")
+	AssertEqual(t, int64(7), ticket.ContactID.ValueOr(7), "absent field should use the default")
+
+	data, err := json.Marshal(Ticket{
+		ID:                 1,
+		DueDateTime:        Null[DateTime](),
+		AssignedResourceID: NullableOf(int64(0)),
+	})
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, `{"id":1,"dueDateTime":null,"assignedResourceID":0}`, string(data), "absent fields should be omitted and null and zero sent")
+}
+
+func TestNullableHelpers(t *testing.T) {
+	var active Nullable[bool]
+	AssertTrue(t, active.Ptr() == nil, "absent field should have no pointer")
+	AssertEqual(t, "null", active.String(), "absent field should format as null")
+
+	active.Set(false)
+	AssertEqual(t, false, *active.Ptr(), "pointer should hold the value")
+	AssertEqual(t, "false", active.String(), "value should be formatted")
+
+	active.SetNull()
+	AssertTrue(t, active.IsNull(), "field should be null")
+
+	active.Unset()
+	AssertFalse(t, active.IsSet(), "field should be absent")
+
+	AssertTrue(t, NullableFromPtr[bool](nil).IsNull(), "nil pointer should be null")
+	v := true
+	AssertEqual(t, true, NullableFromPtr(&v).ValueOr(false), "pointer value should be kept")
+}
+
+func TestNullableCopy(t *testing.T) {
+	original := Ticket{ID: 1, ContactID: NullableOf(int64(5)), AssignedResourceID: NullableOf(int64(9))}
+
+	copied := original
+	copied.ContactID.Set(6)
+	copied.AssignedResourceID.SetNull()
+	copied.DueDateTime.Set(DateTime{})
+
+	AssertEqual(t, int64(5), original.ContactID.ValueOr(0), "setting a copy should not change the original")
+	AssertEqual(t, int64(9), original.AssignedResourceID.ValueOr(0), "nulling a copy should not change the original")
+	AssertFalse(t, original.DueDateTime.IsSet(), "setting an absent field on a copy should not change the original")
+
+	copied = original
+	copied.ContactID.Unset()
+	AssertTrue(t, original.ContactID.IsSet(), "unsetting a copy should not change the original")
+
+	AssertTrue(t, NullableOf(int64(5)) == original.ContactID, "equal values should compare equal")
+	AssertFalse(t, Null[int64]() == Nullable[int64]{}, "null should not equal absent")
+}
@@ -31,7 +31,7 @@ func NewPaginationIterator(ctx context.Context, service EntityService, filter st
 		currentPage:  1,
 		pageSize:     pageSize,
 		currentIndex: -1,
-		ctx:          ctx,
+		ctx:          withOperation(ctx, service.GetEntityName(), "query"),
 	}
 
 	// Load the first page
@@ -180,6 +180,7 @@ func DefaultPaginationOptions() PaginationOptions {
 // FetchAllPages is a convenience method to fetch all pages of results
 // This is useful when you need all results and don't want to manually handle pagination
 func FetchAllPages[T any](ctx context.Context, service EntityService, filter string) ([]T, error) {
+	ctx = withOperation(ctx, service.GetEntityName(), "query")
 	var allItems []T
 	var nextPageUrl string
 	pageSize := 100 // Default page size
@@ -235,6 +236,10 @@ func FetchAllPages[T any](ctx context.Context, service EntityService, filter str
 			return nil, fmt.Errorf("failed to create request for next page: %w", err)
 		}
 
+		// Reset the response so fields missing from this page, such as a
+		// null nextPageUrl, aren't carried over from the previous one
+		response.Items = nil
+		response.PageDetails = PageDetails{}
 		_, err = service.GetClient().Do(req, &response)
 		if err != nil {
 			return nil, fmt.Errorf("query failed: %w", err)
@@ -257,6 +262,7 @@ func FetchAllPagesWithCallback[T any](
 	filter string,
 	callback func(items []T, pageDetails PageDetails) error,
 ) error {
+	ctx = withOperation(ctx, service.GetEntityName(), "query")
 	var nextPageUrl string
 	pageSize := 100  // Default page size
 	currentPage := 1 // Start with first page
@@ -320,6 +326,10 @@ func FetchAllPagesWithCallback[T any](
 			return fmt.Errorf("failed to create request for next page: %w", err)
 		}
 
+		// Reset the response so fields missing from this page, such as a
+		// null nextPageUrl, aren't carried over from the previous one
+		response.Items = nil
+		response.PageDetails = PageDetails{}
 		_, err = service.GetClient().Do(req, &response)
 		if err != nil {
 			return fmt.Errorf("query failed: %w", err)
@@ -351,6 +361,7 @@ func FetchPage[T any](
 	filter string,
 	options PaginationOptions,
 ) (*PaginatedResults[T], error) {
+	ctx = withOperation(ctx, service.GetEntityName(), "query")
 	// Create a response structure
 	var response PaginatedResults[T]
 
@@ -402,6 +413,9 @@ func FetchPage[T any](
 			return nil, fmt.Errorf("failed to create request for page %d: %w", currentPage+1, err)
 		}
 
+		// Reset the response so fields missing from this page aren't
+		// carried over from the previous one
+		response = PaginatedResults[T]{}
 		_, err = service.GetClient().Do(req, &response)
 		if err != nil {
 			return nil, fmt.Errorf("query failed for page %d: %w", currentPage+1, err)
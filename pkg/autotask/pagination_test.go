@@ -426,6 +426,35 @@ func TestFetchAllPagesWithCallback(t *testing.T) {
 	AssertEqual(t, 100, totalItems, "total items should be 100")
 }
 
+func TestFetchAllPagesStopsAtNullNextPage(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	requests := 0
+	server.AddHandler("/TestEntities/query", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 2 {
+			server.RespondWithError(w, http.StatusBadRequest, "last page requested again", nil)
+			return
+		}
+		next := interface{}("/TestEntities/query?page=2")
+		if r.URL.Query().Get("page") == "2" {
+			next = nil
+		}
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"items":       CreateMockEntities(2, "TestEntity", int64(requests*10)),
+			"pageDetails": map[string]interface{}{"nextPageUrl": next},
+		})
+	})
+
+	client := server.NewTestClient()
+	base := NewBaseEntityService(client, "TestEntities")
+
+	items, err := FetchAllPages[map[string]interface{}](context.Background(), &base, "")
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 4, len(items), "both pages should be fetched once")
+}
+
 func TestDefaultPaginationOptions(t *testing.T) {
 	// Test default options
 	options := DefaultPaginationOptions()
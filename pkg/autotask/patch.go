@@ -0,0 +1,224 @@
+package autotask
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Patch is a partial update to an entity of type T. Only the fields that
+// were set, cleared or found changed by Diff are sent, so fields the patch
+// doesn't name are left alone, and false, zero and empty values are sent
+// explicitly instead of being dropped by omitempty.
+type Patch[T any] struct {
+	fields map[string]interface{}
+	errs   []error
+}
+
+// NewPatch returns an empty patch for T
+func NewPatch[T any]() *Patch[T] {
+	return &Patch[T]{fields: make(map[string]interface{})}
+}
+
+// Set sets a field, named as in the API or T's json tags, to value. A nil
+// value clears the field. Unknown fields and values that don't fit the
+// field's type are reported by Err.
+func (p *Patch[T]) Set(name string, value interface{}) *Patch[T] {
+	field, ok := p.lookup(name)
+	if !ok {
+		return p
+	}
+	if value != nil && field.typ != nil {
+		if err := checkPatchValue(value, field.typ); err != nil {
+			p.errs = append(p.errs, fmt.Errorf("invalid value for %s: %w", field.name, err))
+			return p
+		}
+	}
+	p.fields[field.name] = value
+	return p
+}
+
+// Clear sets a field to null
+func (p *Patch[T]) Clear(name string) *Patch[T] {
+	return p.Set(name, nil)
+}
+
+// Fields returns the field values the patch sends, with nil for cleared fields
+func (p *Patch[T]) Fields() map[string]interface{} {
+	fields := make(map[string]interface{}, len(p.fields))
+	for name, value := range p.fields {
+		fields[name] = value
+	}
+	return fields
+}
+
+// FieldNames returns the names of the fields the patch sends, sorted
+func (p *Patch[T]) FieldNames() []string {
+	names := make([]string, 0, len(p.fields))
+	for name := range p.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p *Patch[T]) IsEmpty() bool {
+	return len(p.fields) == 0
+}
+
+// Err returns the problems found while building the patch
+func (p *Patch[T]) Err() error {
+	return errors.Join(p.errs...)
+}
+
+// MarshalJSON encodes the patch as a PATCH body, so a patch can also be
+// passed to an entity service's Update
+func (p *Patch[T]) MarshalJSON() ([]byte, error) {
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(p.fields)
+}
+
+// lookup resolves a field name against T, recording an error for unknown fields
+func (p *Patch[T]) lookup(name string) (patchField, bool) {
+	if p.fields == nil {
+		p.fields = make(map[string]interface{})
+	}
+
+	fields := patchFieldsOf(reflect.TypeOf((*T)(nil)).Elem())
+	if fields == nil {
+		// T isn't a struct, so any field name is accepted as given
+		return patchField{name: name}, true
+	}
+	field, ok := fields[strings.ToLower(name)]
+	if !ok {
+		p.errs = append(p.errs, fmt.Errorf("unknown field %s", name))
+		return patchField{}, false
+	}
+	return field, true
+}
+
+// Diff returns a patch with the fields that differ between original and
+// modified. Changed fields are sent with their new value, including false
+// and zero; empty strings and nil pointers, slices and maps are sent as null
+// to clear the field. Nullable fields that are absent in modified are left
+// out.
+func Diff[T any](original, modified T) (*Patch[T], error) {
+	p := NewPatch[T]()
+
+	fields := patchFieldsOf(reflect.TypeOf((*T)(nil)).Elem())
+	if fields == nil {
+		return nil, fmt.Errorf("cannot diff %T: not a struct", original)
+	}
+
+	before := reflect.ValueOf(&original).Elem()
+	after := reflect.ValueOf(&modified).Elem()
+	for _, field := range fields {
+		if strings.EqualFold(field.name, "id") {
+			continue
+		}
+		a := before.FieldByIndex(field.index)
+		b := after.FieldByIndex(field.index)
+		if reflect.DeepEqual(a.Interface(), b.Interface()) {
+			continue
+		}
+		if n, ok := b.Interface().(interface{ IsSet() bool }); ok && !n.IsSet() {
+			// An absent Nullable leaves the field alone
+			continue
+		}
+		if isClearingValue(b) {
+			p.fields[field.name] = nil
+		} else {
+			p.fields[field.name] = b.Interface()
+		}
+	}
+	return p, nil
+}
+
+// ApplyPatch sends a patch to the entity with the given ID. An empty patch
+// sends nothing.
+func ApplyPatch[T any](ctx context.Context, service EntityService, id int64, patch *Patch[T]) error {
+	if err := patch.Err(); err != nil {
+		return fmt.Errorf("invalid %s patch: %w", service.GetEntityName(), err)
+	}
+	if patch.IsEmpty() {
+		return nil
+	}
+
+	body := patch.Fields()
+	body["id"] = id
+	if _, err := service.Update(ctx, id, body); err != nil {
+		return fmt.Errorf("failed to patch %s %d: %w", service.GetEntityName(), id, err)
+	}
+	return nil
+}
+
+// patchField is a struct field that a patch can set
+type patchField struct {
+	name  string
+	index []int
+	typ   reflect.Type
+}
+
+// patchFieldsOf returns the JSON fields of a struct type keyed by lowercase
+// name, or nil when t isn't a struct
+func patchFieldsOf(t reflect.Type) map[string]patchField {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := make(map[string]patchField)
+	collectPatchFields(t, nil, fields)
+	return fields
+}
+
+// collectPatchFields adds the JSON fields of t, including those of embedded structs
+func collectPatchFields(t reflect.Type, index []int, fields map[string]patchField) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			collectPatchFields(sf.Type, fieldIndex, fields)
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields[strings.ToLower(name)] = patchField{name: name, index: fieldIndex, typ: sf.Type}
+	}
+}
+
+// checkPatchValue reports whether value can be decoded into a field of type t
+func checkPatchValue(value interface{}, t reflect.Type) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, reflect.New(t).Interface())
+}
+
+// isClearingValue reports whether a changed field value should be sent as null
+func isClearingValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package autotask
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPatchSetAndClear(t *testing.T) {
+	patch := NewPatch[Ticket]().
+		Set("Status", 5).
+		Set("priority", 0).
+		Clear("dueDateTime")
+
+	AssertNil(t, patch.Err(), "error should be nil")
+	AssertEqual(t, "dueDateTime,priority,status", strings.Join(patch.FieldNames(), ","), "fields should use API names")
+
+	data, err := json.Marshal(patch)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, `{"dueDateTime":null,"priority":0,"status":5}`, string(data), "zero values and nulls should be sent")
+
+	patch = NewPatch[Ticket]().Set("nope", 1).Set("status", "closed")
+	AssertNotNil(t, patch.Err(), "unknown fields and mistyped values should be reported")
+	AssertTrue(t, patch.IsEmpty(), "invalid changes should not be kept")
+}
+
+func TestDiff(t *testing.T) {
+	original := Company{ID: 1, CompanyName: "Acme", Phone: "555-0100", Active: NullableOf(true), TerritoryID: 3}
+	modified := original
+	modified.Active = NullableOf(false)
+	modified.Phone = ""
+	modified.TerritoryID = 4
+
+	patch, err := Diff(original, modified)
+	AssertNil(t, err, "error should be nil")
+
+	data, err := json.Marshal(patch)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, `{"active":false,"phone":null,"territoryID":4}`, string(data), "only changed fields should be sent")
+
+	unchanged, err := Diff(original, original)
+	AssertNil(t, err, "error should be nil")
+	AssertTrue(t, unchanged.IsEmpty(), "identical entities should produce an empty patch")
+}
+
+func TestApplyPatch(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	var body map[string]interface{}
+	requests := 0
+	server.AddHandler("/Companies/1", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		AssertEqual(t, http.MethodPatch, r.Method, "method should match")
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"itemId": 1})
+	})
+
+	client := server.NewTestClient()
+	ctx := context.Background()
+
+	err := ApplyPatch(ctx, client.Companies(), 1, NewPatch[Company]().Set("active", false))
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 1, requests, "patch should be sent")
+	AssertEqual(t, float64(1), body["id"], "id should be sent")
+	AssertEqual(t, false, body["active"], "false should be sent explicitly")
+	AssertEqual(t, 2, len(body), "only the patched fields should be sent")
+
+	err = ApplyPatch(ctx, client.Companies(), 1, NewPatch[Company]())
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 1, requests, "empty patch should not be sent")
+
+	err = ApplyPatch(ctx, client.Companies(), 1, NewPatch[Company]().Set("missing", 1))
+	AssertNotNil(t, err, "invalid patch should not be sent")
+	AssertEqual(t, 1, requests, "invalid patch should not be sent")
+}
@@ -3,6 +3,7 @@ package autotask
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 )
 
@@ -176,7 +177,7 @@ func parseSimpleFilter(filterStr string) QueryFilter {
 
 	// Handle contains
 	if strings.Contains(strings.ToLower(filterStr), " contains ") {
-		parts := strings.Split(strings.ToLower(filterStr), " contains ")
+		parts := containsPattern.Split(filterStr, -1)
 		if len(parts) == 2 {
 			field := strings.TrimSpace(parts[0])
 			value := strings.TrimSpace(parts[1])
@@ -242,7 +243,7 @@ func parseComplexFilter(filterStr string) interface{} {
 
 	// Check if it's an AND condition
 	if strings.Contains(strings.ToUpper(filterStr), " AND ") {
-		parts := strings.Split(strings.ToUpper(filterStr), " AND ")
+		parts := splitLogical(filterStr, "AND")
 		filters := make([]interface{}, len(parts))
 		for i, part := range parts {
 			filters[i] = parseSimpleFilter(strings.TrimSpace(part))
@@ -252,7 +253,7 @@ func parseComplexFilter(filterStr string) interface{} {
 
 	// Check if it's an OR condition
 	if strings.Contains(strings.ToUpper(filterStr), " OR ") {
-		parts := strings.Split(strings.ToUpper(filterStr), " OR ")
+		parts := splitLogical(filterStr, "OR")
 		filters := make([]interface{}, len(parts))
 		for i, part := range parts {
 			filters[i] = parseSimpleFilter(strings.TrimSpace(part))
@@ -271,7 +272,7 @@ func parseNestedFilter(filterStr string) interface{} {
 
 	// Check if it's an AND condition with nested OR
 	if strings.Contains(strings.ToUpper(filterStr), " AND ") {
-		parts := strings.Split(strings.ToUpper(filterStr), " AND ")
+		parts := splitLogical(filterStr, "AND")
 		filters := make([]interface{}, 0, len(parts))
 
 		for _, part := range parts {
@@ -281,7 +282,7 @@ func parseNestedFilter(filterStr string) interface{} {
 				nestedPart := part[1 : len(part)-1] // Remove parentheses
 				if strings.Contains(strings.ToUpper(nestedPart), " OR ") {
 					// Parse as OR group
-					nestedParts := strings.Split(strings.ToUpper(nestedPart), " OR ")
+					nestedParts := splitLogical(nestedPart, "OR")
 					nestedFilters := make([]interface{}, len(nestedParts))
 					for i, np := range nestedParts {
 						nestedFilters[i] = parseSimpleFilter(strings.TrimSpace(np))
@@ -302,7 +303,7 @@ func parseNestedFilter(filterStr string) interface{} {
 
 	// Check if it's an OR condition with nested AND
 	if strings.Contains(strings.ToUpper(filterStr), " OR ") {
-		parts := strings.Split(strings.ToUpper(filterStr), " OR ")
+		parts := splitLogical(filterStr, "OR")
 		filters := make([]interface{}, 0, len(parts))
 
 		for _, part := range parts {
@@ -312,7 +313,7 @@ func parseNestedFilter(filterStr string) interface{} {
 				nestedPart := part[1 : len(part)-1] // Remove parentheses
 				if strings.Contains(strings.ToUpper(nestedPart), " AND ") {
 					// Parse as AND group
-					nestedParts := strings.Split(strings.ToUpper(nestedPart), " AND ")
+					nestedParts := splitLogical(nestedPart, "AND")
 					nestedFilters := make([]interface{}, len(nestedParts))
 					for i, np := range nestedParts {
 						nestedFilters[i] = parseSimpleFilter(strings.TrimSpace(np))
@@ -335,6 +336,21 @@ func parseNestedFilter(filterStr string) interface{} {
 	return parseSimpleFilter(filterStr)
 }
 
+// logicalOperatorPatterns match the logical operators in filter strings in any case
+var logicalOperatorPatterns = map[string]*regexp.Regexp{
+	"AND": regexp.MustCompile(`(?i)\s+AND\s+`),
+	"OR":  regexp.MustCompile(`(?i)\s+OR\s+`),
+}
+
+// containsPattern matches the contains operator in filter strings in any case
+var containsPattern = regexp.MustCompile(`(?i)\s+contains\s+`)
+
+// splitLogical splits a filter string on a logical operator, keeping the
+// case of field names and values
+func splitLogical(filterStr, operator string) []string {
+	return logicalOperatorPatterns[operator].Split(filterStr, -1)
+}
+
 // Helper functions for type conversion
 func parseInt(s string) int64 {
 	var i int64
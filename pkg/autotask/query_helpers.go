@@ -1,10 +1,11 @@
 package autotask
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	neturl "net/url"
 	"time"
 )
 
@@ -29,20 +30,14 @@ type QueryParams struct {
 
 // Query executes a query against the Autotask API
 func (c *client) Query(ctx context.Context, entityName string, params interface{}, response interface{}) error {
+	ctx = withOperation(ctx, entityName, "query")
 	url := entityName + "/query"
 
-	reqBytes, err := json.Marshal(params)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %w", err)
-	}
-
-	req, err := c.NewRequest(ctx, "POST", url, bytes.NewBuffer(reqBytes))
+	req, err := c.NewRequest(ctx, http.MethodPost, url, params)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
 	_, err = c.Do(req, response)
 	if err != nil {
 		return err
@@ -51,7 +46,9 @@ func (c *client) Query(ctx context.Context, entityName string, params interface{
 	return nil
 }
 
-// QueryWithDateFilter executes a query with a date-based filter
+// QueryWithDateFilter executes a query for entities whose date field is
+// after since. since is sent in UTC in the format the API accepts, whatever
+// its location.
 func (c *client) QueryWithDateFilter(ctx context.Context, entityName, fieldName string, since time.Time) ([]map[string]interface{}, error) {
 	params := QueryParams{
 		MaxRecords: 500,
@@ -62,7 +59,7 @@ func (c *client) QueryWithDateFilter(ctx context.Context, entityName, fieldName
 					{
 						Field: fieldName,
 						Op:    "gt",
-						Value: since.Format(time.RFC3339),
+						Value: NewDateTime(since).String(),
 					},
 				},
 			},
@@ -190,3 +187,69 @@ func (c *client) QueryIDRange(ctx context.Context, entityName string, startID, e
 
 	return response.Items, nil
 }
+
+// QueryByIDs retrieves entities by ID through the Client interface, using
+// "in" queries of up to batchSize IDs like BatchGetEntities. Entities that
+// don't exist are left out of the result.
+func QueryByIDs[T any](ctx context.Context, c Client, entityName string, ids []int64, batchSize int) ([]T, error) {
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+	return QueryByField[T](ctx, c, entityName, "id", values, batchSize)
+}
+
+// QueryByField retrieves the entities whose field matches any of values,
+// using "in" queries of up to batchSize values. Every page of each query's
+// results is fetched, so values matching many entities aren't cut short.
+func QueryByField[T any](ctx context.Context, c Client, entityName, field string, values []interface{}, batchSize int) ([]T, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	if batchSize <= 0 {
+		batchSize = 50 // Default batch size
+	}
+
+	ctx = withOperation(ctx, entityName, "query")
+
+	var all []T
+	for i := 0; i < len(values); i += batchSize {
+		end := i + batchSize
+		if end > len(values) {
+			end = len(values)
+		}
+
+		params := NewEntityQueryParams(NewQueryFilter(field, OperatorIn, values[i:end])).WithMaxRecords(500)
+		searchJSON, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal search params: %w", err)
+		}
+
+		req, err := c.NewRequest(ctx, http.MethodGet, fmt.Sprintf("%s/query?search=%s", entityName, neturl.QueryEscape(string(searchJSON))), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for {
+			var response struct {
+				Items       []T         `json:"items"`
+				PageDetails PageDetails `json:"pageDetails"`
+			}
+			if _, err := c.Do(req, &response); err != nil {
+				return nil, fmt.Errorf("failed to execute batch query: %w", err)
+			}
+			all = append(all, response.Items...)
+
+			next := response.PageDetails.NextPageUrl
+			if next == "" || len(response.Items) == 0 {
+				break
+			}
+			if req, err = c.NewRequest(ctx, http.MethodGet, next, nil); err != nil {
+				return nil, fmt.Errorf("failed to create request for next page: %w", err)
+			}
+		}
+	}
+
+	return all, nil
+}
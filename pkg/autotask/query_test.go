@@ -296,3 +296,30 @@ func TestParseFloat(t *testing.T) {
 	result = parseFloat("not a number")
 	AssertEqual(t, float64(0), result, "result should be 0.0 for invalid input")
 }
+
+func TestParseFilterStringKeepsCase(t *testing.T) {
+	group, ok := ParseFilterString("id>100 and title contains Printer").(FilterGroup)
+	AssertTrue(t, ok, "filter should be a group")
+	AssertLen(t, group.Items, 2, "both conditions should be parsed")
+
+	first := group.Items[0].(QueryFilter)
+	AssertEqual(t, "id", first.Field, "field name case should be kept")
+	AssertEqual(t, OperatorGreaterThan, first.Operator, "operator should match")
+	AssertEqual(t, "100", first.Value, "value should match")
+
+	second := group.Items[1].(QueryFilter)
+	AssertEqual(t, "title", second.Field, "field name case should be kept")
+	AssertEqual(t, "Printer", second.Value, "value case should be kept")
+
+	group, ok = ParseFilterString("status=1 OR companyName=Acme Corp").(FilterGroup)
+	AssertTrue(t, ok, "filter should be a group")
+	AssertEqual(t, LogicalOperatorOr, group.Operator, "operator should be or")
+	AssertEqual(t, "companyName", group.Items[1].(QueryFilter).Field, "field name case should be kept")
+	AssertEqual(t, "Acme Corp", group.Items[1].(QueryFilter).Value, "value case should be kept")
+
+	// Operators only split on whole words, so fields containing them are kept
+	group, ok = ParseFilterString("brandName=Orion and isActive=true").(FilterGroup)
+	AssertTrue(t, ok, "filter should be a group")
+	AssertLen(t, group.Items, 2, "operator inside a word should not split")
+	AssertEqual(t, "Orion", group.Items[0].(QueryFilter).Value, "value should not be split")
+}
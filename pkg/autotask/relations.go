@@ -0,0 +1,384 @@
+package autotask
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Relation is a reference from one entity to another through a foreign key
+// field, such as a ticket's company through companyID
+type Relation struct {
+	// Name is the name passed to Expand, such as "company"
+	Name string
+
+	// Field is the foreign key field holding the related entity's ID
+	Field string
+
+	// Entity is the related entity, such as Companies
+	Entity string
+}
+
+// relationsMu guards relations
+var relationsMu sync.RWMutex
+
+// relations lists the relations of each entity, keyed by lowercase entity name
+var relations = map[string][]Relation{
+	"companies": {
+		{Name: "parentCompany", Field: "parentCompanyID", Entity: "Companies"},
+	},
+	"tickets": {
+		{Name: "company", Field: "companyID", Entity: "Companies"},
+		{Name: "contact", Field: "contactID", Entity: "Contacts"},
+		{Name: "assignedResource", Field: "assignedResourceID", Entity: "Resources"},
+		{Name: "creatorResource", Field: "creatorResourceID", Entity: "Resources"},
+	},
+	"ticketnotes": {
+		{Name: "ticket", Field: "ticketID", Entity: "Tickets"},
+		{Name: "creatorResource", Field: "creatorResourceID", Entity: "Resources"},
+	},
+	"contacts": {
+		{Name: "company", Field: "companyID", Entity: "Companies"},
+	},
+	"projects": {
+		{Name: "company", Field: "companyID", Entity: "Companies"},
+		{Name: "projectLead", Field: "projectLeadResourceID", Entity: "Resources"},
+		{Name: "contract", Field: "contractID", Entity: "Contracts"},
+	},
+	"tasks": {
+		{Name: "project", Field: "projectID", Entity: "Projects"},
+		{Name: "assignedResource", Field: "assignedResourceID", Entity: "Resources"},
+	},
+	"timeentries": {
+		{Name: "resource", Field: "resourceID", Entity: "Resources"},
+		{Name: "ticket", Field: "ticketID", Entity: "Tickets"},
+		{Name: "task", Field: "taskID", Entity: "Tasks"},
+	},
+	"contracts": {
+		{Name: "company", Field: "companyID", Entity: "Companies"},
+	},
+	"configurationitems": {
+		{Name: "company", Field: "companyID", Entity: "Companies"},
+	},
+}
+
+// RegisterRelation adds a relation that Expand can load for an entity, or
+// replaces the one with the same name
+func RegisterRelation(entityName string, relation Relation) {
+	relationsMu.Lock()
+	defer relationsMu.Unlock()
+
+	key := strings.ToLower(entityName)
+	for i, r := range relations[key] {
+		if strings.EqualFold(r.Name, relation.Name) {
+			relations[key][i] = relation
+			return
+		}
+	}
+	relations[key] = append(relations[key], relation)
+}
+
+// Relations returns the relations Expand can load for an entity
+func Relations(entityName string) []Relation {
+	relationsMu.RLock()
+	defer relationsMu.RUnlock()
+	return append([]Relation(nil), relations[strings.ToLower(entityName)]...)
+}
+
+// lookupRelations resolves relation names for an entity, ignoring case
+func lookupRelations(entityName string, names []string) ([]Relation, error) {
+	known := Relations(entityName)
+
+	resolved := make([]Relation, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, r := range known {
+			if strings.EqualFold(r.Name, name) {
+				resolved = append(resolved, r)
+				found = true
+				break
+			}
+		}
+		if !found {
+			available := make([]string, len(known))
+			for i, r := range known {
+				available[i] = r.Name
+			}
+			sort.Strings(available)
+			return nil, fmt.Errorf("%s has no relation %q; available: %s", entityName, name, strings.Join(available, ", "))
+		}
+	}
+	return resolved, nil
+}
+
+// QueryOption configures a query made with QueryWithRelations
+type QueryOption func(*queryOptions)
+
+// queryOptions holds the settings of a query
+type queryOptions struct {
+	expand    []string
+	batchSize int
+}
+
+// Expand loads the named relations of each result, such as "company" and
+// "assignedResource" for tickets. See Relations for the names of an entity.
+func Expand(relations ...string) QueryOption {
+	return func(o *queryOptions) {
+		o.expand = append(o.expand, relations...)
+	}
+}
+
+// ExpandBatchSize sets the most IDs fetched by one related-entity query
+func ExpandBatchSize(size int) QueryOption {
+	return func(o *queryOptions) {
+		o.batchSize = size
+	}
+}
+
+// Expanded is a query result together with its loaded relations
+type Expanded[T any] struct {
+	Item T
+
+	// Related holds the related entities by relation name. Relations whose
+	// foreign key is empty or whose entity doesn't exist are absent.
+	Related map[string]json.RawMessage
+}
+
+// Relation decodes the named related entity into v and reports whether it was loaded
+func (e Expanded[T]) Relation(name string, v interface{}) (bool, error) {
+	data, ok := LookupField(e.Related, name)
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return true, fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// QueryWithRelations fetches every entity matching filter and loads the
+// relations named with Expand. The foreign keys of each result page are
+// collected and each related entity type is fetched with batched "in"
+// queries, so a page costs one query per related type instead of one Get
+// per result. Entities already loaded for an earlier page are reused.
+func QueryWithRelations[T any](ctx context.Context, service EntityService, filter string, opts ...QueryOption) ([]Expanded[T], error) {
+	options := queryOptions{batchSize: DefaultGetBatchSize}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	rels, err := lookupRelations(service.GetEntityName(), options.expand)
+	if err != nil {
+		return nil, err
+	}
+	exp := newExpander(service.GetClient(), rels, options.batchSize)
+
+	var results []Expanded[T]
+	err = FetchAllPagesWithCallback(ctx, service, filter, func(items []json.RawMessage, _ PageDetails) error {
+		related, err := exp.expand(ctx, items)
+		if err != nil {
+			return err
+		}
+		for i, data := range items {
+			var item T
+			if err := json.Unmarshal(data, &item); err != nil {
+				return fmt.Errorf("failed to decode %s: %w", service.GetEntityName(), err)
+			}
+			results = append(results, Expanded[T]{Item: item, Related: related[i]})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ExpandRelations loads the named relations of items, entities of
+// entityName as returned by the API, with batched "in" queries. It returns
+// the related entities of each item by relation name.
+func ExpandRelations(ctx context.Context, c Client, entityName string, items []json.RawMessage, names ...string) ([]map[string]json.RawMessage, error) {
+	rels, err := lookupRelations(entityName, names)
+	if err != nil {
+		return nil, err
+	}
+	return newExpander(c, rels, DefaultGetBatchSize).expand(ctx, items)
+}
+
+// expander loads relations and remembers the entities it fetched
+type expander struct {
+	client    Client
+	relations []Relation
+	batchSize int
+
+	// loaded holds fetched entities by entity name and ID. A nil body
+	// records an ID that doesn't exist.
+	loaded map[string]map[int64]json.RawMessage
+}
+
+// newExpander returns an expander for the given relations
+func newExpander(c Client, rels []Relation, batchSize int) *expander {
+	return &expander{
+		client:    c,
+		relations: rels,
+		batchSize: batchSize,
+		loaded:    make(map[string]map[int64]json.RawMessage),
+	}
+}
+
+// expand fetches the related entities of items, one query batch per
+// related entity type, and returns them for each item by relation name
+func (e *expander) expand(ctx context.Context, items []json.RawMessage) ([]map[string]json.RawMessage, error) {
+	related := make([]map[string]json.RawMessage, len(items))
+	if len(e.relations) == 0 {
+		return related, nil
+	}
+
+	// Collect the foreign keys of every item
+	keys := make([]map[string]int64, len(items))
+	missing := make(map[string]map[int64]bool)
+	for i, data := range items {
+		var values map[string]interface{}
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("failed to decode item: %w", err)
+		}
+		keys[i] = make(map[string]int64, len(e.relations))
+		for _, r := range e.relations {
+			id, ok := foreignKey(lookupFieldValue(values, r.Field))
+			if !ok {
+				continue
+			}
+			keys[i][r.Name] = id
+			if _, done := e.loaded[r.Entity][id]; !done {
+				if missing[r.Entity] == nil {
+					missing[r.Entity] = make(map[int64]bool)
+				}
+				missing[r.Entity][id] = true
+			}
+		}
+	}
+
+	// Fetch each related entity type with batched in queries
+	entities := make([]string, 0, len(missing))
+	for entity := range missing {
+		entities = append(entities, entity)
+	}
+	sort.Strings(entities)
+	for _, entity := range entities {
+		ids := make([]int64, 0, len(missing[entity]))
+		for id := range missing[entity] {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+		fetched, err := QueryByIDs[json.RawMessage](ctx, e.client, entity, ids, e.batchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", entity, err)
+		}
+
+		if e.loaded[entity] == nil {
+			e.loaded[entity] = make(map[int64]json.RawMessage)
+		}
+		for _, id := range ids {
+			e.loaded[entity][id] = nil
+		}
+		for _, data := range fetched {
+			var ref struct {
+				ID int64 `json:"id"`
+			}
+			if json.Unmarshal(data, &ref) == nil {
+				e.loaded[entity][ref.ID] = data
+			}
+		}
+	}
+
+	// Attach the related entities to their items
+	for i := range items {
+		for _, r := range e.relations {
+			id, ok := keys[i][r.Name]
+			if !ok {
+				continue
+			}
+			if data := e.loaded[r.Entity][id]; data != nil {
+				if related[i] == nil {
+					related[i] = make(map[string]json.RawMessage, len(e.relations))
+				}
+				related[i][r.Name] = data
+			}
+		}
+	}
+	return related, nil
+}
+
+// foreignKey converts a decoded foreign key value to an ID. Null and zero
+// values have no related entity.
+func foreignKey(value interface{}) (int64, bool) {
+	var id int64
+	switch v := value.(type) {
+	case float64:
+		id = int64(v)
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return 0, false
+		}
+		id = n
+	default:
+		return 0, false
+	}
+	return id, id > 0
+}
+
+// TicketWithRelations is a ticket with its related entities loaded by
+// TicketsService.QueryWithRelations. Relations that weren't expanded, or
+// whose reference is empty, are nil.
+type TicketWithRelations struct {
+	Ticket
+
+	Company          *Company  `json:"company,omitempty"`
+	Contact          *Contact  `json:"contact,omitempty"`
+	AssignedResource *Resource `json:"assignedResource,omitempty"`
+	CreatorResource  *Resource `json:"creatorResource,omitempty"`
+}
+
+// QueryWithRelations fetches the tickets matching filter with the
+// relations named with Expand: company, contact, assignedResource and
+// creatorResource
+func (s *ticketsService) QueryWithRelations(ctx context.Context, filter string, opts ...QueryOption) ([]TicketWithRelations, error) {
+	results, err := QueryWithRelations[Ticket](ctx, s, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets := make([]TicketWithRelations, len(results))
+	for i, r := range results {
+		tickets[i].Ticket = r.Item
+		if err := decodeRelation(r, "company", &tickets[i].Company); err != nil {
+			return nil, err
+		}
+		if err := decodeRelation(r, "contact", &tickets[i].Contact); err != nil {
+			return nil, err
+		}
+		if err := decodeRelation(r, "assignedResource", &tickets[i].AssignedResource); err != nil {
+			return nil, err
+		}
+		if err := decodeRelation(r, "creatorResource", &tickets[i].CreatorResource); err != nil {
+			return nil, err
+		}
+	}
+	return tickets, nil
+}
+
+// decodeRelation sets *out to the named related entity when it was loaded
+func decodeRelation[T, R any](e Expanded[T], name string, out **R) error {
+	v := new(R)
+	ok, err := e.Relation(name, v)
+	if err != nil || !ok {
+		return err
+	}
+	*out = v
+	return nil
+}
@@ -0,0 +1,77 @@
+package autotask
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestTicketsQueryWithRelations(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	queries := make(map[string][]string)
+	respondByID := func(entity string, items map[float64]map[string]interface{}) func(w http.ResponseWriter, r *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			search := r.URL.Query().Get("search")
+			queries[entity] = append(queries[entity], search)
+
+			var params struct {
+				Filter []QueryFilter `json:"filter"`
+			}
+			if err := json.Unmarshal([]byte(search), &params); err != nil {
+				t.Errorf("invalid search: %v", err)
+			}
+			var found []map[string]interface{}
+			for _, id := range params.Filter[0].Value.([]interface{}) {
+				if item, ok := items[id.(float64)]; ok {
+					found = append(found, item)
+				}
+			}
+			server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"items": found})
+		}
+	}
+
+	server.AddHandler("/Tickets/query", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"items": []map[string]interface{}{
+			{"id": 1, "title": "Printer", "companyID": 10, "contactID": 20, "assignedResourceID": 30, "creatorResourceID": 31},
+			{"id": 2, "title": "Email", "companyID": 10, "contactID": nil, "assignedResourceID": 31},
+			{"id": 3, "title": "VPN", "companyID": 11},
+		}})
+	})
+	server.AddHandler("/Companies/query", respondByID("Companies", map[float64]map[string]interface{}{
+		10: {"id": 10, "companyName": "Acme"},
+	}))
+	server.AddHandler("/Contacts/query", respondByID("Contacts", map[float64]map[string]interface{}{
+		20: {"id": 20, "firstName": "Ada"},
+	}))
+	server.AddHandler("/Resources/query", respondByID("Resources", map[float64]map[string]interface{}{
+		30: {"id": 30, "firstName": "Grace"},
+		31: {"id": 31, "firstName": "Linus"},
+	}))
+
+	client := server.NewTestClient()
+	tickets, err := client.Tickets().QueryWithRelations(context.Background(), "status=1",
+		Expand("company", "contact", "assignedResource", "creatorResource"))
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 3, len(tickets), "every ticket should be returned")
+
+	AssertEqual(t, 1, len(queries["Companies"]), "companies should be fetched with one query")
+	AssertEqual(t, 1, len(queries["Contacts"]), "contacts should be fetched with one query")
+	AssertEqual(t, 1, len(queries["Resources"]), "relations to the same entity should share a query")
+	AssertTrue(t, strings.Contains(queries["Companies"][0], `"value":[10,11]`), "company IDs should be collected without duplicates")
+
+	AssertEqual(t, "Printer", tickets[0].Title, "ticket fields should be decoded")
+	AssertEqual(t, "Acme", tickets[0].Company.CompanyName, "company should be attached")
+	AssertEqual(t, "Ada", tickets[0].Contact.FirstName, "contact should be attached")
+	AssertEqual(t, "Grace", tickets[0].AssignedResource.FirstName, "assigned resource should be attached")
+	AssertEqual(t, "Linus", tickets[0].CreatorResource.FirstName, "creator resource should be attached")
+	AssertEqual(t, "Acme", tickets[1].Company.CompanyName, "shared company should be attached to every ticket")
+	AssertTrue(t, tickets[1].Contact == nil, "null reference should not be attached")
+	AssertTrue(t, tickets[2].Company == nil, "missing company should not be attached")
+
+	_, err = client.Tickets().QueryWithRelations(context.Background(), "status=1", Expand("queue"))
+	AssertNotNil(t, err, "unknown relation should be rejected")
+}
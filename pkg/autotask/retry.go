@@ -92,17 +92,7 @@ func RetryWithBackoff(ctx context.Context, config *RetryConfig, operation func()
 			return err
 		}
 
-		// Calculate next interval with exponential backoff
-		interval = time.Duration(float64(interval) * config.Multiplier)
-		if interval > config.MaxInterval {
-			interval = config.MaxInterval
-		}
-
-		// Add jitter
-		if config.Jitter > 0 {
-			jitter := time.Duration(float64(interval) * config.Jitter)
-			interval += time.Duration(time.Now().UnixNano() % int64(jitter))
-		}
+		interval = nextBackoff(config, interval)
 
 		// Wait before retry
 		select {
@@ -116,6 +106,23 @@ func RetryWithBackoff(ctx context.Context, config *RetryConfig, operation func()
 	return fmt.Errorf("max retries exceeded: %w", lastErr)
 }
 
+// nextBackoff calculates the next retry interval with exponential backoff and jitter
+func nextBackoff(config *RetryConfig, interval time.Duration) time.Duration {
+	interval = time.Duration(float64(interval) * config.Multiplier)
+	if interval > config.MaxInterval {
+		interval = config.MaxInterval
+	}
+
+	// Add jitter
+	if config.Jitter > 0 {
+		if jitter := time.Duration(float64(interval) * config.Jitter); jitter > 0 {
+			interval += time.Duration(time.Now().UnixNano() % int64(jitter))
+		}
+	}
+
+	return interval
+}
+
 // WithRetry wraps an operation with retry logic
 func WithRetry(ctx context.Context, config *RetryConfig, operation func() error) error {
 	if config == nil {
@@ -7,9 +7,9 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
+	"sync"
 )
 
 // companiesService implements the CompaniesService interface
@@ -70,12 +70,17 @@ type contactsService struct {
 // webhookService implements the WebhookService interface
 type webhookService struct {
 	BaseEntityService
+	mu       sync.RWMutex
 	handlers map[string][]WebhookHandler
 	secret   string // Secret for webhook verification
 }
 
-// RegisterHandler registers a webhook handler
+// RegisterHandler registers a webhook handler. It is safe to call while
+// webhooks are being handled.
 func (s *webhookService) RegisterHandler(eventType string, handler WebhookHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	// Initialize handlers map if it doesn't exist
 	if s.handlers == nil {
 		s.handlers = make(map[string][]WebhookHandler)
@@ -126,7 +131,9 @@ func (s *webhookService) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get handlers for this event type
+	s.mu.RLock()
 	handlers, exists := s.handlers[event.EventType]
+	s.mu.RUnlock()
 	if !exists || len(handlers) == 0 {
 		// No handlers registered for this event type
 		// Return 200 OK to acknowledge receipt
@@ -138,7 +145,12 @@ func (s *webhookService) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 	for _, handler := range handlers {
 		if err := handler(&event); err != nil {
 			// Log the error but continue processing other handlers
-			s.GetClient().(*client).logger.LogError(fmt.Errorf("webhook handler error: %w", err))
+			s.GetClient().(*client).log().ErrorContext(r.Context(), "Webhook handler error",
+				"event_type", event.EventType,
+				"entity", event.Entity,
+				"entity_id", event.EntityID,
+				"error", err,
+			)
 		}
 	}
 
@@ -163,6 +175,9 @@ func (s *webhookService) SetWebhookSecret(secret string) {
 }
 
 // CreateWebhook creates a new webhook
+//
+// Deprecated: Autotask has no Webhooks entity. Use Client.CompanyWebhooks,
+// Client.TicketWebhooks or EnsureWebhooks.
 func (s *webhookService) CreateWebhook(ctx context.Context, url string, events []string) error {
 	webhook := struct {
 		URL    string   `json:"url"`
@@ -177,11 +192,17 @@ func (s *webhookService) CreateWebhook(ctx context.Context, url string, events [
 }
 
 // DeleteWebhook deletes a webhook
+//
+// Deprecated: Autotask has no Webhooks entity. Use Client.CompanyWebhooks,
+// Client.TicketWebhooks or EnsureWebhooks.
 func (s *webhookService) DeleteWebhook(ctx context.Context, id int64) error {
 	return s.Delete(ctx, id)
 }
 
 // ListWebhooks lists all webhooks
+//
+// Deprecated: Autotask has no Webhooks entity. Use Client.CompanyWebhooks,
+// Client.TicketWebhooks or EnsureWebhooks.
 func (s *webhookService) ListWebhooks(ctx context.Context) ([]interface{}, error) {
 	var result ListResponse
 	err := s.Query(ctx, "", &result)
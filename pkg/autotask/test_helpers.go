@@ -3,10 +3,12 @@ package autotask
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -25,6 +27,7 @@ type MockServer struct {
 	Requests         []*http.Request
 	RequestBodies    [][]byte
 	t                *testing.T
+	mu               sync.Mutex
 }
 
 // NewMockServer creates a new mock Autotask API server
@@ -60,16 +63,20 @@ func (m *MockServer) AddHandler(path string, handler func(w http.ResponseWriter,
 	m.ResponseHandlers[path] = handler
 	m.Mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
 		// Record the request for later inspection
+		m.mu.Lock()
 		m.Requests = append(m.Requests, r)
+		m.mu.Unlock()
 
 		// Read and record the request body
 		if r.Body != nil {
-			body := make([]byte, r.ContentLength)
-			if _, err := r.Body.Read(body); err != nil {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
 				m.t.Errorf("Failed to read request body: %v", err)
 				return
 			}
+			m.mu.Lock()
 			m.RequestBodies = append(m.RequestBodies, body)
+			m.mu.Unlock()
 
 			// Reset the body for the handler
 			r.Body = &readCloser{strings.NewReader(string(body))}
@@ -88,8 +95,8 @@ func (m *MockServer) AddHandler(path string, handler func(w http.ResponseWriter,
 
 			// Read and record the request body
 			if r.Body != nil {
-				body := make([]byte, r.ContentLength)
-				if _, err := r.Body.Read(body); err != nil {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
 					m.t.Errorf("Failed to read request body: %v", err)
 					return
 				}
@@ -139,6 +146,9 @@ func (m *MockServer) NewTestClient() Client {
 	baseURL, _ := url.Parse(m.Server.URL)
 	c.baseURL = baseURL
 
+	// Don't throttle requests to the mock server
+	c.rateLimiter.requestsPerMinute = 60000
+
 	// Set the zone info directly to avoid making an HTTP request
 	c.zoneInfo = &ZoneInfo{
 		ZoneName: "MockZone",
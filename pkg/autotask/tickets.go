@@ -0,0 +1,331 @@
+package autotask
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// TicketStatusComplete is the built-in status value of a completed ticket
+const TicketStatusComplete = 5
+
+// Note publish values
+const (
+	NotePublishAllUsers     = 1
+	NotePublishInternalOnly = 2
+)
+
+// Assign assigns a ticket to a resource acting in the given role
+func (s *ticketsService) Assign(ctx context.Context, ticketID, resourceID, roleID int64) (*Ticket, error) {
+	var errs []FieldError
+	if ticketID <= 0 {
+		errs = append(errs, FieldError{Field: "id", Message: "ticket ID is required"})
+	}
+	if resourceID <= 0 {
+		errs = append(errs, FieldError{Field: "assignedResourceID", Message: "resource ID is required"})
+	}
+	if roleID <= 0 {
+		errs = append(errs, FieldError{Field: "assignedResourceRoleID", Message: "role ID is required"})
+	}
+	if err := validationErrorOrNil(s.EntityName, errs); err != nil {
+		return nil, err
+	}
+
+	fields, err := s.GetFieldInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateTicket(ctx, fields, ticketID, map[string]interface{}{
+		"assignedResourceID":     resourceID,
+		"assignedResourceRoleID": roleID,
+	})
+}
+
+// ChangeStatus sets the status of a ticket. The status may be given as a
+// picklist label such as "In Progress" or as its numeric value.
+func (s *ticketsService) ChangeStatus(ctx context.Context, ticketID int64, status string) (*Ticket, error) {
+	if ticketID <= 0 {
+		return nil, &ValidationError{Entity: s.EntityName, Errors: []FieldError{{Field: "id", Message: "ticket ID is required"}}}
+	}
+
+	fields, err := s.GetFieldInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := resolvePicklistValue(fields, "status", status)
+	if err != nil {
+		return nil, &ValidationError{Entity: s.EntityName, Errors: []FieldError{{Field: "status", Message: err.Error()}}}
+	}
+
+	return s.updateTicket(ctx, fields, ticketID, map[string]interface{}{"status": value})
+}
+
+// Close completes a ticket, recording the resolution when one is given
+func (s *ticketsService) Close(ctx context.Context, ticketID int64, resolution string) (*Ticket, error) {
+	if ticketID <= 0 {
+		return nil, &ValidationError{Entity: s.EntityName, Errors: []FieldError{{Field: "id", Message: "ticket ID is required"}}}
+	}
+
+	fields, err := s.GetFieldInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefer the tenant's "Complete" label, falling back to the built-in value
+	status, err := resolvePicklistValue(fields, "status", "Complete")
+	if err != nil {
+		status = TicketStatusComplete
+	}
+
+	values := map[string]interface{}{"status": status}
+	if resolution != "" {
+		values["resolution"] = resolution
+	}
+	return s.updateTicket(ctx, fields, ticketID, values)
+}
+
+// AddNote adds a note to a ticket. Published notes are visible to all
+// Autotask users; unpublished notes are internal only. A zero NoteType uses
+// the picklist default.
+func (s *ticketsService) AddNote(ctx context.Context, ticketID int64, note *TicketNote, publish bool) (*TicketNote, error) {
+	if ticketID <= 0 {
+		return nil, &ValidationError{Entity: "TicketNotes", Errors: []FieldError{{Field: "ticketID", Message: "ticket ID is required"}}}
+	}
+	if note == nil {
+		return nil, &ValidationError{Entity: "TicketNotes", Errors: []FieldError{{Message: "note is required"}}}
+	}
+
+	fields, err := s.Client.GetFieldInfo(ctx, "TicketNotes")
+	if err != nil {
+		return nil, err
+	}
+
+	created := *note
+	created.ID = 0
+	created.TicketID = ticketID
+	created.Publish = NotePublishInternalOnly
+	if publish {
+		created.Publish = NotePublishAllUsers
+	}
+	if created.NoteType == 0 {
+		if field, ok := FindField(fields, "noteType"); ok {
+			if def, ok := field.DefaultPicklistValue(); ok {
+				created.NoteType, _ = strconv.Atoi(def.Value)
+			}
+		}
+	}
+
+	id, err := createEntity(ctx, s.Client, "TicketNotes", fmt.Sprintf("%s/%d/Notes", s.EntityName, ticketID), fields, &created)
+	if err != nil {
+		return nil, err
+	}
+	created.ID = id
+	return &created, nil
+}
+
+// AddTimeEntry logs time against a ticket. The entry is checked the same
+// way as TimeEntriesService.Submit.
+func (s *ticketsService) AddTimeEntry(ctx context.Context, ticketID int64, entry *TimeEntry) (*TimeEntry, error) {
+	if ticketID <= 0 {
+		return nil, &ValidationError{Entity: "TimeEntries", Errors: []FieldError{{Field: "ticketID", Message: "ticket ID is required"}}}
+	}
+	if entry == nil {
+		return nil, &ValidationError{Entity: "TimeEntries", Errors: []FieldError{{Message: "time entry is required"}}}
+	}
+
+	submitted := *entry
+	submitted.TicketID = ticketID
+	return s.Client.TimeEntries().Submit(ctx, &submitted)
+}
+
+// Merge folds a ticket into another. The REST API has no merge operation, so
+// the source ticket's notes are copied to the target, a note recording the
+// merge is added to the target and the source ticket is closed with a
+// resolution pointing at the target. Time entries and attachments stay on
+// the source ticket. Merge returns the target ticket.
+//
+// These steps can't be made atomic. When one fails the error names the
+// step, and Merge can be run again: notes already on the target, matched by
+// title and description, aren't copied twice, nor is the merge note.
+func (s *ticketsService) Merge(ctx context.Context, ticketID, intoTicketID int64) (*Ticket, error) {
+	var errs []FieldError
+	if ticketID <= 0 {
+		errs = append(errs, FieldError{Field: "id", Message: "ticket ID is required"})
+	}
+	if intoTicketID <= 0 {
+		errs = append(errs, FieldError{Field: "id", Message: "target ticket ID is required"})
+	}
+	if ticketID > 0 && ticketID == intoTicketID {
+		errs = append(errs, FieldError{Field: "id", Message: "cannot merge a ticket into itself"})
+	}
+	if err := validationErrorOrNil(s.EntityName, errs); err != nil {
+		return nil, err
+	}
+
+	source, err := s.getTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.getTicket(ctx, intoTicketID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.ListNotes(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes of ticket %d: %w", ticketID, err)
+	}
+	existing, err := s.ListNotes(ctx, intoTicketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes of ticket %d: %w", intoTicketID, err)
+	}
+	onTarget := make(map[noteKey]int, len(existing))
+	for _, note := range existing {
+		onTarget[noteKeyOf(note)]++
+	}
+
+	mergeNote := &TicketNote{
+		Title:       fmt.Sprintf("Merged ticket %s", ticketLabel(source)),
+		Description: fmt.Sprintf("Ticket %s (%s) was merged into this ticket.", ticketLabel(source), source.Title),
+	}
+	merged := onTarget[noteKeyOf(*mergeNote)] > 0
+
+	for _, note := range notes {
+		// Skip notes copied by an earlier attempt
+		if key := noteKeyOf(note); onTarget[key] > 0 {
+			onTarget[key]--
+			continue
+		}
+		copied := TicketNote{Title: note.Title, Description: note.Description, NoteType: note.NoteType}
+		if _, err := s.AddNote(ctx, intoTicketID, &copied, note.Publish == NotePublishAllUsers); err != nil {
+			return nil, fmt.Errorf("failed to copy note %d: %w", note.ID, err)
+		}
+	}
+
+	if !merged {
+		if _, err := s.AddNote(ctx, intoTicketID, mergeNote, false); err != nil {
+			return nil, fmt.Errorf("failed to record merge: %w", err)
+		}
+	}
+
+	if _, err := s.Close(ctx, ticketID, fmt.Sprintf("Merged into ticket %s", ticketLabel(target))); err != nil {
+		return nil, fmt.Errorf("failed to close merged ticket: %w", err)
+	}
+
+	return s.getTicket(ctx, intoTicketID)
+}
+
+// noteKey identifies a note's content for Merge
+type noteKey struct {
+	title       string
+	description string
+}
+
+// noteKeyOf returns the content key of a note
+func noteKeyOf(note TicketNote) noteKey {
+	return noteKey{title: note.Title, description: note.Description}
+}
+
+// ListNotes returns all notes on a ticket
+func (s *ticketsService) ListNotes(ctx context.Context, ticketID int64) ([]TicketNote, error) {
+	if ticketID <= 0 {
+		return nil, &ValidationError{Entity: "TicketNotes", Errors: []FieldError{{Field: "ticketID", Message: "ticket ID is required"}}}
+	}
+	notes := NewBaseEntityService(s.Client, "TicketNotes")
+	return FetchAllPages[TicketNote](ctx, &notes, fmt.Sprintf("ticketID=%d", ticketID))
+}
+
+// ListTimeEntries returns all time entries logged against a ticket
+func (s *ticketsService) ListTimeEntries(ctx context.Context, ticketID int64) ([]TimeEntry, error) {
+	if ticketID <= 0 {
+		return nil, &ValidationError{Entity: "TimeEntries", Errors: []FieldError{{Field: "ticketID", Message: "ticket ID is required"}}}
+	}
+	entries := NewBaseEntityService(s.Client, "TimeEntries")
+	return FetchAllPages[TimeEntry](ctx, &entries, fmt.Sprintf("ticketID=%d", ticketID))
+}
+
+// ListAttachments returns the attachments of a ticket
+func (s *ticketsService) ListAttachments(ctx context.Context, ticketID int64) ([]TicketAttachment, error) {
+	if ticketID <= 0 {
+		return nil, &ValidationError{Entity: "TicketAttachments", Errors: []FieldError{{Field: "parentID", Message: "ticket ID is required"}}}
+	}
+	attachments := NewBaseEntityService(s.Client, "TicketAttachments")
+	return FetchAllPages[TicketAttachment](ctx, &attachments, fmt.Sprintf("parentID=%d", ticketID))
+}
+
+// getTicket gets a ticket by ID as a typed value
+func (s *ticketsService) getTicket(ctx context.Context, ticketID int64) (*Ticket, error) {
+	item, err := s.Get(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("ticket %d not found", ticketID)
+	}
+
+	var ticket Ticket
+	if err := decodeItem(item, &ticket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// updateTicket validates a partial update against the ticket metadata,
+// applies it and returns the updated ticket
+func (s *ticketsService) updateTicket(ctx context.Context, fields []FieldInfo, ticketID int64, values map[string]interface{}) (*Ticket, error) {
+	if err := validationErrorOrNil(s.EntityName, validateFields(fields, values, false)); err != nil {
+		return nil, err
+	}
+
+	values["id"] = ticketID
+	if _, err := s.Update(ctx, ticketID, values); err != nil {
+		return nil, err
+	}
+	return s.getTicket(ctx, ticketID)
+}
+
+// ticketLabel returns the ticket number, or the ID when the number is unknown
+func ticketLabel(ticket *Ticket) string {
+	if ticket.TicketNumber != "" {
+		return ticket.TicketNumber
+	}
+	return strconv.FormatInt(ticket.ID, 10)
+}
+
+// createEntity validates entity against fields, when given, and creates it
+// by posting to path, returning the ID of the new entity
+func createEntity(ctx context.Context, c Client, entityName, path string, fields []FieldInfo, entity interface{}) (int64, error) {
+	if fields != nil {
+		values, err := toFieldMap(entity)
+		if err != nil {
+			return 0, err
+		}
+		if err := validationErrorOrNil(entityName, validateFields(fields, values, true)); err != nil {
+			return 0, err
+		}
+	}
+
+	ctx = withOperation(ctx, entityName, "create")
+	req, err := c.NewRequest(ctx, http.MethodPost, path, entity)
+	if err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		ItemID int64 `json:"itemId"`
+		Item   struct {
+			ID int64 `json:"id"`
+		} `json:"item"`
+	}
+	if _, err := c.Do(req, &result); err != nil {
+		return 0, err
+	}
+
+	if result.ItemID == 0 {
+		return result.Item.ID, nil
+	}
+	return result.ItemID, nil
+}
@@ -0,0 +1,271 @@
+package autotask
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// ticketFields is the field metadata served for Tickets in tests
+var ticketFields = map[string]interface{}{
+	"fields": []FieldInfo{
+		{Name: "id", DataType: "long", IsReadOnly: true},
+		{Name: "title", DataType: "string", Length: 255, IsRequired: true},
+		{Name: "status", DataType: "integer", IsRequired: true, IsPickList: true, PicklistValues: []PicklistValue{
+			{Value: "1", Label: "New", IsActive: true, IsDefaultValue: true},
+			{Value: "5", Label: "Complete", IsActive: true},
+			{Value: "8", Label: "In Progress", IsActive: true},
+			{Value: "9", Label: "Legacy", IsActive: false},
+		}},
+		{Name: "assignedResourceID", DataType: "integer", IsReference: true, ReferenceEntityType: "Resource"},
+		{Name: "assignedResourceRoleID", DataType: "integer", IsReference: true, ReferenceEntityType: "Role"},
+		{Name: "resolution", DataType: "string", Length: 32000},
+		{Name: "ticketNumber", DataType: "string", IsReadOnly: true},
+	},
+}
+
+// ticketNoteFields is the field metadata served for TicketNotes in tests
+var ticketNoteFields = map[string]interface{}{
+	"fields": []FieldInfo{
+		{Name: "id", DataType: "long", IsReadOnly: true},
+		{Name: "ticketID", DataType: "integer", IsRequired: true},
+		{Name: "title", DataType: "string", Length: 250},
+		{Name: "description", DataType: "string", Length: 32000, IsRequired: true},
+		{Name: "noteType", DataType: "integer", IsRequired: true, IsPickList: true, PicklistValues: []PicklistValue{
+			{Value: "1", Label: "Task Summary", IsActive: true, IsDefaultValue: true},
+			{Value: "3", Label: "Task Notes", IsActive: true},
+		}},
+		{Name: "publish", DataType: "integer", IsRequired: true, IsPickList: true, PicklistValues: []PicklistValue{
+			{Value: "1", Label: "All Autotask Users", IsActive: true},
+			{Value: "2", Label: "Internal Only", IsActive: true},
+		}},
+	},
+}
+
+func TestTicketChangeStatus(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	metadataRequests := 0
+	server.AddHandler("/Tickets/entityInformation/fields", func(w http.ResponseWriter, r *http.Request) {
+		metadataRequests++
+		server.RespondWithJSON(w, http.StatusOK, ticketFields)
+	})
+
+	var patched map[string]interface{}
+	server.AddHandler("/Tickets/10", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			_ = json.NewDecoder(r.Body).Decode(&patched)
+			server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"itemId": 10})
+			return
+		}
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 10, "title": "Printer", "status": 8}})
+	})
+
+	client := server.NewTestClient()
+	ctx := context.Background()
+
+	ticket, err := client.Tickets().ChangeStatus(ctx, 10, "in progress")
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 8, ticket.Status, "typed ticket should be returned")
+	AssertEqual(t, float64(8), patched["status"], "status label should be resolved to its value")
+	AssertEqual(t, float64(10), patched["id"], "patch should include the ticket ID")
+
+	_, err = client.Tickets().ChangeStatus(ctx, 10, "Legacy")
+	var validationErr *ValidationError
+	AssertTrue(t, errors.As(err, &validationErr), "error should be a *ValidationError")
+	AssertEqual(t, "status", validationErr.Errors[0].Field, "field should be reported")
+
+	_, err = client.Tickets().ChangeStatus(ctx, 10, "Nonexistent")
+	AssertTrue(t, errors.As(err, &validationErr), "error should be a *ValidationError")
+
+	AssertEqual(t, 1, metadataRequests, "field info should be cached")
+}
+
+func TestTicketAssignAndClose(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/Tickets/entityInformation/fields", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, ticketFields)
+	})
+
+	var patches []map[string]interface{}
+	server.AddHandler("/Tickets/10", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			var patch map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&patch)
+			patches = append(patches, patch)
+			server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"itemId": 10})
+			return
+		}
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 10}})
+	})
+
+	client := server.NewTestClient()
+	ctx := context.Background()
+
+	_, err := client.Tickets().Assign(ctx, 10, 0, 0)
+	var validationErr *ValidationError
+	AssertTrue(t, errors.As(err, &validationErr), "error should be a *ValidationError")
+	AssertEqual(t, 2, len(validationErr.Errors), "all problems should be reported")
+
+	_, err = client.Tickets().Assign(ctx, 10, 29682885, 29683461)
+	AssertNil(t, err, "error should be nil")
+
+	_, err = client.Tickets().Close(ctx, 10, "Replaced toner")
+	AssertNil(t, err, "error should be nil")
+
+	AssertEqual(t, 2, len(patches), "two updates should be sent")
+	AssertEqual(t, float64(29682885), patches[0]["assignedResourceID"], "resource should be assigned")
+	AssertEqual(t, float64(29683461), patches[0]["assignedResourceRoleID"], "role should be assigned")
+	AssertEqual(t, float64(5), patches[1]["status"], "ticket should be completed")
+	AssertEqual(t, "Replaced toner", patches[1]["resolution"], "resolution should be set")
+}
+
+func TestTicketAddNote(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/TicketNotes/entityInformation/fields", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, ticketNoteFields)
+	})
+
+	var posted TicketNote
+	server.AddHandler("/Tickets/10/Notes", func(w http.ResponseWriter, r *http.Request) {
+		AssertEqual(t, http.MethodPost, r.Method, "method should be POST")
+		_ = json.NewDecoder(r.Body).Decode(&posted)
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"itemId": 77})
+	})
+
+	client := server.NewTestClient()
+	ctx := context.Background()
+
+	note, err := client.Tickets().AddNote(ctx, 10, &TicketNote{Title: "Update", Description: "Called the customer"}, true)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, int64(77), note.ID, "created note ID should be returned")
+	AssertEqual(t, int64(10), posted.TicketID, "ticket ID should be set")
+	AssertEqual(t, NotePublishAllUsers, posted.Publish, "published note should be visible to all users")
+	AssertEqual(t, 1, posted.NoteType, "default note type should be applied")
+
+	_, err = client.Tickets().AddNote(ctx, 10, &TicketNote{Title: "Missing description"}, false)
+	var validationErr *ValidationError
+	AssertTrue(t, errors.As(err, &validationErr), "error should be a *ValidationError")
+	AssertEqual(t, "description", validationErr.Errors[0].Field, "missing required field should be reported")
+}
+
+func TestTicketListNotes(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/TicketNotes/query", func(w http.ResponseWriter, r *http.Request) {
+		AssertContains(t, r.URL.Query().Get("search"), `"field":"ticketID"`, "query should filter by ticket")
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"items": []map[string]interface{}{
+				{"id": 1, "ticketID": 10, "title": "First"},
+				{"id": 2, "ticketID": 10, "title": "Second"},
+			},
+			"pageDetails": PageDetails{Count: 2},
+		})
+	})
+
+	client := server.NewTestClient()
+
+	notes, err := client.Tickets().ListNotes(context.Background(), 10)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, 2, len(notes), "all notes should be returned")
+	AssertEqual(t, "Second", notes[1].Title, "notes should be typed")
+}
+
+func TestTicketMerge(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/Tickets/entityInformation/fields", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, ticketFields)
+	})
+	server.AddHandler("/TicketNotes/entityInformation/fields", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, ticketNoteFields)
+	})
+
+	sourceNotes := []map[string]interface{}{
+		{"id": 1, "ticketID": 10, "title": "First", "description": "Printer jammed", "noteType": 1},
+		{"id": 2, "ticketID": 10, "title": "Second", "description": "Replaced roller", "noteType": 1},
+	}
+	// The first note was copied by an earlier attempt
+	targetNotes := []map[string]interface{}{
+		{"id": 3, "ticketID": 20, "title": "First", "description": "Printer jammed", "noteType": 1},
+	}
+	server.AddHandler("/TicketNotes/query", func(w http.ResponseWriter, r *http.Request) {
+		items := sourceNotes
+		if strings.Contains(r.URL.Query().Get("search"), `"value":"20"`) {
+			items = targetNotes
+		}
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+	})
+
+	var posted []string
+	server.AddHandler("/Tickets/20/Notes", func(w http.ResponseWriter, r *http.Request) {
+		var note map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&note)
+		posted = append(posted, note["title"].(string))
+		targetNotes = append(targetNotes, note)
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"itemId": 100 + len(targetNotes)})
+	})
+
+	closeFails := true
+	server.AddHandler("/Tickets/10", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			if closeFails {
+				server.RespondWithError(w, http.StatusBadRequest, "ticket is locked", nil)
+				return
+			}
+			server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"itemId": 10})
+			return
+		}
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 10, "title": "Printer", "ticketNumber": "T1"}})
+	})
+	server.AddHandler("/Tickets/20", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, Response{Item: map[string]interface{}{"id": 20, "title": "Printers", "ticketNumber": "T2"}})
+	})
+
+	client := server.NewTestClient()
+	ctx := context.Background()
+
+	_, err := client.Tickets().Merge(ctx, 10, 20)
+	AssertNotNil(t, err, "failed close should be reported")
+	AssertContains(t, err.Error(), "failed to close merged ticket", "error should name the failed step")
+	AssertEqual(t, "Second,Merged ticket T1", strings.Join(posted, ","), "notes already on the target should not be copied")
+
+	closeFails = false
+	posted = nil
+	ticket, err := client.Tickets().Merge(ctx, 10, 20)
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, int64(20), ticket.ID, "target ticket should be returned")
+	AssertEqual(t, 0, len(posted), "retried merge should not copy notes again")
+}
+
+func TestValidateFields(t *testing.T) {
+	fields := []FieldInfo{
+		{Name: "id", IsReadOnly: true},
+		{Name: "title", Length: 5, IsRequired: true},
+		{Name: "ticketNumber", IsReadOnly: true},
+	}
+
+	errs := validateFields(fields, map[string]interface{}{
+		"id":           float64(1),
+		"ticketNumber": "T1",
+		"bogus":        true,
+	}, true)
+
+	AssertEqual(t, 3, len(errs), "all problems should be reported")
+	AssertEqual(t, "bogus: unknown field", errs[0].String(), "unknown field should be reported")
+	AssertEqual(t, "ticketNumber: field is read-only", errs[1].String(), "read-only field should be reported")
+	AssertEqual(t, "title: field is required", errs[2].String(), "missing required field should be reported")
+
+	errs = validateFields(fields, map[string]interface{}{"title": "too long"}, false)
+	AssertEqual(t, 1, len(errs), "over-long value should be reported")
+}
@@ -0,0 +1,163 @@
+package autotask
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// hoursWorkedTolerance allows for rounding when HoursWorked is compared with
+// the start and end times
+const hoursWorkedTolerance = 1.0 / 60
+
+// resourceRole is a role a resource may log time under
+type resourceRole struct {
+	RoleID    int64 `json:"roleID"`
+	IsActive  bool  `json:"isActive"`
+	IsDefault bool  `json:"isDefault"`
+}
+
+// Submit validates a time entry locally and creates it. The entry must
+// reference exactly one of a ticket or a task, end after it starts, have
+// HoursWorked no greater than the interval, and use a role the resource
+// holds. When RoleID is zero, the resource's default role is filled in.
+// Every problem found is returned together in a *ValidationError before
+// anything is sent.
+func (s *timeEntriesService) Submit(ctx context.Context, entry *TimeEntry) (*TimeEntry, error) {
+	if entry == nil {
+		return nil, &ValidationError{Entity: s.EntityName, Errors: []FieldError{{Message: "time entry is required"}}}
+	}
+
+	submitted := *entry
+	submitted.ID = 0
+
+	errs := validateTimeEntry(&submitted)
+
+	if submitted.ResourceID > 0 {
+		roleErrs, err := s.resolveRole(ctx, &submitted)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, roleErrs...)
+	}
+
+	fields, err := s.GetFieldInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values, err := toFieldMap(&submitted)
+	if err != nil {
+		return nil, err
+	}
+	errs = append(errs, validateFields(fields, values, true)...)
+
+	if err := validationErrorOrNil(s.EntityName, dedupeFieldErrors(errs)); err != nil {
+		return nil, err
+	}
+
+	id, err := createEntity(ctx, s.Client, s.EntityName, s.EntityName, fields, &submitted)
+	if err != nil {
+		return nil, err
+	}
+	submitted.ID = id
+	return &submitted, nil
+}
+
+// validateTimeEntry checks the parts of a time entry that don't need the API
+func validateTimeEntry(entry *TimeEntry) []FieldError {
+	var errs []FieldError
+
+	if entry.ResourceID <= 0 {
+		errs = append(errs, FieldError{Field: "resourceID", Message: "resource ID is required"})
+	}
+
+	switch {
+	case entry.TicketID == 0 && entry.TaskID == 0:
+		errs = append(errs, FieldError{Field: "ticketID", Message: "exactly one of ticketID or taskID is required"})
+	case entry.TicketID != 0 && entry.TaskID != 0:
+		errs = append(errs, FieldError{Field: "taskID", Message: "exactly one of ticketID or taskID may be set"})
+	}
+
+	start := entry.StartDateTime.ValueOr(DateTime{})
+	end := entry.EndDateTime.ValueOr(DateTime{})
+
+	switch {
+	case start.IsZero() && end.IsZero():
+		if entry.HoursWorked <= 0 {
+			errs = append(errs, FieldError{Field: "hoursWorked", Message: "hoursWorked is required when no start and end time are given"})
+		}
+	case start.IsZero() || end.IsZero():
+		errs = append(errs, FieldError{Field: "endDateTime", Message: "startDateTime and endDateTime must be set together"})
+	case !end.After(start.Time):
+		errs = append(errs, FieldError{Field: "endDateTime", Message: "endDateTime must be after startDateTime"})
+	default:
+		interval := end.Sub(start.Time).Hours()
+		if entry.HoursWorked < 0 || entry.HoursWorked > interval+hoursWorkedTolerance {
+			errs = append(errs, FieldError{Field: "hoursWorked", Message: fmt.Sprintf("hoursWorked %.2f is inconsistent with the %.2f hour interval", entry.HoursWorked, interval)})
+		}
+		if entry.HoursWorked == 0 {
+			entry.HoursWorked = math.Round(interval*100) / 100
+		}
+	}
+
+	return errs
+}
+
+// resolveRole checks that the entry's role is held by its resource, filling
+// in the resource's default role when none is given. Ticket time uses the
+// resource's service desk roles; task time uses its project roles.
+func (s *timeEntriesService) resolveRole(ctx context.Context, entry *TimeEntry) ([]FieldError, error) {
+	entityName := "ResourceRoles"
+	if entry.TicketID != 0 {
+		entityName = "ResourceServiceDeskRoles"
+	}
+	service := NewBaseEntityService(s.Client, entityName)
+	roles, err := FetchAllPages[resourceRole](ctx, &service, fmt.Sprintf("resourceID=%d", entry.ResourceID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up roles for resource %d: %w", entry.ResourceID, err)
+	}
+
+	var active []resourceRole
+	for _, role := range roles {
+		if role.IsActive {
+			active = append(active, role)
+		}
+	}
+
+	if entry.RoleID != 0 {
+		for _, role := range active {
+			if role.RoleID == entry.RoleID {
+				return nil, nil
+			}
+		}
+		return []FieldError{{Field: "roleID", Message: fmt.Sprintf("resource %d does not hold active role %d", entry.ResourceID, entry.RoleID)}}, nil
+	}
+
+	for _, role := range active {
+		if role.IsDefault {
+			entry.RoleID = role.RoleID
+			return nil, nil
+		}
+	}
+	if len(active) == 1 {
+		entry.RoleID = active[0].RoleID
+		return nil, nil
+	}
+	return []FieldError{{Field: "roleID", Message: fmt.Sprintf("roleID is required: resource %d has %d active roles and no default", entry.ResourceID, len(active))}}, nil
+}
+
+// dedupeFieldErrors removes repeated problems for the same field, keeping the first
+func dedupeFieldErrors(errs []FieldError) []FieldError {
+	seen := make(map[string]bool)
+	var result []FieldError
+	for _, e := range errs {
+		key := strings.ToLower(e.Field)
+		if key != "" && seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, e)
+	}
+	return result
+}
@@ -0,0 +1,143 @@
+package autotask
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// timeEntryFields is the field metadata served for TimeEntries in tests
+var timeEntryFields = map[string]interface{}{
+	"fields": []FieldInfo{
+		{Name: "id", DataType: "long", IsReadOnly: true},
+		{Name: "resourceID", DataType: "integer", IsRequired: true},
+		{Name: "ticketID", DataType: "integer"},
+		{Name: "taskID", DataType: "integer"},
+		{Name: "roleID", DataType: "integer", IsRequired: true},
+		{Name: "dateWorked", DataType: "datetime", IsRequired: true},
+		{Name: "startDateTime", DataType: "datetime"},
+		{Name: "endDateTime", DataType: "datetime"},
+		{Name: "hoursWorked", DataType: "decimal"},
+		{Name: "summaryNotes", DataType: "string", Length: 8000},
+	},
+}
+
+// testDateTime parses a date-time for a test entity
+func testDateTime(value string) Nullable[DateTime] {
+	dt, err := ParseDateTime(value)
+	if err != nil {
+		panic(err)
+	}
+	return NullableOf(dt)
+}
+
+func TestValidateTimeEntry(t *testing.T) {
+	tests := []struct {
+		name   string
+		entry  TimeEntry
+		fields []string
+	}{
+		{
+			name:  "valid interval",
+			entry: TimeEntry{ResourceID: 1, TicketID: 2, StartDateTime: testDateTime("2025-03-01T09:00:00Z"), EndDateTime: testDateTime("2025-03-01T10:30:00Z"), HoursWorked: 1.5},
+		},
+		{
+			name:  "hours only",
+			entry: TimeEntry{ResourceID: 1, TaskID: 2, HoursWorked: 2},
+		},
+		{
+			name:   "ticket and task",
+			entry:  TimeEntry{ResourceID: 1, TicketID: 2, TaskID: 3, HoursWorked: 1},
+			fields: []string{"taskID"},
+		},
+		{
+			name:   "no parent and no resource",
+			entry:  TimeEntry{HoursWorked: 1},
+			fields: []string{"resourceID", "ticketID"},
+		},
+		{
+			name:   "end before start",
+			entry:  TimeEntry{ResourceID: 1, TicketID: 2, StartDateTime: testDateTime("2025-03-01T10:00:00Z"), EndDateTime: testDateTime("2025-03-01T09:00:00Z")},
+			fields: []string{"endDateTime"},
+		},
+		{
+			name:   "hours exceed interval",
+			entry:  TimeEntry{ResourceID: 1, TicketID: 2, StartDateTime: testDateTime("2025-03-01T09:00:00Z"), EndDateTime: testDateTime("2025-03-01T10:00:00Z"), HoursWorked: 3},
+			fields: []string{"hoursWorked"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateTimeEntry(&tt.entry)
+			AssertEqual(t, len(tt.fields), len(errs), "number of problems should match")
+			for i, field := range tt.fields {
+				AssertEqual(t, field, errs[i].Field, "problem field should match")
+			}
+		})
+	}
+
+	entry := TimeEntry{ResourceID: 1, TicketID: 2, StartDateTime: testDateTime("2025-03-01T09:00:00Z"), EndDateTime: testDateTime("2025-03-01T09:45:00Z")}
+	validateTimeEntry(&entry)
+	AssertEqual(t, 0.75, entry.HoursWorked, "hours worked should be filled in from the interval")
+}
+
+func TestTimeEntrySubmit(t *testing.T) {
+	server := NewMockServer(t)
+	defer server.Close()
+
+	server.AddHandler("/TimeEntries/entityInformation/fields", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, timeEntryFields)
+	})
+	server.AddHandler("/ResourceServiceDeskRoles/query", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"items": []map[string]interface{}{
+				{"resourceID": 5, "roleID": 100, "isActive": true, "isDefault": false},
+				{"resourceID": 5, "roleID": 200, "isActive": true, "isDefault": true},
+				{"resourceID": 5, "roleID": 300, "isActive": false},
+			},
+		})
+	})
+
+	var posted TimeEntry
+	posts := 0
+	server.AddHandler("/TimeEntries", func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		_ = json.NewDecoder(r.Body).Decode(&posted)
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"itemId": 900})
+	})
+
+	client := server.NewTestClient()
+	ctx := context.Background()
+
+	entry, err := client.Tickets().AddTimeEntry(ctx, 42, &TimeEntry{
+		ResourceID:    5,
+		DateWorked:    NullableOf(NewDate(2025, time.March, 1)),
+		StartDateTime: testDateTime("2025-03-01T09:00:00Z"),
+		EndDateTime:   testDateTime("2025-03-01T10:00:00Z"),
+		SummaryNotes:  "Replaced toner",
+	})
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, int64(900), entry.ID, "created ID should be returned")
+	AssertEqual(t, int64(200), posted.RoleID, "default role should be filled in")
+	AssertEqual(t, int64(42), posted.TicketID, "ticket ID should be set")
+	AssertEqual(t, 1.0, posted.HoursWorked, "hours worked should be derived")
+
+	_, err = client.TimeEntries().Submit(ctx, &TimeEntry{
+		ResourceID:    5,
+		TicketID:      42,
+		RoleID:        300,
+		StartDateTime: testDateTime("2025-03-01T10:00:00Z"),
+		EndDateTime:   testDateTime("2025-03-01T09:00:00Z"),
+	})
+	var validationErr *ValidationError
+	AssertTrue(t, errors.As(err, &validationErr), "error should be a *ValidationError")
+	AssertEqual(t, 3, len(validationErr.Errors), "all problems should be reported")
+	AssertEqual(t, "endDateTime", validationErr.Errors[0].Field, "interval problem should be reported")
+	AssertEqual(t, "roleID", validationErr.Errors[1].Field, "inactive role should be reported")
+	AssertEqual(t, "dateWorked", validationErr.Errors[2].Field, "missing required field should be reported")
+	AssertEqual(t, 1, posts, "invalid entry should not be sent")
+}
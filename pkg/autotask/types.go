@@ -4,29 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
-	"os"
+	"time"
 )
 
-// FilterItem represents a single condition in an Autotask API filter
-type FilterItem struct {
-	Field string      `json:"field"`
-	Op    string      `json:"op"`
-	Value interface{} `json:"value"`
-}
-
-// FilterCondition represents a filter condition that can contain multiple items
-type FilterCondition struct {
-	Op    string       `json:"op"`
-	Items []FilterItem `json:"items"`
-}
-
-// QueryParams represents the parameters for an Autotask API query
-type QueryParams struct {
-	MaxRecords int               `json:"MaxRecords"`
-	Filter     []FilterCondition `json:"filter"` // Note: lowercase 'filter' to match API
-}
-
 // LogLevel represents the logging level
 type LogLevel int
 
@@ -69,13 +52,19 @@ type EntityService interface {
 	// Pagination handles paginated results
 	Pagination(ctx context.Context, url string, result interface{}) error
 
-	// BatchCreate creates multiple entities in a single request
+	// BatchCreate creates multiple entities, one request each
+	//
+	// Deprecated: use BulkCreate
 	BatchCreate(ctx context.Context, entities []interface{}, result interface{}) error
 
-	// BatchUpdate updates multiple entities in a single request
+	// BatchUpdate updates multiple entities, one request each
+	//
+	// Deprecated: use BulkUpdate
 	BatchUpdate(ctx context.Context, entities []interface{}, result interface{}) error
 
-	// BatchDelete deletes multiple entities in a single request
+	// BatchDelete deletes multiple entities, one request each
+	//
+	// Deprecated: use BulkDelete
 	BatchDelete(ctx context.Context, ids []int64) error
 
 	// GetNextPage gets the next page of results
@@ -89,6 +78,9 @@ type EntityService interface {
 
 	// GetClient returns the client used by the service
 	GetClient() Client
+
+	// GetFieldInfo returns the field metadata for the entity
+	GetFieldInfo(ctx context.Context) ([]FieldInfo, error)
 }
 
 // CompaniesService represents the companies service interface
@@ -99,6 +91,36 @@ type CompaniesService interface {
 // TicketsService represents the tickets service interface
 type TicketsService interface {
 	EntityService
+
+	// Assign assigns a ticket to a resource acting in the given role
+	Assign(ctx context.Context, ticketID, resourceID, roleID int64) (*Ticket, error)
+
+	// ChangeStatus sets the status of a ticket by picklist label or value
+	ChangeStatus(ctx context.Context, ticketID int64, status string) (*Ticket, error)
+
+	// Close completes a ticket with an optional resolution
+	Close(ctx context.Context, ticketID int64, resolution string) (*Ticket, error)
+
+	// AddNote adds a note to a ticket
+	AddNote(ctx context.Context, ticketID int64, note *TicketNote, publish bool) (*TicketNote, error)
+
+	// AddTimeEntry logs time against a ticket
+	AddTimeEntry(ctx context.Context, ticketID int64, entry *TimeEntry) (*TimeEntry, error)
+
+	// Merge folds a ticket into another and returns the target ticket
+	Merge(ctx context.Context, ticketID, intoTicketID int64) (*Ticket, error)
+
+	// ListNotes returns all notes on a ticket
+	ListNotes(ctx context.Context, ticketID int64) ([]TicketNote, error)
+
+	// ListTimeEntries returns all time entries logged against a ticket
+	ListTimeEntries(ctx context.Context, ticketID int64) ([]TimeEntry, error)
+
+	// ListAttachments returns the attachments of a ticket
+	ListAttachments(ctx context.Context, ticketID int64) ([]TicketAttachment, error)
+
+	// QueryWithRelations returns tickets with the relations named with Expand loaded
+	QueryWithRelations(ctx context.Context, filter string, opts ...QueryOption) ([]TicketWithRelations, error)
 }
 
 // ContactsService represents the contacts service interface
@@ -110,6 +132,9 @@ type ContactsService interface {
 type WebhookHandler func(event *WebhookEvent) error
 
 // WebhookEvent represents a webhook event from Autotask
+//
+// Deprecated: Autotask does not send this payload shape. Use the
+// pkg/webhook package, which parses the real payload.
 type WebhookEvent struct {
 	EventType string          `json:"eventType"`
 	Entity    string          `json:"entity"`
@@ -119,16 +144,86 @@ type WebhookEvent struct {
 }
 
 // WebhookService represents the webhook service interface
+//
+// Deprecated: receive webhooks with the pkg/webhook package and manage them
+// with Client.CompanyWebhooks, Client.TicketWebhooks or EnsureWebhooks.
 type WebhookService interface {
 	EntityService
+
+	// Deprecated: use webhook.Receiver from the pkg/webhook package
 	RegisterHandler(eventType string, handler WebhookHandler)
+
+	// Deprecated: use webhook.Receiver from the pkg/webhook package
 	HandleWebhook(w http.ResponseWriter, r *http.Request)
+
+	// Deprecated: use Client.CompanyWebhooks, Client.TicketWebhooks or EnsureWebhooks
 	CreateWebhook(ctx context.Context, url string, events []string) error
+
+	// Deprecated: use Client.CompanyWebhooks, Client.TicketWebhooks or EnsureWebhooks
 	DeleteWebhook(ctx context.Context, id int64) error
+
+	// Deprecated: use Client.CompanyWebhooks, Client.TicketWebhooks or EnsureWebhooks
 	ListWebhooks(ctx context.Context) ([]interface{}, error)
 	SetWebhookSecret(secret string)
 }
 
+// WebhookSubscriptionService manages the webhooks of one entity type, such
+// as TicketWebhooks, with their field selections and excluded resources
+type WebhookSubscriptionService interface {
+	EntityService
+
+	// EntityType returns the entity type the webhooks report on, such as "Ticket"
+	EntityType() string
+
+	// ListWebhooks returns all webhooks for the entity type
+	ListWebhooks(ctx context.Context) ([]Webhook, error)
+
+	// GetWebhook gets a webhook by ID
+	GetWebhook(ctx context.Context, id int64) (*Webhook, error)
+
+	// CreateWebhook creates a webhook and returns it as stored
+	CreateWebhook(ctx context.Context, webhook *Webhook) (*Webhook, error)
+
+	// UpdateWebhook applies a partial update to a webhook
+	UpdateWebhook(ctx context.Context, id int64, changes map[string]interface{}) (*Webhook, error)
+
+	// DeactivateWebhook stops a webhook from sending callbacks
+	DeactivateWebhook(ctx context.Context, id int64) error
+
+	// ListFields returns the standard fields selected for a webhook
+	ListFields(ctx context.Context, webhookID int64) ([]WebhookField, error)
+
+	// AddField selects a standard field for a webhook
+	AddField(ctx context.Context, webhookID int64, field WebhookField) (*WebhookField, error)
+
+	// RemoveField removes a field selection from a webhook
+	RemoveField(ctx context.Context, webhookID, id int64) error
+
+	// ListUdfFields returns the user-defined fields selected for a webhook
+	ListUdfFields(ctx context.Context, webhookID int64) ([]WebhookUdfField, error)
+
+	// AddUdfField selects a user-defined field for a webhook
+	AddUdfField(ctx context.Context, webhookID int64, field WebhookUdfField) (*WebhookUdfField, error)
+
+	// RemoveUdfField removes a user-defined field selection from a webhook
+	RemoveUdfField(ctx context.Context, webhookID, id int64) error
+
+	// ListExcludedResources returns the resources whose changes don't trigger a webhook
+	ListExcludedResources(ctx context.Context, webhookID int64) ([]WebhookExcludedResource, error)
+
+	// AddExcludedResource stops changes made by a resource from triggering a webhook
+	AddExcludedResource(ctx context.Context, webhookID, resourceID int64) (*WebhookExcludedResource, error)
+
+	// RemoveExcludedResource removes a resource exclusion from a webhook
+	RemoveExcludedResource(ctx context.Context, webhookID, id int64) error
+
+	// FieldID resolves a standard field name to its webhook field ID
+	FieldID(ctx context.Context, name string) (int64, error)
+
+	// UdfFieldID resolves a user-defined field name to its webhook field ID
+	UdfFieldID(ctx context.Context, name string) (int64, error)
+}
+
 // ResourcesService represents the resources service interface
 type ResourcesService interface {
 	EntityService
@@ -147,6 +242,9 @@ type TasksService interface {
 // TimeEntriesService represents the time entries service interface
 type TimeEntriesService interface {
 	EntityService
+
+	// Submit validates a time entry locally and creates it
+	Submit(ctx context.Context, entry *TimeEntry) (*TimeEntry, error)
 }
 
 // ContractsService represents the contracts service interface
@@ -174,8 +272,25 @@ type Client interface {
 	Resources() ResourcesService
 
 	// Webhooks returns the webhooks service
+	//
+	// Deprecated: use CompanyWebhooks, TicketWebhooks or EnsureWebhooks
 	Webhooks() WebhookService
 
+	// CompanyWebhooks returns the company webhook subscription service
+	CompanyWebhooks() WebhookSubscriptionService
+
+	// ContactWebhooks returns the contact webhook subscription service
+	ContactWebhooks() WebhookSubscriptionService
+
+	// TicketWebhooks returns the ticket webhook subscription service
+	TicketWebhooks() WebhookSubscriptionService
+
+	// TicketNoteWebhooks returns the ticket note webhook subscription service
+	TicketNoteWebhooks() WebhookSubscriptionService
+
+	// ConfigurationItemWebhooks returns the configuration item webhook subscription service
+	ConfigurationItemWebhooks() WebhookSubscriptionService
+
 	// Projects returns the projects service
 	Projects() ProjectsService
 
@@ -198,16 +313,61 @@ type Client interface {
 	SetDebugMode(debug bool)
 
 	// SetLogOutput sets the output writer for the logger
-	SetLogOutput(output *os.File)
+	SetLogOutput(output io.Writer)
+
+	// SetLogger replaces the client's logger with a slog logger
+	SetLogger(logger *slog.Logger)
+
+	// SetLogHandler routes the client's logs to a slog handler
+	SetLogHandler(handler slog.Handler)
+
+	// SetTimeZone sets the tenant's time zone
+	SetTimeZone(loc *time.Location)
+
+	// TimeZone returns the tenant's time zone
+	TimeZone() *time.Location
 
 	// GetZoneInfo gets the zone information for the Autotask account
 	GetZoneInfo() (*ZoneInfo, error)
 
+	// GetFieldInfo returns the field metadata for an entity
+	GetFieldInfo(ctx context.Context, entityName string) ([]FieldInfo, error)
+
 	// NewRequest creates a new HTTP request
 	NewRequest(ctx context.Context, method, url string, body interface{}) (*http.Request, error)
 
 	// Do sends an HTTP request and returns the response
 	Do(req *http.Request, v interface{}) (*http.Response, error)
+
+	// Use appends middleware to the chain applied around every request
+	Use(middleware ...Middleware)
+
+	// SetMiddleware replaces the chain applied around every request
+	SetMiddleware(middleware ...Middleware)
+
+	// Middleware returns the chain applied around every request
+	Middleware() []Middleware
+
+	// SetDryRun turns dry-run mode on or off for all requests
+	SetDryRun(enabled bool)
+
+	// DryRunReport returns the writes recorded in dry-run mode
+	DryRunReport() DryRunReport
+
+	// ResetDryRunReport clears the writes recorded in dry-run mode
+	ResetDryRunReport()
+
+	// SetCache caches the responses of Get and read-only queries
+	SetCache(config *CacheConfig)
+
+	// InvalidateCache drops the cached responses for an entity
+	InvalidateCache(entityName string, id int64)
+
+	// SetCoalescing shares one request between identical concurrent reads
+	SetCoalescing(enabled bool)
+
+	// SetGetBatching combines concurrent Gets for an entity into one query
+	SetGetBatching(window time.Duration, maxSize int)
 }
 
 // ZoneInfo represents the zone information for an Autotask account
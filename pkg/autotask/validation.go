@@ -0,0 +1,209 @@
+package autotask
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FieldError describes a problem with a single field of an entity
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// String returns the field and message
+func (e FieldError) String() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError is returned when an entity fails local validation before
+// it is sent to the API. It lists every problem found, not just the first.
+type ValidationError struct {
+	Entity string
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		problems[i] = fe.String()
+	}
+	return fmt.Sprintf("invalid %s: %s", e.Entity, strings.Join(problems, "; "))
+}
+
+// validationErrorOrNil returns a *ValidationError for errs, or nil when errs is empty
+func validationErrorOrNil(entity string, errs []FieldError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Entity: entity, Errors: errs}
+}
+
+// ValidateFields checks field values against an entity's field metadata the
+// way creates and updates do before they are sent. It returns a
+// *ValidationError listing every problem, or nil.
+func ValidateFields(entityName string, fields []FieldInfo, values map[string]interface{}, create bool) error {
+	return validationErrorOrNil(entityName, validateFields(fields, values, create))
+}
+
+// toFieldMap converts an entity struct or map to a map of its JSON fields
+func toFieldMap(entity interface{}) (map[string]interface{}, error) {
+	if values, ok := entity.(map[string]interface{}); ok {
+		return values, nil
+	}
+
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal entity: %w", err)
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal entity: %w", err)
+	}
+	return values, nil
+}
+
+// decodeItem converts an untyped API item into out
+func decodeItem(item interface{}, out interface{}) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal item: %w", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode item: %w", err)
+	}
+	return nil
+}
+
+// validateFields checks the given field values against entity metadata.
+// Unknown fields, read-only fields, invalid picklist values and over-long
+// strings are reported. When create is true, missing required fields are
+// reported as well.
+func validateFields(fields []FieldInfo, values map[string]interface{}, create bool) []FieldError {
+	var errs []FieldError
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if strings.EqualFold(name, "id") || strings.EqualFold(name, "userDefinedFields") {
+			continue
+		}
+
+		field, ok := FindField(fields, name)
+		if !ok {
+			errs = append(errs, FieldError{Field: name, Message: "unknown field"})
+			continue
+		}
+		value := values[name]
+		if value == nil {
+			continue
+		}
+		if field.IsReadOnly {
+			errs = append(errs, FieldError{Field: name, Message: "field is read-only"})
+			continue
+		}
+		if field.IsPickList && len(field.PicklistValues) > 0 {
+			picklistValue, ok := field.LookupPicklistValue(fieldValueString(value))
+			if !ok {
+				errs = append(errs, FieldError{Field: name, Message: fmt.Sprintf("%v is not a valid picklist value", value)})
+			} else if !picklistValue.IsActive {
+				errs = append(errs, FieldError{Field: name, Message: fmt.Sprintf("picklist value %q is inactive", picklistValue.Label)})
+			}
+		}
+		if s, ok := value.(string); ok && field.Length > 0 && len(s) > field.Length {
+			errs = append(errs, FieldError{Field: name, Message: fmt.Sprintf("exceeds maximum length of %d", field.Length)})
+		}
+	}
+
+	if create {
+		for _, field := range fields {
+			if !field.IsRequired || field.IsReadOnly || strings.EqualFold(field.Name, "id") {
+				continue
+			}
+			if isEmptyFieldValue(lookupFieldValue(values, field.Name)) {
+				errs = append(errs, FieldError{Field: field.Name, Message: "field is required"})
+			}
+		}
+	}
+
+	return errs
+}
+
+// resolvePicklistValue resolves a picklist label or value to its integer value
+func resolvePicklistValue(fields []FieldInfo, fieldName, labelOrValue string) (int, error) {
+	field, ok := FindField(fields, fieldName)
+	if !ok {
+		return 0, fmt.Errorf("unknown field %s", fieldName)
+	}
+
+	picklistValue, ok := field.LookupPicklistValue(labelOrValue)
+	if !ok {
+		return 0, fmt.Errorf("%q is not a valid %s", labelOrValue, fieldName)
+	}
+	if !picklistValue.IsActive {
+		return 0, fmt.Errorf("%s %q is inactive", fieldName, picklistValue.Label)
+	}
+
+	value, err := strconv.Atoi(picklistValue.Value)
+	if err != nil {
+		return 0, fmt.Errorf("%s value %q is not numeric", fieldName, picklistValue.Value)
+	}
+	return value, nil
+}
+
+// LookupField returns the value of a field from the fields of an entity,
+// matching the name case-insensitively since the API doesn't always use
+// the case of the field metadata
+func LookupField[V any](fields map[string]V, name string) (V, bool) {
+	if v, ok := fields[name]; ok {
+		return v, true
+	}
+	for k, v := range fields {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// lookupFieldValue returns the value of a field from values, or nil when it is absent
+func lookupFieldValue(values map[string]interface{}, name string) interface{} {
+	v, _ := LookupField(values, name)
+	return v
+}
+
+// isEmptyFieldValue reports whether a decoded JSON value is absent or zero
+func isEmptyFieldValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	}
+	return false
+}
+
+// fieldValueString formats a decoded JSON value for picklist comparison
+func fieldValueString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
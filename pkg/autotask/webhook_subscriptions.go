@@ -0,0 +1,621 @@
+package autotask
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Webhook is an Autotask webhook subscription, such as a TicketWebhooks entity
+type Webhook struct {
+	ID                                int64  `json:"id,omitempty"`
+	Name                              string `json:"name,omitempty"`
+	WebhookURL                        string `json:"webhookUrl,omitempty"`
+	SecretKey                         string `json:"secretKey,omitempty"`
+	DeactivationURL                   string `json:"deactivationUrl,omitempty"`
+	NotificationEmailAddress          string `json:"notificationEmailAddress,omitempty"`
+	IsActive                          bool   `json:"isActive"`
+	IsReady                           bool   `json:"isReady,omitempty"`
+	IsSubscribedToCreateEvents        bool   `json:"isSubscribedToCreateEvents"`
+	IsSubscribedToUpdateEvents        bool   `json:"isSubscribedToUpdateEvents"`
+	IsSubscribedToDeleteEvents        bool   `json:"isSubscribedToDeleteEvents"`
+	SendThresholdExceededNotification bool   `json:"sendThresholdExceededNotification"`
+	OwnerResourceID                   int64  `json:"ownerResourceID,omitempty"`
+	WebhookGUID                       string `json:"webhookGUID,omitempty"`
+}
+
+// WebhookField selects a standard field for a webhook. Subscribed fields
+// trigger update callbacks when they change; display-always fields are sent
+// with every callback.
+type WebhookField struct {
+	ID                   int64 `json:"id,omitempty"`
+	WebhookID            int64 `json:"webhookID,omitempty"`
+	FieldID              int64 `json:"fieldID,omitempty"`
+	IsDisplayAlwaysField bool  `json:"isDisplayAlwaysField"`
+	IsSubscribedField    bool  `json:"isSubscribedField"`
+}
+
+// WebhookUdfField selects a user-defined field for a webhook
+type WebhookUdfField struct {
+	ID                   int64 `json:"id,omitempty"`
+	WebhookID            int64 `json:"webhookID,omitempty"`
+	UdfFieldID           int64 `json:"udfFieldID,omitempty"`
+	IsDisplayAlwaysField bool  `json:"isDisplayAlwaysField"`
+	IsSubscribedField    bool  `json:"isSubscribedField"`
+}
+
+// WebhookExcludedResource stops changes made by a resource from triggering a webhook
+type WebhookExcludedResource struct {
+	ID         int64 `json:"id,omitempty"`
+	WebhookID  int64 `json:"webhookID,omitempty"`
+	ResourceID int64 `json:"resourceID,omitempty"`
+}
+
+// webhookSubscriptionService manages the webhooks of one entity type
+type webhookSubscriptionService struct {
+	BaseEntityService
+	entityType string
+}
+
+// newWebhookSubscriptionService returns the service for an entity type such as "Ticket"
+func newWebhookSubscriptionService(c Client, entityType string) *webhookSubscriptionService {
+	return &webhookSubscriptionService{
+		BaseEntityService: NewBaseEntityService(c, entityType+"Webhooks"),
+		entityType:        entityType,
+	}
+}
+
+// EntityType returns the entity type the webhooks report on
+func (s *webhookSubscriptionService) EntityType() string {
+	return s.entityType
+}
+
+// ListWebhooks returns all webhooks for the entity type
+func (s *webhookSubscriptionService) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	return FetchAllPages[Webhook](ctx, s, "id>0")
+}
+
+// GetWebhook gets a webhook by ID
+func (s *webhookSubscriptionService) GetWebhook(ctx context.Context, id int64) (*Webhook, error) {
+	item, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("%s %d not found", s.EntityName, id)
+	}
+
+	var webhook Webhook
+	if err := decodeItem(item, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// CreateWebhook creates a webhook and returns it as stored
+func (s *webhookSubscriptionService) CreateWebhook(ctx context.Context, webhook *Webhook) (*Webhook, error) {
+	if err := validateWebhook(s.EntityName, webhook); err != nil {
+		return nil, err
+	}
+
+	created := *webhook
+	created.ID = 0
+	id, err := createEntity(ctx, s.Client, s.EntityName, s.EntityName, nil, &created)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetWebhook(ctx, id)
+}
+
+// UpdateWebhook applies a partial update to a webhook, given as a map of
+// field names to values, and returns the updated webhook
+func (s *webhookSubscriptionService) UpdateWebhook(ctx context.Context, id int64, changes map[string]interface{}) (*Webhook, error) {
+	values := make(map[string]interface{}, len(changes)+1)
+	for k, v := range changes {
+		values[k] = v
+	}
+	values["id"] = id
+
+	if _, err := s.Update(ctx, id, values); err != nil {
+		return nil, err
+	}
+	return s.GetWebhook(ctx, id)
+}
+
+// DeactivateWebhook stops a webhook from sending callbacks
+func (s *webhookSubscriptionService) DeactivateWebhook(ctx context.Context, id int64) error {
+	_, err := s.Update(ctx, id, map[string]interface{}{"id": id, "isActive": false})
+	return err
+}
+
+// ListFields returns the standard fields selected for a webhook
+func (s *webhookSubscriptionService) ListFields(ctx context.Context, webhookID int64) ([]WebhookField, error) {
+	service := NewBaseEntityService(s.Client, s.entityType+"WebhookFields")
+	return FetchAllPages[WebhookField](ctx, &service, fmt.Sprintf("webhookID=%d", webhookID))
+}
+
+// AddField selects a standard field for a webhook
+func (s *webhookSubscriptionService) AddField(ctx context.Context, webhookID int64, field WebhookField) (*WebhookField, error) {
+	field.ID = 0
+	field.WebhookID = webhookID
+	id, err := s.createChild(ctx, webhookID, "Fields", &field)
+	if err != nil {
+		return nil, err
+	}
+	field.ID = id
+	return &field, nil
+}
+
+// RemoveField removes a field selection from a webhook
+func (s *webhookSubscriptionService) RemoveField(ctx context.Context, webhookID, id int64) error {
+	return s.deleteChild(ctx, webhookID, "Fields", id)
+}
+
+// ListUdfFields returns the user-defined fields selected for a webhook
+func (s *webhookSubscriptionService) ListUdfFields(ctx context.Context, webhookID int64) ([]WebhookUdfField, error) {
+	service := NewBaseEntityService(s.Client, s.entityType+"WebhookUdfFields")
+	return FetchAllPages[WebhookUdfField](ctx, &service, fmt.Sprintf("webhookID=%d", webhookID))
+}
+
+// AddUdfField selects a user-defined field for a webhook
+func (s *webhookSubscriptionService) AddUdfField(ctx context.Context, webhookID int64, field WebhookUdfField) (*WebhookUdfField, error) {
+	field.ID = 0
+	field.WebhookID = webhookID
+	id, err := s.createChild(ctx, webhookID, "UdfFields", &field)
+	if err != nil {
+		return nil, err
+	}
+	field.ID = id
+	return &field, nil
+}
+
+// RemoveUdfField removes a user-defined field selection from a webhook
+func (s *webhookSubscriptionService) RemoveUdfField(ctx context.Context, webhookID, id int64) error {
+	return s.deleteChild(ctx, webhookID, "UdfFields", id)
+}
+
+// ListExcludedResources returns the resources whose changes don't trigger a webhook
+func (s *webhookSubscriptionService) ListExcludedResources(ctx context.Context, webhookID int64) ([]WebhookExcludedResource, error) {
+	service := NewBaseEntityService(s.Client, s.entityType+"WebhookExcludedResources")
+	return FetchAllPages[WebhookExcludedResource](ctx, &service, fmt.Sprintf("webhookID=%d", webhookID))
+}
+
+// AddExcludedResource stops changes made by a resource from triggering a webhook
+func (s *webhookSubscriptionService) AddExcludedResource(ctx context.Context, webhookID, resourceID int64) (*WebhookExcludedResource, error) {
+	excluded := WebhookExcludedResource{WebhookID: webhookID, ResourceID: resourceID}
+	id, err := s.createChild(ctx, webhookID, "ExcludedResources", &excluded)
+	if err != nil {
+		return nil, err
+	}
+	excluded.ID = id
+	return &excluded, nil
+}
+
+// RemoveExcludedResource removes a resource exclusion from a webhook
+func (s *webhookSubscriptionService) RemoveExcludedResource(ctx context.Context, webhookID, id int64) error {
+	return s.deleteChild(ctx, webhookID, "ExcludedResources", id)
+}
+
+// FieldID resolves a standard field name to the ID used by webhook field selections
+func (s *webhookSubscriptionService) FieldID(ctx context.Context, name string) (int64, error) {
+	return s.resolveFieldID(ctx, s.entityType+"WebhookFields", "fieldID", name)
+}
+
+// UdfFieldID resolves a user-defined field name to the ID used by webhook field selections
+func (s *webhookSubscriptionService) UdfFieldID(ctx context.Context, name string) (int64, error) {
+	return s.resolveFieldID(ctx, s.entityType+"WebhookUdfFields", "udfFieldID", name)
+}
+
+// resolveFieldID looks up a field name in the picklist of a webhook field entity
+func (s *webhookSubscriptionService) resolveFieldID(ctx context.Context, entityName, fieldName, name string) (int64, error) {
+	fields, err := s.Client.GetFieldInfo(ctx, entityName)
+	if err != nil {
+		return 0, err
+	}
+	value, err := resolvePicklistValue(fields, fieldName, name)
+	if err != nil {
+		return 0, fmt.Errorf("cannot select %s field: %w", s.entityType, err)
+	}
+	return int64(value), nil
+}
+
+// createChild creates a child entity of a webhook, such as a field selection
+func (s *webhookSubscriptionService) createChild(ctx context.Context, webhookID int64, child string, entity interface{}) (int64, error) {
+	path := fmt.Sprintf("%s/%d/%s", s.EntityName, webhookID, child)
+	return createEntity(ctx, s.Client, s.entityType+"Webhook"+child, path, nil, entity)
+}
+
+// deleteChild deletes a child entity of a webhook
+func (s *webhookSubscriptionService) deleteChild(ctx context.Context, webhookID int64, child string, id int64) error {
+	ctx = withOperation(ctx, s.entityType+"Webhook"+child, "delete")
+	path := fmt.Sprintf("%s/%d/%s/%d", s.EntityName, webhookID, child, id)
+	req, err := s.Client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	_, err = s.Client.Do(req, nil)
+	return err
+}
+
+// validateWebhook checks the fields Autotask requires on a new webhook
+func validateWebhook(entityName string, webhook *Webhook) error {
+	if webhook == nil {
+		return &ValidationError{Entity: entityName, Errors: []FieldError{{Message: "webhook is required"}}}
+	}
+
+	var errs []FieldError
+	if webhook.Name == "" {
+		errs = append(errs, FieldError{Field: "name", Message: "name is required"})
+	}
+	if !strings.HasPrefix(webhook.WebhookURL, "https://") {
+		errs = append(errs, FieldError{Field: "webhookUrl", Message: "an https URL is required"})
+	}
+	if webhook.SecretKey == "" {
+		errs = append(errs, FieldError{Field: "secretKey", Message: "secret key is required"})
+	}
+	if !webhook.IsSubscribedToCreateEvents && !webhook.IsSubscribedToUpdateEvents && !webhook.IsSubscribedToDeleteEvents {
+		errs = append(errs, FieldError{Field: "isSubscribedToCreateEvents", Message: "at least one event type is required"})
+	}
+	return validationErrorOrNil(entityName, errs)
+}
+
+// WebhookSpec is the desired state of a webhook subscription for EnsureWebhooks
+type WebhookSpec struct {
+	// Entity is the entity type to subscribe to, such as "Ticket"
+	Entity string
+
+	// Name identifies the webhook; it must be unique per entity type
+	Name string
+
+	URL                      string
+	SecretKey                string
+	DeactivationURL          string
+	NotificationEmailAddress string
+
+	// Events to subscribe to
+	Create bool
+	Update bool
+	Delete bool
+
+	// SubscribedFields trigger update callbacks when they change
+	SubscribedFields []string
+
+	// DisplayFields are sent with every callback
+	DisplayFields []string
+
+	// SubscribedUdfFields and DisplayUdfFields do the same for user-defined fields
+	SubscribedUdfFields []string
+	DisplayUdfFields    []string
+
+	// ExcludedResourceIDs are resources whose changes don't trigger callbacks
+	ExcludedResourceIDs []int64
+
+	SendThresholdExceededNotification bool
+}
+
+// WebhookResult reports what EnsureWebhooks did for one spec
+type WebhookResult struct {
+	Entity    string
+	Name      string
+	WebhookID int64
+	Created   bool
+
+	// Changes lists the settings, fields and exclusions that were changed
+	Changes []string
+}
+
+// Changed reports whether the webhook was created or modified
+func (r WebhookResult) Changed() bool {
+	return r.Created || len(r.Changes) > 0
+}
+
+// WebhookSubscriptions returns the webhook service for an entity type such
+// as "Ticket", or nil when Autotask has no webhooks for it
+func WebhookSubscriptions(c Client, entityType string) WebhookSubscriptionService {
+	switch strings.ToLower(entityType) {
+	case "company":
+		return c.CompanyWebhooks()
+	case "contact":
+		return c.ContactWebhooks()
+	case "ticket":
+		return c.TicketWebhooks()
+	case "ticketnote":
+		return c.TicketNoteWebhooks()
+	case "configurationitem":
+		return c.ConfigurationItemWebhooks()
+	}
+	return nil
+}
+
+// EnsureWebhooks reconciles the webhooks in Autotask with specs. Webhooks
+// are matched by entity type and name; missing ones are created and existing
+// ones are updated so their settings, field selections and excluded
+// resources match the spec. Running it again with the same specs makes no
+// changes. Webhooks that aren't in specs are left alone. Secret keys are only
+// compared when the API returns them.
+func EnsureWebhooks(ctx context.Context, c Client, specs []WebhookSpec) ([]WebhookResult, error) {
+	results := make([]WebhookResult, 0, len(specs))
+	existing := make(map[string][]Webhook)
+
+	for _, spec := range specs {
+		service := WebhookSubscriptions(c, spec.Entity)
+		if service == nil {
+			return results, fmt.Errorf("entity %q does not support webhooks", spec.Entity)
+		}
+
+		webhooks, ok := existing[service.EntityType()]
+		if !ok {
+			var err error
+			webhooks, err = service.ListWebhooks(ctx)
+			if err != nil {
+				return results, err
+			}
+			existing[service.EntityType()] = webhooks
+		}
+
+		result, err := ensureWebhook(ctx, service, spec, webhooks)
+		results = append(results, result)
+		if err != nil {
+			return results, fmt.Errorf("failed to ensure %s webhook %q: %w", spec.Entity, spec.Name, err)
+		}
+	}
+
+	return results, nil
+}
+
+// ensureWebhook reconciles a single webhook with its spec
+func ensureWebhook(ctx context.Context, service WebhookSubscriptionService, spec WebhookSpec, webhooks []Webhook) (WebhookResult, error) {
+	result := WebhookResult{Entity: service.EntityType(), Name: spec.Name}
+	desired := spec.webhook()
+
+	var current *Webhook
+	for i := range webhooks {
+		if strings.EqualFold(webhooks[i].Name, spec.Name) {
+			current = &webhooks[i]
+			break
+		}
+	}
+
+	if current == nil {
+		created, err := service.CreateWebhook(ctx, &desired)
+		if err != nil {
+			return result, err
+		}
+		current = created
+		result.Created = true
+	} else if changes := webhookChanges(current, &desired); len(changes) > 0 {
+		for name := range changes {
+			result.Changes = append(result.Changes, name)
+		}
+		sort.Strings(result.Changes)
+		if _, err := service.UpdateWebhook(ctx, current.ID, changes); err != nil {
+			return result, err
+		}
+	}
+	result.WebhookID = current.ID
+
+	fieldChanges, err := ensureWebhookFields(ctx, service, current.ID, spec)
+	result.Changes = append(result.Changes, fieldChanges...)
+	if err != nil {
+		return result, err
+	}
+
+	udfChanges, err := ensureWebhookUdfFields(ctx, service, current.ID, spec)
+	result.Changes = append(result.Changes, udfChanges...)
+	if err != nil {
+		return result, err
+	}
+
+	excludedChanges, err := ensureWebhookExcludedResources(ctx, service, current.ID, spec)
+	result.Changes = append(result.Changes, excludedChanges...)
+	return result, err
+}
+
+// webhook returns the Webhook described by the spec
+func (spec WebhookSpec) webhook() Webhook {
+	return Webhook{
+		Name:                              spec.Name,
+		WebhookURL:                        spec.URL,
+		SecretKey:                         spec.SecretKey,
+		DeactivationURL:                   spec.DeactivationURL,
+		NotificationEmailAddress:          spec.NotificationEmailAddress,
+		IsActive:                          true,
+		IsSubscribedToCreateEvents:        spec.Create,
+		IsSubscribedToUpdateEvents:        spec.Update,
+		IsSubscribedToDeleteEvents:        spec.Delete,
+		SendThresholdExceededNotification: spec.SendThresholdExceededNotification,
+	}
+}
+
+// webhookChanges returns the fields of current that differ from desired
+func webhookChanges(current, desired *Webhook) map[string]interface{} {
+	changes := make(map[string]interface{})
+	if current.WebhookURL != desired.WebhookURL {
+		changes["webhookUrl"] = desired.WebhookURL
+	}
+	if current.SecretKey != "" && current.SecretKey != desired.SecretKey {
+		changes["secretKey"] = desired.SecretKey
+	}
+	if current.DeactivationURL != desired.DeactivationURL {
+		changes["deactivationUrl"] = desired.DeactivationURL
+	}
+	if current.NotificationEmailAddress != desired.NotificationEmailAddress {
+		changes["notificationEmailAddress"] = desired.NotificationEmailAddress
+	}
+	if current.IsActive != desired.IsActive {
+		changes["isActive"] = desired.IsActive
+	}
+	if current.IsSubscribedToCreateEvents != desired.IsSubscribedToCreateEvents {
+		changes["isSubscribedToCreateEvents"] = desired.IsSubscribedToCreateEvents
+	}
+	if current.IsSubscribedToUpdateEvents != desired.IsSubscribedToUpdateEvents {
+		changes["isSubscribedToUpdateEvents"] = desired.IsSubscribedToUpdateEvents
+	}
+	if current.IsSubscribedToDeleteEvents != desired.IsSubscribedToDeleteEvents {
+		changes["isSubscribedToDeleteEvents"] = desired.IsSubscribedToDeleteEvents
+	}
+	if current.SendThresholdExceededNotification != desired.SendThresholdExceededNotification {
+		changes["sendThresholdExceededNotification"] = desired.SendThresholdExceededNotification
+	}
+	return changes
+}
+
+// fieldSelection is the desired flags for a selected webhook field
+type fieldSelection struct {
+	name      string
+	display   bool
+	subscribe bool
+}
+
+// desiredSelections combines subscribed and display field names into selections
+func desiredSelections(subscribed, display []string) []*fieldSelection {
+	var selections []*fieldSelection
+	byName := make(map[string]*fieldSelection)
+	get := func(name string) *fieldSelection {
+		key := strings.ToLower(name)
+		if sel, ok := byName[key]; ok {
+			return sel
+		}
+		sel := &fieldSelection{name: name}
+		byName[key] = sel
+		selections = append(selections, sel)
+		return sel
+	}
+	for _, name := range subscribed {
+		get(name).subscribe = true
+	}
+	for _, name := range display {
+		get(name).display = true
+	}
+	return selections
+}
+
+// ensureWebhookFields reconciles the standard field selections of a webhook
+func ensureWebhookFields(ctx context.Context, service WebhookSubscriptionService, webhookID int64, spec WebhookSpec) ([]string, error) {
+	desired := make(map[int64]*fieldSelection)
+	for _, sel := range desiredSelections(spec.SubscribedFields, spec.DisplayFields) {
+		id, err := service.FieldID(ctx, sel.name)
+		if err != nil {
+			return nil, err
+		}
+		desired[id] = sel
+	}
+
+	current, err := service.ListFields(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for _, field := range current {
+		sel, ok := desired[field.FieldID]
+		if ok && sel.display == field.IsDisplayAlwaysField && sel.subscribe == field.IsSubscribedField {
+			delete(desired, field.FieldID)
+			continue
+		}
+		if err := service.RemoveField(ctx, webhookID, field.ID); err != nil {
+			return changes, err
+		}
+		if !ok {
+			changes = append(changes, "-field:"+strconv.FormatInt(field.FieldID, 10))
+		}
+	}
+
+	for _, id := range sortedKeys(desired) {
+		sel := desired[id]
+		field := WebhookField{FieldID: id, IsDisplayAlwaysField: sel.display, IsSubscribedField: sel.subscribe}
+		if _, err := service.AddField(ctx, webhookID, field); err != nil {
+			return changes, err
+		}
+		changes = append(changes, "field:"+sel.name)
+	}
+	return changes, nil
+}
+
+// ensureWebhookUdfFields reconciles the user-defined field selections of a webhook
+func ensureWebhookUdfFields(ctx context.Context, service WebhookSubscriptionService, webhookID int64, spec WebhookSpec) ([]string, error) {
+	desired := make(map[int64]*fieldSelection)
+	for _, sel := range desiredSelections(spec.SubscribedUdfFields, spec.DisplayUdfFields) {
+		id, err := service.UdfFieldID(ctx, sel.name)
+		if err != nil {
+			return nil, err
+		}
+		desired[id] = sel
+	}
+
+	current, err := service.ListUdfFields(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for _, field := range current {
+		sel, ok := desired[field.UdfFieldID]
+		if ok && sel.display == field.IsDisplayAlwaysField && sel.subscribe == field.IsSubscribedField {
+			delete(desired, field.UdfFieldID)
+			continue
+		}
+		if err := service.RemoveUdfField(ctx, webhookID, field.ID); err != nil {
+			return changes, err
+		}
+		if !ok {
+			changes = append(changes, "-udf:"+strconv.FormatInt(field.UdfFieldID, 10))
+		}
+	}
+
+	for _, id := range sortedKeys(desired) {
+		sel := desired[id]
+		field := WebhookUdfField{UdfFieldID: id, IsDisplayAlwaysField: sel.display, IsSubscribedField: sel.subscribe}
+		if _, err := service.AddUdfField(ctx, webhookID, field); err != nil {
+			return changes, err
+		}
+		changes = append(changes, "udf:"+sel.name)
+	}
+	return changes, nil
+}
+
+// ensureWebhookExcludedResources reconciles the excluded resources of a webhook
+func ensureWebhookExcludedResources(ctx context.Context, service WebhookSubscriptionService, webhookID int64, spec WebhookSpec) ([]string, error) {
+	desired := make(map[int64]bool)
+	for _, id := range spec.ExcludedResourceIDs {
+		desired[id] = true
+	}
+
+	current, err := service.ListExcludedResources(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for _, excluded := range current {
+		if desired[excluded.ResourceID] {
+			delete(desired, excluded.ResourceID)
+			continue
+		}
+		if err := service.RemoveExcludedResource(ctx, webhookID, excluded.ID); err != nil {
+			return changes, err
+		}
+		changes = append(changes, "-excluded:"+strconv.FormatInt(excluded.ResourceID, 10))
+	}
+
+	for _, id := range sortedKeys(desired) {
+		if _, err := service.AddExcludedResource(ctx, webhookID, id); err != nil {
+			return changes, err
+		}
+		changes = append(changes, "excluded:"+strconv.FormatInt(id, 10))
+	}
+	return changes, nil
+}
+
+// sortedKeys returns the keys of m in ascending order
+func sortedKeys[V any](m map[int64]V) []int64 {
+	keys := make([]int64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
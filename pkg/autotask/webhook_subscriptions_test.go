@@ -0,0 +1,190 @@
+package autotask
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeWebhookStore holds the ticket webhooks served by newFakeWebhookServer
+type fakeWebhookStore struct {
+	nextID   int64
+	webhooks map[int64]map[string]interface{}
+	children map[string]map[int64]map[string]interface{}
+	writes   int
+}
+
+// newFakeWebhookServer serves TicketWebhooks and their child entities from memory
+func newFakeWebhookServer(t *testing.T) (*MockServer, *fakeWebhookStore) {
+	server := NewMockServer(t)
+	store := &fakeWebhookStore{
+		nextID:   100,
+		webhooks: make(map[int64]map[string]interface{}),
+		children: map[string]map[int64]map[string]interface{}{
+			"Fields":            {},
+			"UdfFields":         {},
+			"ExcludedResources": {},
+		},
+	}
+
+	list := func(w http.ResponseWriter, items map[int64]map[string]interface{}, webhookID int64) {
+		result := []map[string]interface{}{}
+		for _, item := range items {
+			if webhookID == 0 || item["webhookID"] == float64(webhookID) {
+				result = append(result, item)
+			}
+		}
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"items": result, "pageDetails": PageDetails{Count: len(result)}})
+	}
+	decode := func(r *http.Request) map[string]interface{} {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		return body
+	}
+
+	server.AddHandler("/TicketWebhooks", func(w http.ResponseWriter, r *http.Request) {
+		store.writes++
+		store.nextID++
+		body := decode(r)
+		body["id"] = float64(store.nextID)
+		body["secretKey"] = ""
+		store.webhooks[store.nextID] = body
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"itemId": store.nextID})
+	})
+	server.AddHandler("/TicketWebhooks/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/atservicesrest/v1.0"), "/")[2:]
+		if parts[0] == "query" {
+			list(w, store.webhooks, 0)
+			return
+		}
+		id, _ := strconv.ParseInt(parts[0], 10, 64)
+		if len(parts) == 1 {
+			if r.Method == http.MethodPatch {
+				store.writes++
+				for k, v := range decode(r) {
+					store.webhooks[id][k] = v
+				}
+				server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"itemId": id})
+				return
+			}
+			server.RespondWithJSON(w, http.StatusOK, Response{Item: store.webhooks[id]})
+			return
+		}
+
+		children := store.children[parts[1]]
+		store.writes++
+		if r.Method == http.MethodDelete {
+			childID, _ := strconv.ParseInt(parts[2], 10, 64)
+			delete(children, childID)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		store.nextID++
+		body := decode(r)
+		body["id"] = float64(store.nextID)
+		children[store.nextID] = body
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"itemId": store.nextID})
+	})
+	for _, child := range []string{"Fields", "UdfFields", "ExcludedResources"} {
+		child := child
+		server.AddHandler("/TicketWebhook"+child+"/query", func(w http.ResponseWriter, r *http.Request) {
+			var search EntityQueryParams
+			_ = json.Unmarshal([]byte(r.URL.Query().Get("search")), &search)
+			filter := search.Filter[0].(map[string]interface{})
+			webhookID, _ := strconv.ParseInt(fmt.Sprint(filter["value"]), 10, 64)
+			list(w, store.children[child], webhookID)
+		})
+	}
+	server.AddHandler("/TicketWebhookFields/entityInformation/fields", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"fields": []FieldInfo{
+			{Name: "fieldID", IsPickList: true, PicklistValues: []PicklistValue{
+				{Value: "1", Label: "Status", IsActive: true},
+				{Value: "2", Label: "Title", IsActive: true},
+				{Value: "3", Label: "Priority", IsActive: true},
+			}},
+		}})
+	})
+	server.AddHandler("/TicketWebhookUdfFields/entityInformation/fields", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"fields": []FieldInfo{
+			{Name: "udfFieldID", IsPickList: true, PicklistValues: []PicklistValue{
+				{Value: "50", Label: "Escalation Reason", IsActive: true},
+			}},
+		}})
+	})
+
+	return server, store
+}
+
+func TestEnsureWebhooks(t *testing.T) {
+	server, store := newFakeWebhookServer(t)
+	defer server.Close()
+
+	client := server.NewTestClient()
+	ctx := context.Background()
+
+	spec := WebhookSpec{
+		Entity:              "Ticket",
+		Name:                "ticket-sync",
+		URL:                 "https://example.com/hooks/tickets",
+		SecretKey:           "s3cret",
+		Create:              true,
+		Update:              true,
+		SubscribedFields:    []string{"Status", "Priority"},
+		DisplayFields:       []string{"Title", "Status"},
+		SubscribedUdfFields: []string{"Escalation Reason"},
+		ExcludedResourceIDs: []int64{7},
+	}
+
+	results, err := EnsureWebhooks(ctx, client, []WebhookSpec{spec})
+	AssertNil(t, err, "error should be nil")
+	AssertTrue(t, results[0].Created, "webhook should be created")
+	AssertEqual(t, 1, len(store.webhooks), "one webhook should exist")
+	AssertEqual(t, 3, len(store.children["Fields"]), "field selections should be created")
+	AssertEqual(t, 1, len(store.children["UdfFields"]), "udf selection should be created")
+	AssertEqual(t, 1, len(store.children["ExcludedResources"]), "excluded resource should be created")
+	for _, field := range store.children["Fields"] {
+		if field["fieldID"] == float64(1) {
+			AssertEqual(t, true, field["isSubscribedField"], "status should be subscribed")
+			AssertEqual(t, true, field["isDisplayAlwaysField"], "status should always be displayed")
+		}
+	}
+
+	writes := store.writes
+	results, err = EnsureWebhooks(ctx, client, []WebhookSpec{spec})
+	AssertNil(t, err, "error should be nil")
+	AssertFalse(t, results[0].Changed(), "second run should make no changes")
+	AssertEqual(t, writes, store.writes, "second run should not write")
+
+	spec.URL = "https://example.com/hooks/v2/tickets"
+	spec.DisplayFields = nil
+	spec.ExcludedResourceIDs = nil
+	results, err = EnsureWebhooks(ctx, client, []WebhookSpec{spec})
+	AssertNil(t, err, "error should be nil")
+	AssertEqual(t, "webhookUrl", results[0].Changes[0], "URL change should be reported")
+	AssertEqual(t, 2, len(store.children["Fields"]), "display-only field should be removed")
+	AssertEqual(t, 0, len(store.children["ExcludedResources"]), "exclusion should be removed")
+	for _, webhook := range store.webhooks {
+		AssertEqual(t, spec.URL, webhook["webhookUrl"], "URL should be updated")
+	}
+}
+
+func TestEnsureWebhooksInvalidSpec(t *testing.T) {
+	server, store := newFakeWebhookServer(t)
+	defer server.Close()
+
+	client := server.NewTestClient()
+
+	_, err := EnsureWebhooks(context.Background(), client, []WebhookSpec{{Entity: "Invoice", Name: "x"}})
+	AssertNotNil(t, err, "unsupported entity should be rejected")
+
+	_, err = EnsureWebhooks(context.Background(), client, []WebhookSpec{{Entity: "Ticket", Name: "insecure", URL: "http://example.com"}})
+	var validationErr *ValidationError
+	AssertTrue(t, errors.As(err, &validationErr), "error should be a *ValidationError")
+	AssertEqual(t, 3, len(validationErr.Errors), "all problems should be reported")
+	AssertEqual(t, 0, store.writes, "invalid webhook should not be sent")
+}
@@ -0,0 +1,217 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+	"github.com/parquet-go/parquet-go"
+)
+
+// csvEncoder writes a header row followed by one row per entity
+type csvEncoder struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVEncoder(w io.Writer, columns []column) (*csvEncoder, error) {
+	enc := &csvEncoder{w: csv.NewWriter(w), record: make([]string, len(columns))}
+	for i, c := range columns {
+		enc.record[i] = c.name
+	}
+	if err := enc.w.Write(enc.record); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	return enc, nil
+}
+
+func (e *csvEncoder) write(values []interface{}) error {
+	for i, value := range values {
+		e.record[i] = formatText(value)
+	}
+	return e.w.Write(e.record)
+}
+
+func (e *csvEncoder) close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// formatText formats a converted value as CSV text. Nulls are empty.
+func formatText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	case json.RawMessage:
+		return string(v)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
+
+// jsonlEncoder writes one JSON object per line with the columns in order
+type jsonlEncoder struct {
+	w       *bufio.Writer
+	columns []column
+	buf     bytes.Buffer
+}
+
+func newJSONLEncoder(w io.Writer, columns []column) *jsonlEncoder {
+	return &jsonlEncoder{w: bufio.NewWriter(w), columns: columns}
+}
+
+func (e *jsonlEncoder) write(values []interface{}) error {
+	e.buf.Reset()
+	e.buf.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(e.columns[i].name)
+		e.buf.Write(name)
+		e.buf.WriteByte(':')
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		e.buf.Write(data)
+	}
+	e.buf.WriteString("}\n")
+	_, err := e.w.Write(e.buf.Bytes())
+	return err
+}
+
+func (e *jsonlEncoder) close() error {
+	return e.w.Flush()
+}
+
+// parquetEncoder writes entities as rows of a schema built from the
+// columns. Every column is optional; date-times are millisecond UTC
+// timestamps and dates are dates.
+type parquetEncoder struct {
+	w       *parquet.Writer
+	columns []column
+	index   []int
+	rows    []parquet.Row
+}
+
+// parquetRowBuffer is the number of rows passed to the writer at once
+const parquetRowBuffer = 500
+
+// parquetRowGroupSize is the most rows in one row group. The writer keeps
+// the current row group in memory, so large exports are split into groups.
+var parquetRowGroupSize int64 = 100000
+
+func newParquetEncoder(w io.Writer, name string, columns []column) *parquetEncoder {
+	group := make(parquet.Group, len(columns))
+	for _, c := range columns {
+		group[c.name] = parquet.Optional(parquetNode(c))
+	}
+	schema := parquet.NewSchema(name, group)
+
+	// Group fields are ordered by name; map each column to its leaf
+	leaves := make(map[string]int)
+	for i, path := range schema.Columns() {
+		leaves[path[0]] = i
+	}
+	index := make([]int, len(columns))
+	for i, c := range columns {
+		index[i] = leaves[c.name]
+	}
+
+	return &parquetEncoder{w: parquet.NewWriter(w, schema, parquet.MaxRowsPerRowGroup(parquetRowGroupSize)), columns: columns, index: index}
+}
+
+// parquetNode returns the Parquet type of a column
+func parquetNode(c column) parquet.Node {
+	switch c.dataType {
+	case "integer", "long", "short", "byte":
+		return parquet.Int(64)
+	case "double", "decimal", "float":
+		return parquet.Leaf(parquet.DoubleType)
+	case "boolean":
+		return parquet.Leaf(parquet.BooleanType)
+	case "datetime":
+		return parquet.Timestamp(parquet.Millisecond)
+	case "date":
+		return parquet.Date()
+	}
+	return parquet.String()
+}
+
+func (e *parquetEncoder) write(values []interface{}) error {
+	row := make(parquet.Row, len(values))
+	for i, value := range values {
+		leaf := e.index[i]
+		v, err := parquetValue(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s value: %w", e.columns[i].name, err)
+		}
+		if v.IsNull() {
+			row[leaf] = v.Level(0, 0, leaf)
+		} else {
+			row[leaf] = v.Level(0, 1, leaf)
+		}
+	}
+
+	e.rows = append(e.rows, row)
+	if len(e.rows) >= parquetRowBuffer {
+		return e.flush()
+	}
+	return nil
+}
+
+// flush passes the buffered rows to the writer
+func (e *parquetEncoder) flush() error {
+	if len(e.rows) == 0 {
+		return nil
+	}
+	_, err := e.w.WriteRows(e.rows)
+	e.rows = e.rows[:0]
+	return err
+}
+
+func (e *parquetEncoder) close() error {
+	if err := e.flush(); err != nil {
+		return err
+	}
+	return e.w.Close()
+}
+
+// parquetValue converts a value for its Parquet column
+func parquetValue(value interface{}) (parquet.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return parquet.NullValue(), nil
+	case bool:
+		return parquet.BooleanValue(v), nil
+	case int64:
+		return parquet.Int64Value(v), nil
+	case float64:
+		return parquet.DoubleValue(v), nil
+	case string:
+		return parquet.ByteArrayValue([]byte(v)), nil
+	case json.RawMessage:
+		return parquet.ByteArrayValue(v), nil
+	case autotask.DateTime:
+		return parquet.Int64Value(v.UnixMilli()), nil
+	case autotask.Date:
+		return parquet.Int32Value(int32(v.In(time.UTC).Unix() / 86400)), nil
+	}
+	return parquet.Value{}, fmt.Errorf("unsupported value %T", value)
+}
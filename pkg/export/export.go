@@ -0,0 +1,267 @@
+// Package export streams Autotask query results to CSV, newline-delimited
+// JSON or Parquet. Results are fetched and written one page at a time, so
+// memory use doesn't grow with the number of entities exported.
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+// Format is an export file format
+type Format string
+
+// Supported formats
+const (
+	CSV     Format = "csv"
+	JSONL   Format = "jsonl"
+	Parquet Format = "parquet"
+)
+
+// udfField is the entity field holding user-defined fields. It isn't in the
+// field metadata but can be selected as a column.
+const udfField = "userDefinedFields"
+
+// Options configures an export
+type Options struct {
+	// Format defaults to CSV
+	Format Format
+
+	// Columns names the fields to export, in order. By default every field
+	// in the entity's metadata is exported. userDefinedFields exports the
+	// UDFs as JSON.
+	Columns []string
+
+	// PicklistLabels writes the labels of picklist values instead of the
+	// stored values
+	PicklistLabels bool
+}
+
+// FormatFromPath returns the format for a file name's extension
+func FormatFromPath(path string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return CSV, true
+	case ".jsonl", ".ndjson":
+		return JSONL, true
+	case ".parquet":
+		return Parquet, true
+	}
+	return "", false
+}
+
+// column is an exported field
+type column struct {
+	name     string
+	dataType string
+	field    autotask.FieldInfo
+
+	// labels is set when picklist values are written as labels
+	labels bool
+}
+
+// encoder writes rows in one format
+type encoder interface {
+	write(values []interface{}) error
+	close() error
+}
+
+// Write runs a query against service and writes the matching entities to w.
+// It returns the number of entities written.
+func Write(ctx context.Context, w io.Writer, service autotask.EntityService, filter string, opts Options) (int, error) {
+	columns, err := resolveColumns(ctx, service, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	var enc encoder
+	switch opts.Format {
+	case CSV, "":
+		enc, err = newCSVEncoder(w, columns)
+	case JSONL:
+		enc = newJSONLEncoder(w, columns)
+	case Parquet:
+		enc = newParquetEncoder(w, service.GetEntityName(), columns)
+	default:
+		return 0, fmt.Errorf("unsupported export format %q", opts.Format)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = autotask.FetchAllPagesWithCallback(ctx, service, filter,
+		func(items []map[string]json.RawMessage, _ autotask.PageDetails) error {
+			for _, item := range items {
+				values := make([]interface{}, len(columns))
+				for i, c := range columns {
+					raw, _ := autotask.LookupField(item, c.name)
+					value, err := convert(raw, c)
+					if err != nil {
+						return fmt.Errorf("invalid %s field %s: %w", service.GetEntityName(), c.name, err)
+					}
+					values[i] = value
+				}
+				if err := enc.write(values); err != nil {
+					return fmt.Errorf("failed to write %s: %w", service.GetEntityName(), err)
+				}
+				count++
+			}
+			return nil
+		})
+	if err != nil {
+		return count, fmt.Errorf("failed to export %s: %w", service.GetEntityName(), err)
+	}
+	if err := enc.close(); err != nil {
+		return count, fmt.Errorf("failed to finish %s export: %w", service.GetEntityName(), err)
+	}
+	return count, nil
+}
+
+// WriteFile exports to the file at path. When opts.Format is empty it is
+// chosen from the file extension.
+func WriteFile(ctx context.Context, path string, service autotask.EntityService, filter string, opts Options) (int, error) {
+	if opts.Format == "" {
+		format, ok := FormatFromPath(path)
+		if !ok {
+			return 0, fmt.Errorf("can't tell the export format of %s; set Options.Format", path)
+		}
+		opts.Format = format
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create export file: %w", err)
+	}
+	count, err := Write(ctx, f, service, filter, opts)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close export file: %w", closeErr)
+	}
+	return count, err
+}
+
+// resolveColumns looks up the exported fields in the entity metadata
+func resolveColumns(ctx context.Context, service autotask.EntityService, opts Options) ([]column, error) {
+	fields, err := service.GetFieldInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s fields: %w", service.GetEntityName(), err)
+	}
+
+	names := opts.Columns
+	if len(names) == 0 {
+		for _, field := range fields {
+			names = append(names, field.Name)
+		}
+	}
+
+	columns := make([]column, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if seen[strings.ToLower(name)] {
+			return nil, fmt.Errorf("column %s is listed more than once", name)
+		}
+		seen[strings.ToLower(name)] = true
+
+		if strings.EqualFold(name, udfField) {
+			columns = append(columns, column{name: udfField, dataType: "udf"})
+			continue
+		}
+		field, ok := autotask.FindField(fields, name)
+		if !ok {
+			return nil, fmt.Errorf("%s has no field %s", service.GetEntityName(), name)
+		}
+		c := column{name: field.Name, dataType: strings.ToLower(field.DataType), field: field}
+		if opts.PicklistLabels && field.IsPickList {
+			c.labels = true
+			c.dataType = "string"
+		}
+		columns = append(columns, c)
+	}
+	return columns, nil
+}
+
+// convert decodes a field value for c. Values are nil, bool, int64,
+// float64, string, autotask.DateTime, autotask.Date or, for UDFs,
+// json.RawMessage.
+func convert(raw json.RawMessage, c column) (interface{}, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if c.dataType == "udf" {
+		return json.RawMessage(raw), nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	if c.labels {
+		stored := fmt.Sprint(value)
+		if option, ok := c.field.LookupPicklistValue(stored); ok {
+			return option.Label, nil
+		}
+		return stored, nil
+	}
+
+	switch c.dataType {
+	case "integer", "long", "short", "byte":
+		n, ok := value.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("expected a number, got %s", raw)
+		}
+		return n.Int64()
+	case "double", "decimal", "float":
+		n, ok := value.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("expected a number, got %s", raw)
+		}
+		return n.Float64()
+	case "boolean":
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case json.Number:
+			return v.String() != "0", nil
+		}
+		return nil, fmt.Errorf("expected a boolean, got %s", raw)
+	case "datetime":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a date-time, got %s", raw)
+		}
+		if s == "" {
+			return nil, nil
+		}
+		return autotask.ParseDateTime(s)
+	case "date":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a date, got %s", raw)
+		}
+		if s == "" {
+			return nil, nil
+		}
+		return autotask.ParseDate(s)
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	if n, ok := value.(json.Number); ok {
+		return n.String(), nil
+	}
+	return string(raw), nil
+}
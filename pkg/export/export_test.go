@@ -0,0 +1,148 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+	"github.com/parquet-go/parquet-go"
+)
+
+// newTicketServer serves ticket metadata and two pages of tickets
+func newTicketServer(t *testing.T) *autotask.MockServer {
+	server := autotask.NewMockServer(t)
+	server.AddHandler("/Tickets/entityInformation/fields", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"fields": []map[string]interface{}{
+			{"name": "id", "dataType": "long"},
+			{"name": "title", "dataType": "string"},
+			{"name": "status", "dataType": "integer", "isPickList": true, "picklistValues": []map[string]interface{}{
+				{"value": "1", "label": "New", "isActive": true},
+				{"value": "5", "label": "Complete", "isActive": true},
+			}},
+			{"name": "isBillable", "dataType": "boolean"},
+			{"name": "estimatedHours", "dataType": "decimal"},
+			{"name": "dueDateTime", "dataType": "datetime"},
+		}})
+	})
+	server.AddHandler("/Tickets/query", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"id": 3, "title": "VPN", "status": 5, "isBillable": false, "estimatedHours": 0.25, "dueDateTime": nil,
+						"userDefinedFields": []map[string]interface{}{{"name": "Region", "value": "North"}}},
+				},
+				"pageDetails": map[string]interface{}{},
+			})
+			return
+		}
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"items": []map[string]interface{}{
+				{"id": 1, "title": "Printer, jammed", "status": 1, "isBillable": true, "estimatedHours": 1.5, "dueDateTime": "2025-03-01T10:00:00Z"},
+				{"id": 2, "title": "Email", "status": 7, "isBillable": true, "estimatedHours": 2, "dueDateTime": "2025-03-02T09:30:00.000Z"},
+			},
+			"pageDetails": map[string]interface{}{"nextPageUrl": "/Tickets/query?page=2"},
+		})
+	})
+	return server
+}
+
+func TestWriteCSV(t *testing.T) {
+	server := newTicketServer(t)
+	defer server.Close()
+
+	var buf bytes.Buffer
+	count, err := Write(context.Background(), &buf, server.NewTestClient().Tickets(), "", Options{
+		Columns:        []string{"id", "Title", "status", "dueDateTime"},
+		PicklistLabels: true,
+	})
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, 3, count, "every page should be exported")
+
+	want := "id,title,status,dueDateTime\n" +
+		"1,\"Printer, jammed\",New,2025-03-01T10:00:00.000Z\n" +
+		"2,Email,7,2025-03-02T09:30:00.000Z\n" +
+		"3,VPN,Complete,\n"
+	autotask.AssertEqual(t, want, buf.String(), "CSV should have the selected columns with picklist labels")
+
+	_, err = Write(context.Background(), &buf, server.NewTestClient().Tickets(), "", Options{Columns: []string{"nope"}})
+	autotask.AssertNotNil(t, err, "unknown columns should be rejected")
+}
+
+func TestWriteJSONL(t *testing.T) {
+	server := newTicketServer(t)
+	defer server.Close()
+
+	var buf bytes.Buffer
+	_, err := Write(context.Background(), &buf, server.NewTestClient().Tickets(), "", Options{
+		Format:  JSONL,
+		Columns: []string{"id", "status", "estimatedHours", "userDefinedFields"},
+	})
+	autotask.AssertNil(t, err, "error should be nil")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	autotask.AssertEqual(t, 3, len(lines), "one line per entity")
+	autotask.AssertEqual(t, `{"id":1,"status":1,"estimatedHours":1.5,"userDefinedFields":null}`, lines[0], "columns should keep their order")
+	autotask.AssertEqual(t, `{"id":3,"status":5,"estimatedHours":0.25,"userDefinedFields":[{"name":"Region","value":"North"}]}`, lines[2], "UDFs should be exported as JSON")
+}
+
+func TestWriteParquet(t *testing.T) {
+	server := newTicketServer(t)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "tickets.parquet")
+	count, err := WriteFile(context.Background(), path, server.NewTestClient().Tickets(), "", Options{})
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, 3, count, "every page should be exported")
+
+	type ticketRow struct {
+		ID             *int64   `parquet:"id,optional"`
+		Title          *string  `parquet:"title,optional"`
+		IsBillable     *bool    `parquet:"isBillable,optional"`
+		EstimatedHours *float64 `parquet:"estimatedHours,optional"`
+		DueDateTime    *int64   `parquet:"dueDateTime,optional"`
+	}
+	f, err := os.Open(path)
+	autotask.AssertNil(t, err, "error should be nil")
+	defer f.Close()
+	info, err := f.Stat()
+	autotask.AssertNil(t, err, "error should be nil")
+
+	rows, err := parquet.Read[ticketRow](f, info.Size())
+	autotask.AssertNil(t, err, "file should be valid Parquet")
+	autotask.AssertEqual(t, 3, len(rows), "every entity should be a row")
+	autotask.AssertEqual(t, int64(2), *rows[1].ID, "id should be an integer")
+	autotask.AssertEqual(t, "Printer, jammed", *rows[0].Title, "title should be a string")
+	autotask.AssertEqual(t, false, *rows[2].IsBillable, "boolean should be kept")
+	autotask.AssertEqual(t, 2.0, *rows[1].EstimatedHours, "decimal should be a double")
+	autotask.AssertEqual(t, time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC).UnixMilli(), *rows[0].DueDateTime, "date-time should be a millisecond timestamp")
+	autotask.AssertTrue(t, rows[2].DueDateTime == nil, "null should be kept")
+}
+
+func TestWriteParquetRowGroups(t *testing.T) {
+	server := newTicketServer(t)
+	defer server.Close()
+
+	defer func(size int64) { parquetRowGroupSize = size }(parquetRowGroupSize)
+	parquetRowGroupSize = 2
+
+	path := filepath.Join(t.TempDir(), "tickets.parquet")
+	_, err := WriteFile(context.Background(), path, server.NewTestClient().Tickets(), "", Options{})
+	autotask.AssertNil(t, err, "error should be nil")
+
+	f, err := os.Open(path)
+	autotask.AssertNil(t, err, "error should be nil")
+	defer f.Close()
+	info, err := f.Stat()
+	autotask.AssertNil(t, err, "error should be nil")
+
+	file, err := parquet.OpenFile(f, info.Size())
+	autotask.AssertNil(t, err, "file should be valid Parquet")
+	autotask.AssertEqual(t, 2, len(file.RowGroups()), "rows should be split into row groups")
+	autotask.AssertEqual(t, int64(3), file.NumRows(), "every entity should be a row")
+}
@@ -0,0 +1,437 @@
+// Package importer creates Autotask entities from CSV or JSON records.
+//
+// A declarative Mapping turns source columns into entity fields, with
+// transforms such as picklist label to value and lookups such as company
+// name to companyID. Plan applies the mapping and validates every row
+// against the entity's field metadata without writing anything, which
+// serves as a dry run. Apply then creates the valid rows with
+// autotask.BulkCreate, and WriteResults records the outcome of each row.
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+// Status is the outcome of importing one row
+type Status string
+
+// Row statuses
+const (
+	// StatusCreated rows were created
+	StatusCreated Status = "created"
+
+	// StatusPlanned rows would have been created, in dry-run mode
+	StatusPlanned Status = "planned"
+
+	// StatusInvalid rows failed mapping or validation and were not sent
+	StatusInvalid Status = "invalid"
+
+	// StatusFailed rows were rejected by the API
+	StatusFailed Status = "failed"
+
+	// StatusSkipped rows were not sent after an earlier failure
+	StatusSkipped Status = "skipped"
+)
+
+// lookupBatchSize is the number of values resolved per lookup query
+const lookupBatchSize = 50
+
+// Row is a mapped source record
+type Row struct {
+	Line   int
+	Values map[string]interface{}
+
+	// Err is a *autotask.ValidationError listing the row's problems, or nil
+	Err error
+}
+
+// Plan is the result of mapping and validating records. It is the dry-run
+// report of an import.
+type Plan struct {
+	Entity string
+	Rows   []Row
+}
+
+// Valid returns the rows that can be created
+func (p *Plan) Valid() []Row {
+	var rows []Row
+	for _, row := range p.Rows {
+		if row.Err == nil {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Invalid returns the rows that failed mapping or validation
+func (p *Plan) Invalid() []Row {
+	var rows []Row
+	for _, row := range p.Rows {
+		if row.Err != nil {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// String summarizes the plan with one line per invalid row
+func (p *Plan) String() string {
+	invalid := p.Invalid()
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %s rows: %d valid, %d invalid", len(p.Rows), p.Entity, len(p.Rows)-len(invalid), len(invalid))
+	for _, row := range invalid {
+		fmt.Fprintf(&b, "\n  line %d: %v", row.Line, row.Err)
+	}
+	return b.String()
+}
+
+// Result is the outcome of importing one row
+type Result struct {
+	Line   int
+	Status Status
+	ID     int64
+	Err    error
+}
+
+// Importer creates entities from records using a mapping
+type Importer struct {
+	client  autotask.Client
+	mapping Mapping
+}
+
+// New returns an importer that creates mapping.Entity entities through client
+func New(client autotask.Client, mapping Mapping) *Importer {
+	return &Importer{client: client, mapping: mapping}
+}
+
+// Plan maps and validates records without writing anything. Problems with
+// individual rows are reported in the plan; an error is returned only when
+// the mapping itself is unusable or metadata and lookups can't be fetched.
+func (i *Importer) Plan(ctx context.Context, records []Record) (*Plan, error) {
+	if err := i.mapping.Validate(); err != nil {
+		return nil, err
+	}
+
+	entity := i.mapping.Entity
+	fields, err := i.client.GetFieldInfo(ctx, entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s fields: %w", entity, err)
+	}
+	targets := make([]autotask.FieldInfo, len(i.mapping.Fields))
+	for n, fm := range i.mapping.Fields {
+		field, ok := autotask.FindField(fields, fm.Field)
+		if !ok {
+			return nil, fmt.Errorf("mapping: %s has no field %s", entity, fm.Field)
+		}
+		targets[n] = field
+	}
+
+	// Map every row first so each lookup can be resolved in batches
+	plan := &Plan{Entity: entity, Rows: make([]Row, len(records))}
+	problems := make([][]autotask.FieldError, len(records))
+	for r, record := range records {
+		row := Row{Line: record.Line, Values: make(map[string]interface{}, len(i.mapping.Fields))}
+		for n, fm := range i.mapping.Fields {
+			value, err := mapValue(record, fm, targets[n])
+			if err != nil {
+				problems[r] = append(problems[r], autotask.FieldError{Field: targets[n].Name, Message: err.Error()})
+				continue
+			}
+			if value != nil {
+				row.Values[targets[n].Name] = value
+			}
+		}
+		plan.Rows[r] = row
+	}
+
+	for n, fm := range i.mapping.Fields {
+		if fm.Lookup == nil {
+			continue
+		}
+		if err := i.resolveLookup(ctx, plan, problems, targets[n].Name, *fm.Lookup); err != nil {
+			return nil, err
+		}
+	}
+
+	for r := range plan.Rows {
+		row := &plan.Rows[r]
+		if err := autotask.ValidateFields(entity, fields, row.Values, true); err != nil {
+			var verr *autotask.ValidationError
+			if !errors.As(err, &verr) {
+				return nil, err
+			}
+			for _, fe := range verr.Errors {
+				if !hasProblem(problems[r], fe.Field) {
+					problems[r] = append(problems[r], fe)
+				}
+			}
+		}
+		if len(problems[r]) > 0 {
+			row.Err = &autotask.ValidationError{Entity: entity, Errors: problems[r]}
+		}
+	}
+	return plan, nil
+}
+
+// Apply creates the valid rows of plan with autotask.BulkCreate and returns
+// a result for every row, in plan order. Invalid rows are reported in the
+// results and aren't sent. The error is the *autotask.BulkError of the rows
+// the API rejected, if any. In dry-run mode rows get StatusPlanned.
+func (i *Importer) Apply(ctx context.Context, plan *Plan, opts autotask.BulkOptions) ([]Result, error) {
+	results := make([]Result, len(plan.Rows))
+	var entities []interface{}
+	var index []int
+	for r, row := range plan.Rows {
+		results[r] = Result{Line: row.Line, Status: StatusInvalid, Err: row.Err}
+		if row.Err == nil {
+			entities = append(entities, row.Values)
+			index = append(index, r)
+		}
+	}
+	if len(entities) == 0 {
+		return results, nil
+	}
+
+	service := autotask.NewBaseEntityService(i.client, plan.Entity)
+	bulk, err := autotask.BulkCreate(ctx, &service, entities, opts)
+	for _, b := range bulk {
+		result := &results[index[b.Index]]
+		result.ID = b.ID
+		result.Err = b.Err
+		switch {
+		case errors.Is(b.Err, autotask.ErrBulkSkipped):
+			result.Status = StatusSkipped
+		case b.Err != nil:
+			result.Status = StatusFailed
+		case b.ID < 0:
+			result.Status = StatusPlanned
+		default:
+			result.Status = StatusCreated
+		}
+	}
+	return results, err
+}
+
+// WriteResults writes one CSV line per row with its source line, status,
+// new ID and error
+func WriteResults(w io.Writer, results []Result) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"line", "status", "id", "error"}); err != nil {
+		return err
+	}
+	for _, result := range results {
+		id, msg := "", ""
+		if result.ID != 0 {
+			id = strconv.FormatInt(result.ID, 10)
+		}
+		if result.Err != nil {
+			msg = result.Err.Error()
+		}
+		if err := cw.Write([]string{strconv.Itoa(result.Line), string(result.Status), id, msg}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// mapValue computes one field of a record: the column value or default,
+// transformed and converted to the field's data type. Lookup fields are
+// left as strings for resolveLookup. Missing values are nil.
+func mapValue(record Record, fm FieldMapping, field autotask.FieldInfo) (interface{}, error) {
+	var value interface{}
+	if fm.Column != "" {
+		value = columnValue(record.Values, fm.Column)
+	}
+	if isEmpty(value) {
+		value = fm.Default
+	}
+	if isEmpty(value) {
+		return nil, nil
+	}
+
+	for _, t := range fm.Transforms {
+		s, ok := value.(string)
+		if !ok {
+			s = fmt.Sprint(value)
+		}
+		switch t {
+		case TransformTrim:
+			value = strings.TrimSpace(s)
+		case TransformLower:
+			value = strings.ToLower(s)
+		case TransformUpper:
+			value = strings.ToUpper(s)
+		case TransformPicklist:
+			option, ok := field.LookupPicklistValue(strings.TrimSpace(s))
+			if !ok {
+				return nil, fmt.Errorf("%q is not a valid picklist label", s)
+			}
+			value = option.Value
+		}
+	}
+
+	if fm.Lookup != nil {
+		return fmt.Sprint(value), nil
+	}
+	return convert(value, field)
+}
+
+// convert converts a source value to the data type of field
+func convert(value interface{}, field autotask.FieldInfo) (interface{}, error) {
+	s, isString := value.(string)
+	if isString {
+		s = strings.TrimSpace(s)
+	}
+
+	switch strings.ToLower(field.DataType) {
+	case "integer", "long", "short", "byte":
+		if f, ok := value.(float64); ok {
+			if f != float64(int64(f)) {
+				return nil, fmt.Errorf("%v is not a whole number", f)
+			}
+			return int64(f), nil
+		}
+		if isString {
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a whole number", s)
+			}
+			return n, nil
+		}
+	case "double", "decimal", "float":
+		if isString {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a number", s)
+			}
+			return f, nil
+		}
+	case "boolean":
+		if isString {
+			switch strings.ToLower(s) {
+			case "true", "yes", "y", "1":
+				return true, nil
+			case "false", "no", "n", "0":
+				return false, nil
+			}
+			return nil, fmt.Errorf("%q is not a boolean", s)
+		}
+	case "datetime":
+		if isString {
+			dt, err := autotask.ParseDateTime(s)
+			if err != nil {
+				return nil, err
+			}
+			return dt.String(), nil
+		}
+	case "date":
+		if isString {
+			d, err := autotask.ParseDate(s)
+			if err != nil {
+				return nil, err
+			}
+			return d.String(), nil
+		}
+	}
+	return value, nil
+}
+
+// resolveLookup replaces the values of a lookup field with the IDs of the
+// entities they name, querying each distinct value once
+func (i *Importer) resolveLookup(ctx context.Context, plan *Plan, problems [][]autotask.FieldError, field string, lookup Lookup) error {
+	seen := make(map[string]bool)
+	var values []interface{}
+	for _, row := range plan.Rows {
+		if s, ok := row.Values[field].(string); ok && !seen[strings.ToLower(s)] {
+			seen[strings.ToLower(s)] = true
+			values = append(values, s)
+		}
+	}
+	sort.Slice(values, func(a, b int) bool { return values[a].(string) < values[b].(string) })
+
+	items, err := autotask.QueryByField[map[string]json.RawMessage](ctx, i.client, lookup.Entity, lookup.Field, values, lookupBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to look up %s by %s: %w", lookup.Entity, lookup.Field, err)
+	}
+
+	ids := make(map[string][]int64)
+	for _, item := range items {
+		var id int64
+		var name string
+		for key, raw := range item {
+			switch {
+			case key == "id":
+				_ = json.Unmarshal(raw, &id)
+			case strings.EqualFold(key, lookup.Field):
+				var v interface{}
+				_ = json.Unmarshal(raw, &v)
+				name = fmt.Sprint(v)
+			}
+		}
+		key := strings.ToLower(name)
+		ids[key] = append(ids[key], id)
+	}
+
+	for r := range plan.Rows {
+		s, ok := plan.Rows[r].Values[field].(string)
+		if !ok {
+			continue
+		}
+		switch matches := ids[strings.ToLower(s)]; len(matches) {
+		case 1:
+			plan.Rows[r].Values[field] = matches[0]
+		case 0:
+			delete(plan.Rows[r].Values, field)
+			problems[r] = append(problems[r], autotask.FieldError{Field: field, Message: fmt.Sprintf("no %s with %s %q", lookup.Entity, lookup.Field, s)})
+		default:
+			delete(plan.Rows[r].Values, field)
+			problems[r] = append(problems[r], autotask.FieldError{Field: field, Message: fmt.Sprintf("%d %s have %s %q", len(matches), lookup.Entity, lookup.Field, s)})
+		}
+	}
+	return nil
+}
+
+// columnValue returns a record's value for a column, matching its name
+// case-insensitively
+func columnValue(values map[string]interface{}, column string) interface{} {
+	if value, ok := values[column]; ok {
+		return value
+	}
+	for key, value := range values {
+		if strings.EqualFold(key, column) {
+			return value
+		}
+	}
+	return nil
+}
+
+// hasProblem reports whether problems already has an entry for field, so a
+// field that failed mapping isn't also reported as missing
+func hasProblem(problems []autotask.FieldError, field string) bool {
+	for _, p := range problems {
+		if strings.EqualFold(p.Field, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmpty reports whether a source value is missing
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	s, ok := value.(string)
+	return ok && strings.TrimSpace(s) == ""
+}
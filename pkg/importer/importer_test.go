@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+// newImportServer serves contact and company metadata, company lookups and
+// creates
+func newImportServer(t *testing.T) (*autotask.MockServer, *[]map[string]interface{}) {
+	server := autotask.NewMockServer(t)
+	var mu sync.Mutex
+	var created []map[string]interface{}
+
+	server.AddHandler("/Contacts/entityInformation/fields", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"fields": []map[string]interface{}{
+			{"name": "id", "dataType": "long", "isReadOnly": true},
+			{"name": "firstName", "dataType": "string", "isRequired": true, "length": 50},
+			{"name": "lastName", "dataType": "string", "isRequired": true, "length": 50},
+			{"name": "companyID", "dataType": "integer", "isRequired": true, "isReference": true},
+			{"name": "emailAddress", "dataType": "string", "length": 254},
+			{"name": "isActive", "dataType": "boolean"},
+		}})
+	})
+	server.AddHandler("/Companies/entityInformation/fields", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"fields": []map[string]interface{}{
+			{"name": "id", "dataType": "long", "isReadOnly": true},
+			{"name": "companyName", "dataType": "string", "isRequired": true},
+			{"name": "companyType", "dataType": "integer", "isRequired": true, "isPickList": true, "picklistValues": []map[string]interface{}{
+				{"value": "1", "label": "Customer", "isActive": true},
+				{"value": "3", "label": "Prospect", "isActive": true},
+			}},
+		}})
+	})
+	server.AddHandler("/Companies/query", func(w http.ResponseWriter, r *http.Request) {
+		search := r.URL.Query().Get("search")
+		if !strings.Contains(search, `"field":"companyName","op":"in"`) {
+			server.RespondWithError(w, http.StatusBadRequest, "expected a companyName in query", nil)
+			return
+		}
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"items": []map[string]interface{}{
+			{"id": 10, "companyName": "Acme"},
+			{"id": 20, "companyName": "Globex"},
+			{"id": 21, "companyName": "Globex"},
+		}})
+	})
+	server.AddHandler("/Contacts", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var values map[string]interface{}
+		_ = json.Unmarshal(body, &values)
+		if values["lastName"] == "Reject" {
+			server.RespondWithError(w, http.StatusInternalServerError, "rejected", nil)
+			return
+		}
+		mu.Lock()
+		created = append(created, values)
+		id := 100 + len(created)
+		mu.Unlock()
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"itemId": id})
+	})
+	return server, &created
+}
+
+func TestImportCSV(t *testing.T) {
+	server, created := newImportServer(t)
+	defer server.Close()
+
+	mapping, err := LoadMapping(strings.NewReader(`{
+		"entity": "Contacts",
+		"fields": [
+			{"field": "firstName", "column": "First", "transforms": ["trim"]},
+			{"field": "lastName", "column": "Last"},
+			{"field": "companyID", "column": "Company", "lookup": {"entity": "Companies", "field": "companyName"}},
+			{"field": "emailAddress", "column": "Email", "transforms": ["trim", "lower"]},
+			{"field": "isActive", "column": "Active", "default": "yes"}
+		]
+	}`))
+	autotask.AssertNil(t, err, "mapping should load")
+
+	records, err := ReadCSV(strings.NewReader("First,Last,Company,Email,Active\n" +
+		" Ada ,Lovelace,Acme, Ada@Example.com ,\n" +
+		"Bob,,Acme,bob@example.com,no\n" +
+		"Cy,Smith,Globex,cy@example.com,yes\n" +
+		"Di,Reject,acme,di@example.com,maybe\n" +
+		"Ed,Reject,acme,ed@example.com,no\n"))
+	autotask.AssertNil(t, err, "CSV should be read")
+	autotask.AssertEqual(t, 5, len(records), "every data row should be read")
+
+	ctx := context.Background()
+	imp := New(server.NewTestClient(), *mapping)
+	plan, err := imp.Plan(ctx, records)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, 2, len(plan.Valid()), "two rows should be valid")
+
+	report := plan.String()
+	autotask.AssertTrue(t, strings.Contains(report, "line 3: invalid Contacts: lastName: field is required"), "missing required field should be reported")
+	autotask.AssertTrue(t, strings.Contains(report, `line 4: invalid Contacts: companyID: 2 Companies have companyName "Globex"`), "ambiguous lookup should be reported")
+	autotask.AssertTrue(t, strings.Contains(report, `line 5: invalid Contacts: isActive: "maybe" is not a boolean`), "bad boolean should be reported")
+	autotask.AssertEqual(t, 0, len(*created), "planning should not write")
+
+	results, err := imp.Apply(ctx, plan, autotask.BulkOptions{Concurrency: 1})
+	autotask.AssertNotNil(t, err, "rejected rows should be reported")
+	autotask.AssertEqual(t, 1, len(*created), "only valid rows should be sent")
+
+	first := (*created)[0]
+	autotask.AssertEqual(t, "Ada", first["firstName"], "value should be trimmed")
+	autotask.AssertEqual(t, "ada@example.com", first["emailAddress"], "value should be lowercased")
+	autotask.AssertEqual(t, float64(10), first["companyID"], "company name should be resolved to its ID")
+	autotask.AssertEqual(t, true, first["isActive"], "default should be converted to a boolean")
+
+	var buf bytes.Buffer
+	autotask.AssertNil(t, WriteResults(&buf, results), "results should be written")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	autotask.AssertEqual(t, "line,status,id,error", lines[0], "results should have a header")
+	autotask.AssertEqual(t, "2,created,101,", lines[1], "created row should have its ID")
+	autotask.AssertTrue(t, strings.HasPrefix(lines[2], "3,invalid,,"), "invalid row should be reported")
+	autotask.AssertTrue(t, strings.HasPrefix(lines[5], "6,failed,,"), "rejected row should be reported")
+}
+
+func TestImportJSONDryRun(t *testing.T) {
+	server, _ := newImportServer(t)
+	defer server.Close()
+
+	records, err := ReadJSON(strings.NewReader(`{"name": "Initech", "type": "prospect"}
+{"name": "Hooli", "type": "Partner"}
+`))
+	autotask.AssertNil(t, err, "JSONL should be read")
+	autotask.AssertEqual(t, 2, len(records), "every line should be read")
+
+	mapping := Mapping{Entity: "Companies", Fields: []FieldMapping{
+		{Field: "companyName", Column: "name"},
+		{Field: "companyType", Column: "type", Transforms: []string{TransformPicklist}},
+	}}
+	imp := New(server.NewTestClient(), mapping)
+	ctx := autotask.WithDryRun(context.Background(), true)
+
+	plan, err := imp.Plan(ctx, records)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, int64(3), plan.Rows[0].Values["companyType"], "picklist label should become its value")
+	autotask.AssertNotNil(t, plan.Rows[1].Err, "unknown picklist label should be reported")
+
+	results, err := imp.Apply(ctx, plan, autotask.BulkOptions{})
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, StatusPlanned, results[0].Status, "dry-run rows should be planned")
+	autotask.AssertEqual(t, StatusInvalid, results[1].Status, "invalid rows should not be sent")
+
+	_, err = LoadMapping(strings.NewReader(`{"entity": "Companies", "fields": [{"field": "companyName", "transforms": ["shout"]}]}`))
+	autotask.AssertNotNil(t, err, "incomplete mappings should be rejected")
+}
@@ -0,0 +1,105 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Transforms applied to source values, in the order listed in a FieldMapping
+const (
+	TransformTrim     = "trim"
+	TransformLower    = "lower"
+	TransformUpper    = "upper"
+	TransformPicklist = "picklist"
+)
+
+// Mapping declares how source records become entities
+type Mapping struct {
+	// Entity is the entity to create, such as Companies
+	Entity string `json:"entity"`
+
+	Fields []FieldMapping `json:"fields"`
+}
+
+// FieldMapping sets one entity field from a source column or a constant
+type FieldMapping struct {
+	// Field is the entity field to set
+	Field string `json:"field"`
+
+	// Column is the source column. Leave it empty to always use Default.
+	Column string `json:"column,omitempty"`
+
+	// Default is used when the column is missing or empty
+	Default interface{} `json:"default,omitempty"`
+
+	// Transforms are applied in order: trim, lower, upper, and picklist,
+	// which turns a picklist label into its value using the field metadata
+	Transforms []string `json:"transforms,omitempty"`
+
+	// Lookup replaces the value with the ID of the entity it names, such as
+	// a company name with its companyID
+	Lookup *Lookup `json:"lookup,omitempty"`
+}
+
+// Lookup resolves a value to the ID of the entity whose Field equals it,
+// ignoring case
+type Lookup struct {
+	Entity string `json:"entity"`
+	Field  string `json:"field"`
+}
+
+// LoadMapping reads a JSON mapping
+func LoadMapping(r io.Reader) (*Mapping, error) {
+	var m Mapping
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to decode mapping: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Validate checks that the mapping is complete. Field names are checked
+// against the entity metadata when an import is planned.
+func (m *Mapping) Validate() error {
+	if m.Entity == "" {
+		return errors.New("mapping has no entity")
+	}
+	if len(m.Fields) == 0 {
+		return errors.New("mapping has no fields")
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(m.Fields))
+	for i, f := range m.Fields {
+		if f.Field == "" {
+			errs = append(errs, fmt.Errorf("mapping %d has no field", i+1))
+			continue
+		}
+		if seen[strings.ToLower(f.Field)] {
+			errs = append(errs, fmt.Errorf("field %s is mapped more than once", f.Field))
+		}
+		seen[strings.ToLower(f.Field)] = true
+
+		if f.Column == "" && f.Default == nil {
+			errs = append(errs, fmt.Errorf("field %s has no column or default", f.Field))
+		}
+		for _, t := range f.Transforms {
+			switch t {
+			case TransformTrim, TransformLower, TransformUpper, TransformPicklist:
+			default:
+				errs = append(errs, fmt.Errorf("field %s has unknown transform %q", f.Field, t))
+			}
+		}
+		if f.Lookup != nil && (f.Lookup.Entity == "" || f.Lookup.Field == "") {
+			errs = append(errs, fmt.Errorf("field %s lookup needs an entity and a field", f.Field))
+		}
+	}
+	return errors.Join(errs...)
+}
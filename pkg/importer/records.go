@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Record is one source row
+type Record struct {
+	// Line is the line of the record in a CSV or JSONL file, or its
+	// position in a JSON array, counting from 1
+	Line int
+
+	Values map[string]interface{}
+}
+
+// ReadCSV reads records from CSV with a header row. Values are strings.
+func ReadCSV(r io.Reader) ([]Record, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	for i, name := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+	}
+
+	var records []Record
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+
+		values := make(map[string]interface{}, len(header))
+		for i, name := range header {
+			if i < len(row) {
+				values[name] = row[i]
+			}
+		}
+		records = append(records, Record{Line: line, Values: values})
+	}
+}
+
+// ReadJSON reads records from a JSON array of objects or from newline-delimited JSON
+func ReadJSON(r io.Reader) ([]Record, error) {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON: %w", err)
+	}
+
+	if first == '[' {
+		var items []map[string]interface{}
+		if err := json.NewDecoder(br).Decode(&items); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		}
+		records := make([]Record, len(items))
+		for i, item := range items {
+			records[i] = Record{Line: i + 1, Values: item}
+		}
+		return records, nil
+	}
+
+	var records []Record
+	scanner := bufio.NewScanner(br)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var values map[string]interface{}
+		if err := json.Unmarshal(text, &values); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON line %d: %w", line, err)
+		}
+		records = append(records, Record{Line: line, Values: values})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read JSON: %w", err)
+	}
+	return records, nil
+}
+
+// peekNonSpace returns the first byte that isn't white space without consuming it
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return b, br.UnreadByte()
+		}
+	}
+}
@@ -0,0 +1,393 @@
+// Package mirror keeps a local SQLite copy of Autotask entities that can be
+// queried with SQL.
+//
+// Each entity gets a table with one column per field in its metadata, plus a
+// userDefinedFields column holding the entity's UDFs as a JSON object keyed
+// by name. The first Sync of an entity loads it in ID-range partitions,
+// recording a checkpoint after each so an interrupted load resumes where it
+// stopped. Later syncs only fetch the entities modified since the previous
+// one. Deletions in Autotask aren't detected.
+package mirror
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+
+	// Registers the "sqlite" database/sql driver
+	_ "modernc.org/sqlite"
+)
+
+// Default settings for a new Mirror
+const (
+	DefaultPartitionSize = 10000
+	DefaultOverlap       = time.Minute
+)
+
+// checkpointTable holds one Checkpoint per mirrored entity
+const checkpointTable = "_mirror_checkpoints"
+
+// udfColumn and syncedAtColumn are added to every entity table
+const (
+	udfColumn      = "userDefinedFields"
+	syncedAtColumn = "_syncedAt"
+)
+
+// modifiedDateFields are tried in order to find the field incremental syncs filter on
+var modifiedDateFields = []string{"lastActivityDate", "lastModifiedDate", "lastModifiedDateTime", "lastActivityDateTime"}
+
+// Checkpoint records how far an entity has been mirrored
+type Checkpoint struct {
+	Entity string
+
+	// LoadedThroughID is the highest ID covered by the initial load
+	LoadedThroughID int64
+
+	// InitialLoadComplete is set once every partition has been loaded
+	InitialLoadComplete bool
+
+	// Since is the modification time the next incremental sync starts from
+	Since time.Time
+
+	// SyncedAt is when the entity was last synced
+	SyncedAt time.Time
+}
+
+// SyncResult describes one Sync of an entity
+type SyncResult struct {
+	Entity string
+
+	// Initial is set when the sync was part of the initial load
+	Initial bool
+
+	// Partitions is the number of ID ranges queried by an initial load
+	Partitions int
+
+	// Rows is the number of entities written
+	Rows int
+}
+
+// Mirror is a local SQLite copy of Autotask entities
+type Mirror struct {
+	db            *sql.DB
+	logger        *slog.Logger
+	partitionSize int64
+	overlap       time.Duration
+
+	mu     sync.Mutex
+	tables map[string]*table
+}
+
+// Open opens or creates the SQLite database file at path and returns a
+// mirror backed by it
+func Open(path string) (*Mirror, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mirror database: %w", err)
+	}
+	m, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// New returns a mirror backed by db, which must use a SQLite driver
+func New(db *sql.DB) (*Mirror, error) {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS "` + checkpointTable + `" (
+	entity TEXT PRIMARY KEY,
+	loaded_through_id INTEGER NOT NULL DEFAULT 0,
+	initial_load_complete INTEGER NOT NULL DEFAULT 0,
+	since TEXT,
+	synced_at TEXT
+)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mirror checkpoint table: %w", err)
+	}
+	return &Mirror{
+		db:            db,
+		logger:        slog.Default(),
+		partitionSize: DefaultPartitionSize,
+		overlap:       DefaultOverlap,
+		tables:        make(map[string]*table),
+	}, nil
+}
+
+// Close closes the database
+func (m *Mirror) Close() error {
+	return m.db.Close()
+}
+
+// DB returns the database, for queries and schema inspection
+func (m *Mirror) DB() *sql.DB {
+	return m.db
+}
+
+// Query runs a SQL query against the mirrored data
+func (m *Mirror) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return m.db.QueryContext(ctx, query, args...)
+}
+
+// SetPartitionSize sets the number of IDs covered by each query of an initial load
+func (m *Mirror) SetPartitionSize(size int64) {
+	if size <= 0 {
+		size = DefaultPartitionSize
+	}
+	m.partitionSize = size
+}
+
+// SetOverlap sets how far before the previous sync each incremental sync
+// starts, so that changes saved while it was running aren't missed
+func (m *Mirror) SetOverlap(overlap time.Duration) {
+	m.overlap = overlap
+}
+
+// SetLogger sets the logger used to report syncs
+func (m *Mirror) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	m.logger = logger
+}
+
+// Checkpoint returns the sync checkpoint of an entity, and false if it has
+// never been synced
+func (m *Mirror) Checkpoint(ctx context.Context, entityName string) (Checkpoint, bool, error) {
+	cp := Checkpoint{Entity: entityName}
+	var complete int
+	var since, syncedAt sql.NullString
+	err := m.db.QueryRowContext(ctx,
+		`SELECT loaded_through_id, initial_load_complete, since, synced_at FROM "`+checkpointTable+`" WHERE entity = ?`,
+		entityName).Scan(&cp.LoadedThroughID, &complete, &since, &syncedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return cp, false, nil
+	}
+	if err != nil {
+		return cp, false, fmt.Errorf("failed to read %s checkpoint: %w", entityName, err)
+	}
+	cp.InitialLoadComplete = complete != 0
+	cp.Since, _ = time.Parse(time.RFC3339Nano, since.String)
+	cp.SyncedAt, _ = time.Parse(time.RFC3339Nano, syncedAt.String)
+	return cp, true, nil
+}
+
+// saveCheckpoint records cp
+func (m *Mirror) saveCheckpoint(ctx context.Context, cp Checkpoint) error {
+	_, err := m.db.ExecContext(ctx, `INSERT INTO "`+checkpointTable+`" (entity, loaded_through_id, initial_load_complete, since, synced_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(entity) DO UPDATE SET
+	loaded_through_id = excluded.loaded_through_id,
+	initial_load_complete = excluded.initial_load_complete,
+	since = excluded.since,
+	synced_at = excluded.synced_at`,
+		cp.Entity, cp.LoadedThroughID, cp.InitialLoadComplete, formatTime(cp.Since), formatTime(cp.SyncedAt))
+	if err != nil {
+		return fmt.Errorf("failed to save %s checkpoint: %w", cp.Entity, err)
+	}
+	return nil
+}
+
+// Reset drops an entity's table and checkpoint, so the next Sync loads it again
+func (m *Mirror) Reset(ctx context.Context, entityName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.db.ExecContext(ctx, `DROP TABLE IF EXISTS `+quoteIdent(entityName)); err != nil {
+		return fmt.Errorf("failed to drop %s table: %w", entityName, err)
+	}
+	if _, err := m.db.ExecContext(ctx, `DELETE FROM "`+checkpointTable+`" WHERE entity = ?`, entityName); err != nil {
+		return fmt.Errorf("failed to delete %s checkpoint: %w", entityName, err)
+	}
+	delete(m.tables, entityName)
+	return nil
+}
+
+// SyncAll syncs each service in turn. A failed entity doesn't stop the
+// others; the errors are joined.
+func (m *Mirror) SyncAll(ctx context.Context, services ...autotask.EntityService) ([]SyncResult, error) {
+	results := make([]SyncResult, 0, len(services))
+	var errs []error
+	for _, service := range services {
+		result, err := m.Sync(ctx, service)
+		results = append(results, result)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// Sync brings the mirror of service's entity up to date. Until the initial
+// load is complete it loads the next ID ranges; after that it fetches the
+// entities modified since the previous sync. Entities without a
+// modification date field are loaded in full every time.
+func (m *Mirror) Sync(ctx context.Context, service autotask.EntityService) (SyncResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entityName := service.GetEntityName()
+	result := SyncResult{Entity: entityName}
+
+	fields, err := service.GetFieldInfo(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to get %s fields: %w", entityName, err)
+	}
+	t, err := m.ensureTable(ctx, entityName, fields)
+	if err != nil {
+		return result, err
+	}
+
+	cp, _, err := m.Checkpoint(ctx, entityName)
+	if err != nil {
+		return result, err
+	}
+
+	dateField := ""
+	for _, name := range modifiedDateFields {
+		if field, ok := autotask.FindField(fields, name); ok {
+			dateField = field.Name
+			break
+		}
+	}
+	if cp.InitialLoadComplete && dateField == "" {
+		cp.LoadedThroughID = 0
+		cp.InitialLoadComplete = false
+	}
+
+	if cp.InitialLoadComplete {
+		err = m.syncChanges(ctx, service, t, dateField, &cp, &result)
+	} else {
+		result.Initial = true
+		err = m.loadPartitions(ctx, service, t, &cp, &result)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	m.logger.InfoContext(ctx, "Mirror sync complete",
+		"entity", entityName, "initial", result.Initial, "partitions", result.Partitions, "rows", result.Rows)
+	return result, nil
+}
+
+// loadPartitions loads the entities in ID ranges of the partition size,
+// starting after the checkpoint's LoadedThroughID. When a range is empty the
+// next ID is looked up so that gaps are skipped in one step.
+func (m *Mirror) loadPartitions(ctx context.Context, service autotask.EntityService, t *table, cp *Checkpoint, result *SyncResult) error {
+	if cp.LoadedThroughID == 0 {
+		// Changes made while loading are picked up by the first incremental sync
+		cp.Since = time.Now().UTC()
+	}
+
+	lo := cp.LoadedThroughID
+	for {
+		hi := lo + m.partitionSize
+		n, _, err := m.load(ctx, service, t, fmt.Sprintf("id>%d AND id<%d", lo, hi+1), "")
+		if err != nil {
+			return err
+		}
+		result.Partitions++
+		result.Rows += n
+
+		cp.LoadedThroughID = hi
+		cp.SyncedAt = time.Now().UTC()
+		if err := m.saveCheckpoint(ctx, *cp); err != nil {
+			return err
+		}
+
+		if n > 0 {
+			lo = hi
+			continue
+		}
+		next, ok, err := nextID(ctx, service, hi)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		lo = next - 1
+	}
+
+	cp.InitialLoadComplete = true
+	return m.saveCheckpoint(ctx, *cp)
+}
+
+// syncChanges fetches the entities modified since the checkpoint
+func (m *Mirror) syncChanges(ctx context.Context, service autotask.EntityService, t *table, dateField string, cp *Checkpoint, result *SyncResult) error {
+	filter := fmt.Sprintf("%s>%s", dateField, autotask.NewDateTime(cp.Since.Add(-m.overlap)))
+	n, latest, err := m.load(ctx, service, t, filter, dateField)
+	if err != nil {
+		return err
+	}
+	result.Rows = n
+
+	if latest.After(cp.Since) {
+		cp.Since = latest
+	}
+	cp.SyncedAt = time.Now().UTC()
+	return m.saveCheckpoint(ctx, *cp)
+}
+
+// load queries the entities matching filter and writes them to t one page
+// at a time. It returns the number written and, when dateField is set, the
+// latest value of that field.
+func (m *Mirror) load(ctx context.Context, service autotask.EntityService, t *table, filter, dateField string) (int, time.Time, error) {
+	var latest time.Time
+	count := 0
+	err := autotask.FetchAllPagesWithCallback(ctx, service, filter,
+		func(items []map[string]json.RawMessage, _ autotask.PageDetails) error {
+			if err := m.upsert(ctx, t, items); err != nil {
+				return err
+			}
+			count += len(items)
+			if dateField == "" {
+				return nil
+			}
+			for _, item := range items {
+				if value, ok := autotask.LookupField(item, dateField); ok {
+					var dt autotask.DateTime
+					if json.Unmarshal(value, &dt) == nil && dt.After(latest) {
+						latest = dt.Time
+					}
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return count, latest, fmt.Errorf("failed to load %s: %w", t.name, err)
+	}
+	return count, latest, nil
+}
+
+// nextID returns the lowest ID above after, and false if there is none
+func nextID(ctx context.Context, service autotask.EntityService, after int64) (int64, bool, error) {
+	it, err := autotask.NewPaginationIterator(ctx, service, fmt.Sprintf("id>%d", after), 1)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to find the next %s: %w", service.GetEntityName(), err)
+	}
+	if !it.Next() {
+		return 0, false, nil
+	}
+	item, _ := it.Item().(map[string]interface{})
+	id, _ := item["id"].(float64)
+	if int64(id) <= after {
+		return 0, false, fmt.Errorf("failed to find the next %s: no id above %d in response", service.GetEntityName(), after)
+	}
+	return int64(id), true, nil
+}
+
+// formatTime formats t for the checkpoint table, or "" for the zero time
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
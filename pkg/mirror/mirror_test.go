@@ -0,0 +1,221 @@
+package mirror
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+// fakeTickets serves ticket metadata and queries from memory, applying the
+// id and date filters the mirror sends
+type fakeTickets struct {
+	mu      sync.Mutex
+	tickets map[int64]map[string]interface{}
+	queries []string
+}
+
+// put adds or replaces a ticket
+func (f *fakeTickets) put(id int64, title string, modified time.Time, udfs ...map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tickets[id] = map[string]interface{}{
+		"id":                id,
+		"title":             title,
+		"status":            1,
+		"isBillable":        true,
+		"estimatedHours":    1.5,
+		"lastActivityDate":  modified.Format(time.RFC3339),
+		"userDefinedFields": udfs,
+	}
+}
+
+func newTicketServer(t *testing.T, fake *fakeTickets) *autotask.MockServer {
+	server := autotask.NewMockServer(t)
+	server.AddHandler("/Tickets/entityInformation/fields", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"fields": []map[string]interface{}{
+			{"name": "id", "dataType": "long"},
+			{"name": "title", "dataType": "string"},
+			{"name": "status", "dataType": "integer"},
+			{"name": "isBillable", "dataType": "boolean"},
+			{"name": "estimatedHours", "dataType": "decimal"},
+			{"name": "lastActivityDate", "dataType": "datetime"},
+		}})
+	})
+	server.AddHandler("/Tickets/query", func(w http.ResponseWriter, r *http.Request) {
+		search := r.URL.Query().Get("search")
+		var params struct {
+			Filter     []map[string]interface{} `json:"filter"`
+			MaxRecords int                      `json:"maxRecords"`
+		}
+		if err := json.Unmarshal([]byte(search), &params); err != nil {
+			server.RespondWithError(w, http.StatusBadRequest, "invalid search", nil)
+			return
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		if offset == 0 {
+			fake.queries = append(fake.queries, search)
+		}
+
+		var matched []map[string]interface{}
+		for _, ticket := range fake.tickets {
+			ok := true
+			for _, filter := range params.Filter {
+				ok = ok && matches(ticket, filter)
+			}
+			if ok {
+				matched = append(matched, ticket)
+			}
+		}
+		sort.Slice(matched, func(i, j int) bool { return matched[i]["id"].(int64) < matched[j]["id"].(int64) })
+
+		pageDetails := map[string]interface{}{}
+		end := offset + params.MaxRecords
+		if end < len(matched) {
+			pageDetails["nextPageUrl"] = fmt.Sprintf("/Tickets/query?search=%s&offset=%d", url.QueryEscape(search), end)
+		} else {
+			end = len(matched)
+		}
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"items": matched[offset:end], "pageDetails": pageDetails})
+	})
+	return server
+}
+
+// matches applies a query filter to a ticket
+func matches(ticket map[string]interface{}, filter map[string]interface{}) bool {
+	if items, ok := filter["items"].([]interface{}); ok {
+		and := filter["op"] == "and"
+		for _, item := range items {
+			if matches(ticket, item.(map[string]interface{})) != and {
+				return !and
+			}
+		}
+		return and
+	}
+
+	field := filter["field"].(string)
+	value := fmt.Sprint(filter["value"])
+	var cmp int
+	if field == "id" {
+		want, _ := strconv.ParseInt(value, 10, 64)
+		cmp = int(ticket["id"].(int64) - want)
+	} else {
+		got, _ := autotask.ParseDateTime(ticket[field].(string))
+		want, _ := autotask.ParseDateTime(value)
+		cmp = got.Compare(want.Time)
+	}
+	switch filter["op"] {
+	case "greaterThan":
+		return cmp > 0
+	case "lessThan":
+		return cmp < 0
+	}
+	return cmp == 0
+}
+
+func TestMirrorSync(t *testing.T) {
+	fake := &fakeTickets{tickets: make(map[int64]map[string]interface{})}
+	server := newTicketServer(t, fake)
+	defer server.Close()
+
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	fake.put(1, "Printer", base, map[string]interface{}{"name": "Region", "value": "North"})
+	fake.put(2, "Email", base)
+	fake.put(3, "VPN", base)
+	fake.put(250, "Laptop", base)
+
+	m, err := Open(filepath.Join(t.TempDir(), "mirror.db"))
+	autotask.AssertNil(t, err, "error should be nil")
+	defer m.Close()
+	m.SetPartitionSize(10)
+
+	ctx := context.Background()
+	tickets := server.NewTestClient().Tickets()
+
+	result, err := m.Sync(ctx, tickets)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertTrue(t, result.Initial, "first sync should be the initial load")
+	autotask.AssertEqual(t, 4, result.Rows, "every ticket should be loaded")
+	autotask.AssertEqual(t, 4, result.Partitions, "the gap between ids should be skipped")
+
+	cp, ok, err := m.Checkpoint(ctx, "Tickets")
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertTrue(t, ok, "checkpoint should be recorded")
+	autotask.AssertTrue(t, cp.InitialLoadComplete, "initial load should be complete")
+	autotask.AssertEqual(t, int64(269), cp.LoadedThroughID, "checkpoint should cover the last partition")
+
+	var title, region string
+	var billable int
+	var hours float64
+	err = m.DB().QueryRowContext(ctx,
+		`SELECT title, isBillable, estimatedHours, json_extract(userDefinedFields, '$.Region') FROM Tickets WHERE id = 1`).
+		Scan(&title, &billable, &hours, &region)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, "Printer", title, "string column should be stored")
+	autotask.AssertEqual(t, 1, billable, "boolean should be stored as an integer")
+	autotask.AssertEqual(t, 1.5, hours, "decimal should be stored")
+	autotask.AssertEqual(t, "North", region, "UDFs should be queryable as JSON")
+
+	// Only changes are fetched once the initial load is complete
+	fake.put(2, "Email bounce", time.Now().UTC().Add(time.Minute))
+	fake.put(300, "Monitor", time.Now().UTC().Add(time.Minute))
+
+	result, err = m.Sync(ctx, tickets)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertTrue(t, !result.Initial, "second sync should be incremental")
+	autotask.AssertEqual(t, 2, result.Rows, "only changed tickets should be fetched")
+	autotask.AssertTrue(t, strings.Contains(fake.queries[len(fake.queries)-1], `"field":"lastActivityDate"`), "changes should be queried by modification date")
+
+	rows, err := m.Query(ctx, "SELECT id, title FROM Tickets ORDER BY id")
+	autotask.AssertNil(t, err, "error should be nil")
+	defer rows.Close()
+	titles := map[int64]string{}
+	for rows.Next() {
+		var id int64
+		var title string
+		autotask.AssertNil(t, rows.Scan(&id, &title), "scan should succeed")
+		titles[id] = title
+	}
+	autotask.AssertEqual(t, 5, len(titles), "new ticket should be added")
+	autotask.AssertEqual(t, "Email bounce", titles[2], "changed ticket should be updated")
+}
+
+func TestMirrorResumesInitialLoad(t *testing.T) {
+	fake := &fakeTickets{tickets: make(map[int64]map[string]interface{})}
+	server := newTicketServer(t, fake)
+	defer server.Close()
+
+	for id := int64(1); id <= 25; id++ {
+		fake.put(id, fmt.Sprintf("Ticket %d", id), time.Now().UTC())
+	}
+
+	m, err := Open(filepath.Join(t.TempDir(), "mirror.db"))
+	autotask.AssertNil(t, err, "error should be nil")
+	defer m.Close()
+	m.SetPartitionSize(10)
+
+	// A previous load stopped after the first partition
+	ctx := context.Background()
+	autotask.AssertNil(t, m.saveCheckpoint(ctx, Checkpoint{Entity: "Tickets", LoadedThroughID: 10, Since: time.Now().UTC()}), "checkpoint should be saved")
+
+	result, err := m.Sync(ctx, server.NewTestClient().Tickets())
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, 15, result.Rows, "load should resume after the checkpoint")
+
+	var count int
+	autotask.AssertNil(t, m.DB().QueryRowContext(ctx, "SELECT COUNT(*) FROM Tickets").Scan(&count), "count should succeed")
+	autotask.AssertEqual(t, 15, count, "only the remaining partitions should be loaded")
+}
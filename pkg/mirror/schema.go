@@ -0,0 +1,262 @@
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+// column is a column of an entity table
+type column struct {
+	name     string
+	sqlType  string
+	dataType string
+}
+
+// table is the SQLite table mirroring an entity
+type table struct {
+	name    string
+	columns []column
+	upsert  string
+}
+
+// sqlType returns the SQLite column type for an Autotask data type. Dates
+// are stored as text in DateTimeLayout, which sorts chronologically.
+func sqlType(dataType string) string {
+	switch strings.ToLower(dataType) {
+	case "integer", "long", "short", "byte", "boolean":
+		return "INTEGER"
+	case "double", "decimal", "float":
+		return "REAL"
+	default:
+		return "TEXT"
+	}
+}
+
+// ensureTable creates the table for an entity from its field metadata, or
+// adds the columns of fields that are new since it was created
+func (m *Mirror) ensureTable(ctx context.Context, entityName string, fields []autotask.FieldInfo) (*table, error) {
+	t := &table{name: entityName, columns: []column{{name: "id", sqlType: "INTEGER", dataType: "long"}}}
+	for _, field := range fields {
+		if strings.EqualFold(field.Name, "id") || strings.EqualFold(field.Name, udfColumn) {
+			continue
+		}
+		t.columns = append(t.columns, column{name: field.Name, sqlType: sqlType(field.DataType), dataType: field.DataType})
+	}
+	t.columns = append(t.columns,
+		column{name: udfColumn, sqlType: "TEXT"},
+		column{name: syncedAtColumn, sqlType: "TEXT"})
+
+	if cached, ok := m.tables[entityName]; ok && sameColumns(cached, t) {
+		return cached, nil
+	}
+
+	defs := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		defs[i] = quoteIdent(c.name) + " " + c.sqlType
+		if i == 0 {
+			defs[i] += " PRIMARY KEY"
+		}
+	}
+	if _, err := m.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+quoteIdent(entityName)+" ("+strings.Join(defs, ", ")+")"); err != nil {
+		return nil, fmt.Errorf("failed to create %s table: %w", entityName, err)
+	}
+
+	existing, err := m.tableColumns(ctx, entityName)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range t.columns {
+		if existing[strings.ToLower(c.name)] {
+			continue
+		}
+		if _, err := m.db.ExecContext(ctx, "ALTER TABLE "+quoteIdent(entityName)+" ADD COLUMN "+quoteIdent(c.name)+" "+c.sqlType); err != nil {
+			return nil, fmt.Errorf("failed to add %s column %s: %w", entityName, c.name, err)
+		}
+	}
+
+	names := make([]string, len(t.columns))
+	placeholders := make([]string, len(t.columns))
+	updates := make([]string, 0, len(t.columns)-1)
+	for i, c := range t.columns {
+		names[i] = quoteIdent(c.name)
+		placeholders[i] = "?"
+		if i > 0 {
+			updates = append(updates, names[i]+" = excluded."+names[i])
+		}
+	}
+	t.upsert = "INSERT INTO " + quoteIdent(entityName) + " (" + strings.Join(names, ", ") + ") VALUES (" +
+		strings.Join(placeholders, ", ") + ") ON CONFLICT(\"id\") DO UPDATE SET " + strings.Join(updates, ", ")
+
+	m.tables[entityName] = t
+	return t, nil
+}
+
+// tableColumns returns the lowercased column names of a table
+func (m *Mirror) tableColumns(ctx context.Context, name string) (map[string]bool, error) {
+	rows, err := m.db.QueryContext(ctx, "SELECT name FROM pragma_table_info(?)", name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s columns: %w", name, err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("failed to read %s columns: %w", name, err)
+		}
+		columns[strings.ToLower(column)] = true
+	}
+	return columns, rows.Err()
+}
+
+// sameColumns reports whether two tables have the same columns
+func sameColumns(a, b *table) bool {
+	if len(a.columns) != len(b.columns) {
+		return false
+	}
+	for i := range a.columns {
+		if a.columns[i] != b.columns[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// upsert writes items to t in one transaction
+func (m *Mirror) upsert(ctx context.Context, t *table, items []map[string]json.RawMessage) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin %s write: %w", t.name, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, t.upsert)
+	if err != nil {
+		return fmt.Errorf("failed to prepare %s write: %w", t.name, err)
+	}
+	defer stmt.Close()
+
+	syncedAt := time.Now().UTC().Format(time.RFC3339Nano)
+	for _, item := range items {
+		args := make([]interface{}, len(t.columns))
+		for i, c := range t.columns {
+			switch c.name {
+			case udfColumn:
+				args[i], err = udfValue(item)
+			case syncedAtColumn:
+				args[i] = syncedAt
+			default:
+				value, _ := autotask.LookupField(item, c.name)
+				args[i], err = columnValue(value, c)
+			}
+			if err != nil {
+				return fmt.Errorf("invalid %s field %s: %w", t.name, c.name, err)
+			}
+		}
+		if args[0] == nil {
+			return fmt.Errorf("%s item has no id", t.name)
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return fmt.Errorf("failed to write %s %v: %w", t.name, args[0], err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit %s write: %w", t.name, err)
+	}
+	return nil
+}
+
+// columnValue converts a JSON field value to the value stored in c
+func columnValue(raw json.RawMessage, c column) (interface{}, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case json.Number:
+		if c.sqlType == "INTEGER" {
+			if n, err := v.Int64(); err == nil {
+				return n, nil
+			}
+		}
+		return v.Float64()
+	case string:
+		switch strings.ToLower(c.dataType) {
+		case "datetime":
+			if dt, err := autotask.ParseDateTime(v); err == nil {
+				return dt.String(), nil
+			}
+		case "date":
+			if d, err := autotask.ParseDate(v); err == nil {
+				return d.String(), nil
+			}
+		}
+		return v, nil
+	default:
+		// Objects and arrays are kept as JSON
+		return string(raw), nil
+	}
+}
+
+// udfValue converts an item's userDefinedFields list to a JSON object of
+// values keyed by name, or nil if it has none
+func udfValue(item map[string]json.RawMessage) (interface{}, error) {
+	raw, ok := autotask.LookupField(item, udfColumn)
+	if !ok || len(bytes.TrimSpace(raw)) == 0 || string(bytes.TrimSpace(raw)) == "null" {
+		return nil, nil
+	}
+
+	var udfs []struct {
+		Name  string          `json:"name"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &udfs); err != nil {
+		return nil, err
+	}
+	if len(udfs) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string]json.RawMessage, len(udfs))
+	for _, udf := range udfs {
+		if len(udf.Value) == 0 {
+			udf.Value = json.RawMessage("null")
+		}
+		values[udf.Name] = udf.Value
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// quoteIdent quotes a SQL identifier
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
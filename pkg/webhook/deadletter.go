@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DeadLetter is an item that could not be processed after all retries
+type DeadLetter struct {
+	Item     Item      `json:"item"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// DeadLetterStore keeps items that failed processing so they can be
+// inspected and redriven
+type DeadLetterStore interface {
+	// Put stores a failed item
+	Put(ctx context.Context, letter DeadLetter) error
+
+	// List returns the stored items, oldest first
+	List(ctx context.Context) ([]DeadLetter, error)
+
+	// Remove deletes a stored item by its item ID
+	Remove(ctx context.Context, id string) error
+}
+
+// MemoryDeadLetters is an in-memory DeadLetterStore
+type MemoryDeadLetters struct {
+	mu      sync.Mutex
+	letters []DeadLetter
+}
+
+// NewMemoryDeadLetters returns an empty in-memory dead-letter store
+func NewMemoryDeadLetters() *MemoryDeadLetters {
+	return &MemoryDeadLetters{}
+}
+
+// Put stores a failed item
+func (s *MemoryDeadLetters) Put(ctx context.Context, letter DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.letters = append(s.letters, letter)
+	return nil
+}
+
+// List returns the stored items, oldest first
+func (s *MemoryDeadLetters) List(ctx context.Context) ([]DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DeadLetter(nil), s.letters...), nil
+}
+
+// Remove deletes a stored item by its item ID
+func (s *MemoryDeadLetters) Remove(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, letter := range s.letters {
+		if letter.Item.ID == id {
+			s.letters = append(s.letters[:i], s.letters[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// FileDeadLetters is a DeadLetterStore that keeps each item as a JSON file in a directory
+type FileDeadLetters struct {
+	dir string
+}
+
+// NewFileDeadLetters opens a file-backed dead-letter store in dir, creating it if needed
+func NewFileDeadLetters(dir string) (*FileDeadLetters, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create dead-letter directory: %w", err)
+	}
+	return &FileDeadLetters{dir: dir}, nil
+}
+
+// Put stores a failed item
+func (s *FileDeadLetters) Put(ctx context.Context, letter DeadLetter) error {
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+	name := fmt.Sprintf("%020d-%s.json", letter.FailedAt.UnixNano(), sanitizeFileName(letter.Item.ID))
+	return writeFileAtomic(filepath.Join(s.dir, name), data)
+}
+
+// List returns the stored items, oldest first
+func (s *FileDeadLetters) List(ctx context.Context) ([]DeadLetter, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dead-letter directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	letters := make([]DeadLetter, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dead letter: %w", err)
+		}
+		var letter DeadLetter
+		if err := json.Unmarshal(data, &letter); err != nil {
+			return nil, fmt.Errorf("failed to parse dead letter %s: %w", name, err)
+		}
+		letters = append(letters, letter)
+	}
+	return letters, nil
+}
+
+// Remove deletes a stored item by its item ID
+func (s *FileDeadLetters) Remove(ctx context.Context, id string) error {
+	matches, err := filepath.Glob(filepath.Join(s.dir, "*-"+sanitizeFileName(id)+".json"))
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		if err := os.Remove(match); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove dead letter: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+package webhook
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how often a failed event is retried before it is
+// moved to the dead-letter store
+type RetryPolicy struct {
+	MaxAttempts     int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+}
+
+// DefaultRetryPolicy returns the retry policy used by a new Dispatcher
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     5,
+		InitialInterval: time.Second,
+		MaxInterval:     time.Minute,
+		Multiplier:      2,
+	}
+}
+
+// backoff returns the wait before the given retry, with up to 10% jitter
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	interval := float64(p.InitialInterval)
+	for i := 1; i < attempt; i++ {
+		interval *= p.Multiplier
+		if p.MaxInterval > 0 && interval > float64(p.MaxInterval) {
+			interval = float64(p.MaxInterval)
+			break
+		}
+	}
+	if interval <= 0 {
+		return 0
+	}
+	return time.Duration(interval + rand.Float64()*interval*0.1)
+}
+
+// Dispatcher processes webhooks asynchronously. Its ServeHTTP verifies a
+// callback, queues it and responds 200 straight away so a slow handler never
+// makes Autotask time out. Workers started with Start take events off the
+// queue and run the receiver's handlers, retrying failures with backoff and
+// moving events that still fail to the dead-letter store.
+type Dispatcher struct {
+	receiver    *Receiver
+	queue       Queue
+	deadLetters DeadLetterStore
+	retry       RetryPolicy
+	workers     int
+	logger      *slog.Logger
+
+	wg sync.WaitGroup
+}
+
+// NewDispatcher returns a dispatcher that queues callbacks verified by
+// receiver and processes them with its handlers. A nil queue uses a
+// MemoryQueue.
+func NewDispatcher(receiver *Receiver, queue Queue) *Dispatcher {
+	if queue == nil {
+		queue = NewMemoryQueue(0)
+	}
+	return &Dispatcher{
+		receiver:    receiver,
+		queue:       queue,
+		deadLetters: NewMemoryDeadLetters(),
+		retry:       DefaultRetryPolicy(),
+		workers:     4,
+		logger:      receiver.logger,
+	}
+}
+
+// SetWorkers sets the number of workers started by Start
+func (d *Dispatcher) SetWorkers(n int) {
+	if n < 1 {
+		n = 1
+	}
+	d.workers = n
+}
+
+// SetRetryPolicy sets how failed events are retried
+func (d *Dispatcher) SetRetryPolicy(policy RetryPolicy) {
+	d.retry = policy
+}
+
+// SetDeadLetterStore sets where events that exhaust their retries are kept.
+// A nil store discards them after logging.
+func (d *Dispatcher) SetDeadLetterStore(store DeadLetterStore) {
+	d.deadLetters = store
+}
+
+// SetLogger sets the logger used to report failures
+func (d *Dispatcher) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	d.logger = logger
+}
+
+// DeadLetters returns the dead-letter store
+func (d *Dispatcher) DeadLetters() DeadLetterStore {
+	return d.deadLetters
+}
+
+// ServeHTTP verifies and queues a webhook callback. It responds 503 when the
+// queue can't accept the event, so that Autotask delivers it again.
+func (d *Dispatcher) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	event, err := d.receiver.readEvent(w, req)
+	if err != nil {
+		return
+	}
+
+	id := event.GUID
+	if id == "" {
+		id = newItemID()
+	}
+	item := Item{ID: id, Body: event.Raw, ReceivedAt: time.Now().UTC()}
+
+	if err := d.queue.Push(req.Context(), item); err != nil {
+		d.logger.ErrorContext(req.Context(), "Failed to queue webhook",
+			"entity_type", event.EntityType,
+			"action", event.Action,
+			"entity_id", event.EntityID,
+			"guid", event.GUID,
+			"error", err,
+		)
+		http.Error(w, "Webhook queue unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Start starts the workers. They run until ctx is done; use Wait to block
+// until they have finished.
+func (d *Dispatcher) Start(ctx context.Context) {
+	for i := 0; i < d.workers; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.work(ctx)
+		}()
+	}
+}
+
+// Wait blocks until all workers have stopped
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// Redrive moves every dead letter back onto the queue
+func (d *Dispatcher) Redrive(ctx context.Context) (int, error) {
+	if d.deadLetters == nil {
+		return 0, nil
+	}
+	letters, err := d.deadLetters.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i, letter := range letters {
+		if err := d.queue.Push(ctx, letter.Item); err != nil {
+			return i, err
+		}
+		if err := d.deadLetters.Remove(ctx, letter.Item.ID); err != nil {
+			return i + 1, err
+		}
+	}
+	return len(letters), nil
+}
+
+// work processes queued items until ctx is done
+func (d *Dispatcher) work(ctx context.Context) {
+	for {
+		item, err := d.queue.Pop(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			d.logger.ErrorContext(ctx, "Failed to read webhook queue", "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		if err := d.process(ctx, item); err != nil {
+			// Leave the item in the queue and hand it out again after a pause
+			if ctx.Err() != nil {
+				return
+			}
+			d.logger.ErrorContext(ctx, "Failed to settle webhook", "id", item.ID, "error", err)
+			if releaser, ok := d.queue.(Releaser); ok {
+				if err := releaser.Release(ctx, item.ID); err != nil {
+					d.logger.ErrorContext(ctx, "Failed to release webhook", "id", item.ID, "error", err)
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		if err := d.queue.Ack(ctx, item.ID); err != nil {
+			d.logger.ErrorContext(ctx, "Failed to acknowledge webhook", "id", item.ID, "error", err)
+		}
+	}
+}
+
+// process runs the handlers for an item, retrying failures, and moves it to
+// the dead-letter store when it still fails. A non-nil error means the item
+// was neither processed nor stored and must stay in the queue.
+func (d *Dispatcher) process(ctx context.Context, item Item) error {
+	event, err := ParseEvent(item.Body)
+	if err != nil {
+		return d.deadLetter(ctx, item, 1, err)
+	}
+
+	maxAttempts := d.retry.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = d.receiver.Dispatch(ctx, event)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		d.logger.WarnContext(ctx, "Webhook handler error",
+			"entity_type", event.EntityType,
+			"action", event.Action,
+			"entity_id", event.EntityID,
+			"guid", event.GUID,
+			"attempt", attempt,
+			"error", err,
+		)
+		if attempt >= maxAttempts {
+			return d.deadLetter(ctx, item, attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d.retry.backoff(attempt)):
+		}
+	}
+}
+
+// deadLetter stores an item that could not be processed
+func (d *Dispatcher) deadLetter(ctx context.Context, item Item, attempts int, cause error) error {
+	if d.deadLetters == nil {
+		d.logger.ErrorContext(ctx, "Discarding failed webhook", "id", item.ID, "attempts", attempts, "error", cause)
+		return nil
+	}
+	return d.deadLetters.Put(ctx, DeadLetter{
+		Item:     item,
+		Error:    cause.Error(),
+		Attempts: attempts,
+		FailedAt: time.Now().UTC(),
+	})
+}
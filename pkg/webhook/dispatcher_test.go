@@ -0,0 +1,195 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+// fastRetry retries quickly so tests don't wait on backoff
+var fastRetry = RetryPolicy{MaxAttempts: 3, InitialInterval: time.Millisecond, MaxInterval: 5 * time.Millisecond, Multiplier: 2}
+
+// waitFor polls cond until it is true or the test times out
+func waitFor(t *testing.T, cond func() bool, message string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal(message)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// post sends a signed webhook to handler and returns the status code
+func post(handler http.Handler, secret, body string) int {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set(HookSignatureHeader, SignatureHeader(secret, []byte(body)))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	receiver := NewReceiver("secret")
+
+	var calls int32
+	receiver.Handle(EntityTicket, ActionUpdate, func(ctx context.Context, event *Event) error {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			return errors.New("temporarily unavailable")
+		}
+		return nil
+	})
+
+	dispatcher := NewDispatcher(receiver, nil)
+	dispatcher.SetRetryPolicy(fastRetry)
+
+	// The request is accepted before any worker runs
+	autotask.AssertEqual(t, http.StatusOK, post(dispatcher, "secret", samplePayload), "webhook should be accepted")
+	autotask.AssertEqual(t, int32(0), atomic.LoadInt32(&calls), "handler should not run inside the request")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatcher.Start(ctx)
+
+	waitFor(t, func() bool { return atomic.LoadInt32(&calls) == 3 }, "handler should be retried until it succeeds")
+	cancel()
+	dispatcher.Wait()
+
+	letters, _ := dispatcher.DeadLetters().List(context.Background())
+	autotask.AssertEqual(t, 0, len(letters), "successful event should not be dead-lettered")
+}
+
+func TestDispatcherDeadLetters(t *testing.T) {
+	receiver := NewReceiver("secret")
+
+	var calls int32
+	receiver.Handle(EntityTicket, ActionUpdate, func(ctx context.Context, event *Event) error {
+		atomic.AddInt32(&calls, 1)
+		return errors.New("rejected")
+	})
+
+	dispatcher := NewDispatcher(receiver, NewMemoryQueue(10))
+	dispatcher.SetRetryPolicy(fastRetry)
+	dispatcher.SetWorkers(2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dispatcher.Start(ctx)
+
+	autotask.AssertEqual(t, http.StatusOK, post(dispatcher, "secret", samplePayload), "webhook should be accepted")
+	autotask.AssertEqual(t, http.StatusUnauthorized, post(dispatcher, "wrong", samplePayload), "unsigned webhook should be rejected")
+
+	var letters []DeadLetter
+	waitFor(t, func() bool {
+		letters, _ = dispatcher.DeadLetters().List(ctx)
+		return len(letters) == 1
+	}, "failed event should be dead-lettered")
+
+	autotask.AssertEqual(t, int32(3), atomic.LoadInt32(&calls), "handler should be attempted MaxAttempts times")
+	autotask.AssertEqual(t, 3, letters[0].Attempts, "attempts should be recorded")
+	autotask.AssertEqual(t, "rejected", letters[0].Error, "error should be recorded")
+	autotask.AssertEqual(t, "7c1b4a9e-2f7d-4d1e-9a0b-6b3c2d1e0f9a", letters[0].Item.ID, "item should be keyed by GUID")
+
+	n, err := dispatcher.Redrive(ctx)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, 1, n, "dead letter should be redriven")
+	waitFor(t, func() bool { return atomic.LoadInt32(&calls) == 6 }, "redriven event should be processed again")
+}
+
+func TestDispatcherQueueFull(t *testing.T) {
+	dispatcher := NewDispatcher(NewReceiver("secret"), NewMemoryQueue(1))
+
+	autotask.AssertEqual(t, http.StatusOK, post(dispatcher, "secret", samplePayload), "first webhook should be queued")
+	autotask.AssertEqual(t, http.StatusServiceUnavailable, post(dispatcher, "secret", samplePayload), "full queue should ask Autotask to retry")
+}
+
+func TestFileQueue(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	queue, err := NewFileQueue(dir)
+	autotask.AssertNil(t, err, "error should be nil")
+
+	now := time.Now()
+	autotask.AssertNil(t, queue.Push(ctx, Item{ID: "b", Body: []byte(`{"n":2}`), ReceivedAt: now.Add(time.Second)}), "push should succeed")
+	autotask.AssertNil(t, queue.Push(ctx, Item{ID: "a", Body: []byte(`{"n":1}`), ReceivedAt: now}), "push should succeed")
+
+	item, err := queue.Pop(ctx)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, "a", item.ID, "oldest item should be popped first")
+
+	// A new queue on the same directory redelivers the unacknowledged item
+	reopened, err := NewFileQueue(dir)
+	autotask.AssertNil(t, err, "error should be nil")
+	item, err = reopened.Pop(ctx)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, "a", item.ID, "in-flight item should survive a restart")
+	autotask.AssertNil(t, reopened.Ack(ctx, "a"), "ack should succeed")
+
+	item, err = reopened.Pop(ctx)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, "b", item.ID, "next item should be popped")
+	autotask.AssertEqual(t, `{"n":2}`, string(item.Body), "body should be preserved")
+
+	n, err := reopened.Len()
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, 1, n, "acknowledged item should be removed")
+
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = reopened.Pop(waitCtx)
+	autotask.AssertTrue(t, errors.Is(err, context.DeadlineExceeded), "pop should wait for new items")
+}
+
+func TestFileQueueBadItems(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	queue, err := NewFileQueue(dir)
+	autotask.AssertNil(t, err, "error should be nil")
+
+	now := time.Now()
+	corrupt := fmt.Sprintf("%020d-corrupt.json", now.UnixNano())
+	autotask.AssertNil(t, os.WriteFile(filepath.Join(dir, corrupt), []byte("{not json"), 0o600), "write should succeed")
+	autotask.AssertNil(t, queue.Push(ctx, Item{ID: "good", Body: []byte(`{"n":1}`), ReceivedAt: now.Add(time.Second)}), "push should succeed")
+
+	item, err := queue.Pop(ctx)
+	autotask.AssertNil(t, err, "corrupt item should not fail pop")
+	autotask.AssertEqual(t, "good", item.ID, "valid item after a corrupt one should be delivered")
+
+	_, err = os.Stat(filepath.Join(dir, BadItemsDir, corrupt))
+	autotask.AssertNil(t, err, "corrupt item should be quarantined")
+
+	// A released item is handed out again without reopening the queue
+	autotask.AssertNil(t, queue.Release(ctx, "good"), "release should succeed")
+	item, err = queue.Pop(ctx)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, "good", item.ID, "released item should be popped again")
+}
+
+func TestFileDeadLetters(t *testing.T) {
+	store, err := NewFileDeadLetters(t.TempDir())
+	autotask.AssertNil(t, err, "error should be nil")
+	ctx := context.Background()
+
+	autotask.AssertNil(t, store.Put(ctx, DeadLetter{Item: Item{ID: "x/1"}, Error: "boom", Attempts: 2, FailedAt: time.Now()}), "put should succeed")
+
+	letters, err := store.List(ctx)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, 1, len(letters), "dead letter should be stored")
+	autotask.AssertEqual(t, "x/1", letters[0].Item.ID, "item ID should be preserved")
+
+	autotask.AssertNil(t, store.Remove(ctx, "x/1"), "remove should succeed")
+	letters, _ = store.List(ctx)
+	autotask.AssertEqual(t, 0, len(letters), "dead letter should be removed")
+}
@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+// Default settings for a new Enricher
+const (
+	DefaultEnrichBatchWindow = 20 * time.Millisecond
+	DefaultEnrichCacheTTL    = 10 * time.Second
+	DefaultEnrichBatchSize   = 200
+)
+
+// entityType describes how to fetch and decode one entity type
+type entityType struct {
+	entityName string
+	decode     func(data []byte) (interface{}, error)
+}
+
+// decodeAs returns a decoder producing a *T
+func decodeAs[T any]() func(data []byte) (interface{}, error) {
+	return func(data []byte) (interface{}, error) {
+		v := new(T)
+		if err := json.Unmarshal(data, v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+// entityTypes maps entity types to their REST entities and Go types
+var entityTypes = map[string]entityType{
+	strings.ToLower(EntityCompany):           {"Companies", decodeAs[autotask.Company]()},
+	strings.ToLower(EntityContact):           {"Contacts", decodeAs[autotask.Contact]()},
+	strings.ToLower(EntityTicket):            {"Tickets", decodeAs[autotask.Ticket]()},
+	strings.ToLower(EntityTicketNote):        {"TicketNotes", decodeAs[autotask.TicketNote]()},
+	strings.ToLower(EntityConfigurationItem): {"ConfigurationItems", decodeAs[autotask.ConfigurationItem]()},
+	strings.ToLower(EntityProject):           {"Projects", decodeAs[autotask.Project]()},
+	strings.ToLower(EntityTask):              {"Tasks", decodeAs[autotask.Task]()},
+	strings.ToLower(EntityTimeEntry):         {"TimeEntries", decodeAs[autotask.TimeEntry]()},
+	strings.ToLower(EntityContract):          {"Contracts", decodeAs[autotask.Contract]()},
+	strings.ToLower(EntityResource):          {"Resources", decodeAs[autotask.Resource]()},
+}
+
+// Enricher loads the full entity for webhook events, whose payloads only
+// carry the fields the webhook is configured to send. It sets Event.Entity
+// to a typed pointer such as *autotask.Ticket before the handlers run.
+//
+// Lookups go through an autotask.GetBatcher, so lookups for the same entity
+// type that arrive within the batch window are combined into a single "in"
+// query and use the client's response cache. Set the client as the
+// receiver's CacheInvalidator so an event drops the client's cached copy of
+// its entity before it is enriched. Fetched entities are also
+// cached briefly so an event storm on one entity doesn't fetch it every
+// time. A cached entity is only used for events that happened before it
+// was fetched.
+type Enricher struct {
+	batcher *autotask.GetBatcher
+
+	mu       sync.Mutex
+	cacheTTL time.Duration
+	cache    map[string]cachedEntity
+}
+
+// cachedEntity is a fetched entity body
+type cachedEntity struct {
+	data      json.RawMessage
+	fetchedAt time.Time
+}
+
+// NewEnricher returns an enricher that fetches entities with client
+func NewEnricher(client autotask.Client) *Enricher {
+	return &Enricher{
+		batcher:  autotask.NewGetBatcher(client, DefaultEnrichBatchWindow, DefaultEnrichBatchSize),
+		cacheTTL: DefaultEnrichCacheTTL,
+		cache:    make(map[string]cachedEntity),
+	}
+}
+
+// SetBatchWindow sets how long lookups wait to be batched with others
+func (e *Enricher) SetBatchWindow(window time.Duration) {
+	e.batcher.SetWindow(window)
+}
+
+// SetCacheTTL sets how long fetched entities are reused. Zero disables the cache.
+func (e *Enricher) SetCacheTTL(ttl time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cacheTTL = ttl
+}
+
+// SetBatchSize sets the most IDs fetched by one query
+func (e *Enricher) SetBatchSize(size int) {
+	if size < 1 {
+		size = 1
+	}
+	e.batcher.SetMaxSize(size)
+}
+
+// Process sets event.Entity and calls next. Deletes and entity types the
+// enricher doesn't know are passed through unchanged, as are entities that
+// no longer exist. A failed lookup is returned without calling next.
+func (e *Enricher) Process(ctx context.Context, event *Event, next Handler) error {
+	if err := e.Enrich(ctx, event); err != nil {
+		return err
+	}
+	return next(ctx, event)
+}
+
+// Enrich fetches the entity for an event and stores it in event.Entity
+func (e *Enricher) Enrich(ctx context.Context, event *Event) error {
+	typ, ok := entityTypes[strings.ToLower(event.EntityType)]
+	if !ok || event.EntityID == 0 || event.Action == ActionDelete {
+		return nil
+	}
+
+	data, err := e.fetch(ctx, typ.entityName, event)
+	if err != nil {
+		return fmt.Errorf("failed to enrich %s %d: %w", event.EntityType, event.EntityID, err)
+	}
+	if data == nil {
+		return nil
+	}
+
+	entity, err := typ.decode(data)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s %d: %w", event.EntityType, event.EntityID, err)
+	}
+	event.Entity = entity
+	return nil
+}
+
+// fetch returns the entity body from the cache or the batcher. A nil body
+// means the entity wasn't found.
+func (e *Enricher) fetch(ctx context.Context, entityName string, event *Event) (json.RawMessage, error) {
+	key := fmt.Sprintf("%s:%d", entityName, event.EntityID)
+
+	e.mu.Lock()
+	if cached, ok := e.cache[key]; ok {
+		if time.Since(cached.fetchedAt) < e.cacheTTL && !cached.fetchedAt.Before(event.EventTime) {
+			e.mu.Unlock()
+			return cached.data, nil
+		}
+		delete(e.cache, key)
+	}
+	e.mu.Unlock()
+
+	data, err := e.batcher.Get(ctx, entityName, event.EntityID)
+	if err != nil || data == nil {
+		return data, err
+	}
+
+	e.mu.Lock()
+	if e.cacheTTL > 0 {
+		e.prune()
+		e.cache[key] = cachedEntity{data: data, fetchedAt: time.Now()}
+	}
+	e.mu.Unlock()
+	return data, nil
+}
+
+// prune drops expired cache entries. It must be called with e.mu held.
+func (e *Enricher) prune() {
+	for key, cached := range e.cache {
+		if time.Since(cached.fetchedAt) >= e.cacheTTL {
+			delete(e.cache, key)
+		}
+	}
+}
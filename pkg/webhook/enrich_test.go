@@ -0,0 +1,128 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+// newTicketQueryServer serves ticket "in" queries and Gets and counts them
+func newTicketQueryServer(t *testing.T, queries *int32) *autotask.MockServer {
+	server := autotask.NewMockServer(t)
+	server.AddHandler("/Tickets/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(queries, 1)
+		var item interface{}
+		if !strings.HasSuffix(r.URL.Path, "/404") {
+			item = map[string]interface{}{"id": 1, "title": "Ticket", "status": 1}
+		}
+		server.RespondWithJSON(w, http.StatusOK, autotask.Response{Item: item})
+	})
+	server.AddHandler("/Tickets/query", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(queries, 1)
+
+		var search struct {
+			Filter []struct {
+				Field string  `json:"field"`
+				Op    string  `json:"op"`
+				Value []int64 `json:"value"`
+			} `json:"filter"`
+		}
+		if err := json.Unmarshal([]byte(r.URL.Query().Get("search")), &search); err != nil || len(search.Filter) != 1 || search.Filter[0].Op != "in" {
+			server.RespondWithError(w, http.StatusBadRequest, "expected an in filter", nil)
+			return
+		}
+
+		items := []map[string]interface{}{}
+		for _, id := range search.Filter[0].Value {
+			if id != 404 {
+				items = append(items, map[string]interface{}{"id": id, "title": "Ticket", "status": 1})
+			}
+		}
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+	})
+	return server
+}
+
+func TestEnricherBatchesLookups(t *testing.T) {
+	var queries int32
+	server := newTicketQueryServer(t, &queries)
+	defer server.Close()
+
+	enricher := NewEnricher(server.NewTestClient())
+	enricher.SetBatchWindow(50 * time.Millisecond)
+
+	events := []*Event{
+		{Action: ActionUpdate, EntityType: EntityTicket, EntityID: 1},
+		{Action: ActionUpdate, EntityType: EntityTicket, EntityID: 2},
+		{Action: ActionCreate, EntityType: EntityTicket, EntityID: 404},
+	}
+
+	var wg sync.WaitGroup
+	for _, event := range events {
+		wg.Add(1)
+		go func(event *Event) {
+			defer wg.Done()
+			if err := enricher.Enrich(context.Background(), event); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(event)
+	}
+	wg.Wait()
+
+	autotask.AssertEqual(t, int32(1), atomic.LoadInt32(&queries), "lookups should share one query")
+	ticket, ok := events[0].Entity.(*autotask.Ticket)
+	autotask.AssertTrue(t, ok, "entity should be a *autotask.Ticket")
+	autotask.AssertEqual(t, int64(1), ticket.ID, "ticket should match the event")
+	autotask.AssertNil(t, events[2].Entity, "missing entity should be left nil")
+}
+
+func TestEnricherCache(t *testing.T) {
+	var queries int32
+	server := newTicketQueryServer(t, &queries)
+	defer server.Close()
+
+	enricher := NewEnricher(server.NewTestClient())
+	enricher.SetBatchWindow(time.Millisecond)
+	ctx := context.Background()
+
+	before := time.Now()
+	autotask.AssertNil(t, enricher.Enrich(ctx, &Event{Action: ActionUpdate, EntityType: EntityTicket, EntityID: 1, EventTime: before}), "error should be nil")
+	autotask.AssertNil(t, enricher.Enrich(ctx, &Event{Action: ActionUpdate, EntityType: EntityTicket, EntityID: 1, EventTime: before}), "error should be nil")
+	autotask.AssertEqual(t, int32(1), atomic.LoadInt32(&queries), "cached entity should be reused")
+
+	later := &Event{Action: ActionUpdate, EntityType: EntityTicket, EntityID: 1, EventTime: time.Now().Add(time.Second)}
+	autotask.AssertNil(t, enricher.Enrich(ctx, later), "error should be nil")
+	autotask.AssertEqual(t, int32(2), atomic.LoadInt32(&queries), "newer event should fetch again")
+
+	deleted := &Event{Action: ActionDelete, EntityType: EntityTicket, EntityID: 1}
+	autotask.AssertNil(t, enricher.Enrich(ctx, deleted), "error should be nil")
+	autotask.AssertNil(t, deleted.Entity, "deletes should not be enriched")
+}
+
+func TestReceiverEnricher(t *testing.T) {
+	var queries int32
+	server := newTicketQueryServer(t, &queries)
+	defer server.Close()
+
+	enricher := NewEnricher(server.NewTestClient())
+	enricher.SetBatchWindow(time.Millisecond)
+
+	receiver := NewReceiver("secret")
+	receiver.SetEnricher(enricher)
+
+	var title string
+	receiver.Handle(EntityTicket, ActionUpdate, func(ctx context.Context, event *Event) error {
+		title = event.Entity.(*autotask.Ticket).Title
+		return nil
+	})
+
+	autotask.AssertEqual(t, http.StatusOK, post(receiver, "secret", samplePayload), "webhook should be accepted")
+	autotask.AssertEqual(t, "Ticket", title, "handler should see the full ticket")
+}
@@ -0,0 +1,199 @@
+// Package webhook receives Autotask webhook callbacks. It verifies the
+// signature Autotask sends, parses the payload into typed events and
+// dispatches them to handlers registered by entity type and action.
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Action is the kind of change a webhook event reports
+type Action string
+
+const (
+	ActionCreate      Action = "Create"
+	ActionUpdate      Action = "Update"
+	ActionDelete      Action = "Delete"
+	ActionDeactivated Action = "Deactivated"
+)
+
+// Entity types that Autotask can send webhooks for
+const (
+	EntityCompany           = "Company"
+	EntityContact           = "Contact"
+	EntityTicket            = "Ticket"
+	EntityTicketNote        = "TicketNote"
+	EntityConfigurationItem = "ConfigurationItem"
+)
+
+// Entity types without webhook support, whose events come from a Poller
+const (
+	EntityProject   = "Project"
+	EntityTask      = "Task"
+	EntityTimeEntry = "TimeEntry"
+	EntityContract  = "Contract"
+	EntityResource  = "Resource"
+)
+
+// payload is the JSON body Autotask posts for a webhook callback
+type payload struct {
+	Action         Action                     `json:"Action"`
+	Guid           string                     `json:"Guid"`
+	EntityType     string                     `json:"EntityType"`
+	Id             int64                      `json:"Id"`
+	Fields         map[string]json.RawMessage `json:"Fields"`
+	EventTime      string                     `json:"EventTime"`
+	SequenceNumber int64                      `json:"SequenceNumber"`
+	PersonID       int64                      `json:"PersonID"`
+}
+
+// Event is a webhook callback from Autotask
+type Event struct {
+	// Action is the kind of change
+	Action Action
+
+	// GUID uniquely identifies the delivery
+	GUID string
+
+	// EntityType is the type of the changed entity, such as "Ticket"
+	EntityType string
+
+	// EntityID is the ID of the changed entity
+	EntityID int64
+
+	// Fields holds the values of the fields the webhook is configured to
+	// send, keyed by field name
+	Fields map[string]json.RawMessage
+
+	// EventTime is when the change happened
+	EventTime time.Time
+
+	// SequenceNumber increases with each change to the same entity
+	SequenceNumber int64
+
+	// PersonID is the resource or contact who made the change
+	PersonID int64
+
+	// Raw is the body as received
+	Raw json.RawMessage
+
+	// Entity is the full entity, such as *autotask.Ticket, when the
+	// receiver has an Enricher. It is nil otherwise, for deletes and when the
+	// entity no longer exists.
+	Entity interface{}
+}
+
+// eventTimeLayouts are the formats Autotask uses for EventTime
+var eventTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.9999999",
+	"2006-01-02T15:04:05",
+}
+
+// ParseEvent parses the body of an Autotask webhook callback
+func ParseEvent(body []byte) (*Event, error) {
+	var p payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+	}
+	if p.EntityType == "" || p.Action == "" {
+		return nil, fmt.Errorf("webhook payload is missing EntityType or Action")
+	}
+
+	event := &Event{
+		Action:         p.Action,
+		GUID:           p.Guid,
+		EntityType:     p.EntityType,
+		EntityID:       p.Id,
+		Fields:         p.Fields,
+		SequenceNumber: p.SequenceNumber,
+		PersonID:       p.PersonID,
+		Raw:            json.RawMessage(body),
+	}
+	if event.Fields == nil {
+		event.Fields = make(map[string]json.RawMessage)
+	}
+
+	if p.EventTime != "" {
+		t, err := parseEventTime(p.EventTime)
+		if err != nil {
+			return nil, err
+		}
+		event.EventTime = t
+	}
+
+	return event, nil
+}
+
+// parseEventTime parses an EventTime value, treating times without a zone as UTC
+func parseEventTime(value string) (time.Time, error) {
+	for _, layout := range eventTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid webhook EventTime %q", value)
+}
+
+// Is reports whether the event is for the given entity type and action,
+// ignoring case
+func (e *Event) Is(entityType string, action Action) bool {
+	return strings.EqualFold(e.EntityType, entityType) && strings.EqualFold(string(e.Action), string(action))
+}
+
+// FieldNames returns the names of the fields included in the event, sorted
+func (e *Event) FieldNames() []string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Field decodes the named field into v, matching the name without regard to
+// case. It reports whether the field was present.
+func (e *Event) Field(name string, v interface{}) (bool, error) {
+	raw, ok := e.Fields[name]
+	if !ok {
+		for k, value := range e.Fields {
+			if strings.EqualFold(k, name) {
+				raw, ok = value, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("failed to decode field %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// Decode decodes the event fields and entity ID into an entity struct such
+// as autotask.Ticket. Fields not included in the webhook are left unchanged.
+func (e *Event) Decode(v interface{}) error {
+	fields := make(map[string]json.RawMessage, len(e.Fields)+1)
+	for k, value := range e.Fields {
+		fields[k] = value
+	}
+	if ok, _ := e.Field("id", new(json.RawMessage)); !ok && e.EntityID != 0 {
+		fields["id"] = json.RawMessage(strconv.FormatInt(e.EntityID, 10))
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook fields: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode webhook fields: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/asachs01/autotask-go/internal/telemetry"
+)
+
+// Middleware wraps the processing of an event with cross-cutting behavior
+// such as logging, panic recovery, metrics or timeouts. A middleware may
+// modify the context, skip the event, or inspect the error before returning it.
+type Middleware func(next Handler) Handler
+
+// chain composes middleware around a Handler. The first middleware is the
+// outermost and sees the event first.
+func chain(handler Handler, middleware ...Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			handler = middleware[i](handler)
+		}
+	}
+	return handler
+}
+
+// PanicError is returned by RecoverMiddleware when a handler panics
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Error returns the panic value
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("webhook handler panic: %v", e.Value)
+}
+
+// LoggingMiddleware logs each event with its duration and outcome. A nil
+// logger uses slog.Default().
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, event *Event) error {
+			start := time.Now()
+			err := next(ctx, event)
+
+			attrs := []any{
+				"entity_type", event.EntityType,
+				"action", event.Action,
+				"entity_id", event.EntityID,
+				"guid", event.GUID,
+				"duration", time.Since(start),
+			}
+			if err != nil {
+				logger.ErrorContext(ctx, "Webhook event failed", append(attrs, "error", err)...)
+			} else {
+				logger.DebugContext(ctx, "Webhook event processed", attrs...)
+			}
+			return err
+		}
+	}
+}
+
+// RecoverMiddleware turns a panic in a handler into a *PanicError, so one bad
+// event doesn't take down the server or a dispatcher worker
+func RecoverMiddleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, event *Event) (err error) {
+			defer func() {
+				if v := recover(); v != nil {
+					err = &PanicError{Value: v, Stack: debug.Stack()}
+				}
+			}()
+			return next(ctx, event)
+		}
+	}
+}
+
+// TimeoutMiddleware cancels the context passed to handlers after timeout.
+// Handlers must watch the context for the timeout to take effect.
+func TimeoutMiddleware(timeout time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, event *Event) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next(ctx, event)
+		}
+	}
+}
+
+// TelemetryMiddleware records an OpenTelemetry span and event metrics for
+// each event using the globally registered tracer and meter providers.
+func TelemetryMiddleware() Middleware {
+	t := telemetry.New(true)
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, event *Event) error {
+			start := time.Now()
+			ctx, span := t.StartWebhookSpan(ctx, event.EntityType, string(event.Action))
+
+			err := next(ctx, event)
+
+			t.EndWebhookSpan(ctx, span, event.EntityType, string(event.Action), time.Since(start), err)
+			return err
+		}
+	}
+}
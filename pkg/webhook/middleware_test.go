@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	receiver := NewReceiver("")
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, event *Event) error {
+				calls = append(calls, name+">")
+				err := next(ctx, event)
+				calls = append(calls, "<"+name)
+				return err
+			}
+		}
+	}
+	receiver.Use(trace("outer"), trace("inner"))
+	receiver.HandleAll(func(ctx context.Context, event *Event) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+
+	autotask.AssertNil(t, receiver.Dispatch(context.Background(), &Event{Action: ActionUpdate, EntityType: EntityTicket}), "error should be nil")
+	autotask.AssertEqual(t, "outer>,inner>,handler,<inner,<outer", strings.Join(calls, ","), "first middleware should be outermost")
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	receiver := NewReceiver("secret")
+	receiver.Use(RecoverMiddleware())
+	receiver.Handle(EntityTicket, ActionUpdate, func(ctx context.Context, event *Event) error {
+		panic("boom")
+	})
+
+	err := receiver.Dispatch(context.Background(), &Event{Action: ActionUpdate, EntityType: EntityTicket})
+	var panicErr *PanicError
+	autotask.AssertTrue(t, errors.As(err, &panicErr), "panic should be returned as a PanicError")
+	autotask.AssertEqual(t, "boom", panicErr.Value, "panic value should be kept")
+	autotask.AssertTrue(t, len(panicErr.Stack) > 0, "stack should be captured")
+
+	autotask.AssertEqual(t, http.StatusInternalServerError, post(receiver, "secret", samplePayload), "panic should fail the request")
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	receiver := NewReceiver("")
+	receiver.Use(TimeoutMiddleware(10 * time.Millisecond))
+	receiver.HandleAll(func(ctx context.Context, event *Event) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := receiver.Dispatch(context.Background(), &Event{Action: ActionUpdate, EntityType: EntityTicket})
+	autotask.AssertTrue(t, errors.Is(err, context.DeadlineExceeded), "handler should see the deadline")
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	receiver := NewReceiver("")
+	receiver.Use(LoggingMiddleware(logger), TelemetryMiddleware())
+	receiver.HandleAll(func(ctx context.Context, event *Event) error {
+		return errors.New("rejected")
+	})
+
+	err := receiver.Dispatch(context.Background(), &Event{Action: ActionUpdate, EntityType: EntityTicket, EntityID: 7})
+	autotask.AssertNotNil(t, err, "handler error should be returned")
+	autotask.AssertContains(t, buf.String(), "Webhook event failed", "failure should be logged")
+	autotask.AssertContains(t, buf.String(), "entity_id=7", "entity ID should be logged")
+}
+
+func TestReceiverConcurrentRegistration(t *testing.T) {
+	receiver := NewReceiver("")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			receiver.HandleAll(func(ctx context.Context, event *Event) error { return nil })
+			receiver.Use(func(next Handler) Handler { return next })
+		}()
+		go func() {
+			defer wg.Done()
+			_ = receiver.Dispatch(context.Background(), &Event{Action: ActionUpdate, EntityType: EntityTicket})
+		}()
+	}
+	wg.Wait()
+}
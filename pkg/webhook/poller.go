@@ -0,0 +1,436 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+// Default settings for a new Poller
+const (
+	DefaultPollInterval    = time.Minute
+	DefaultPollOverlap     = time.Minute
+	DefaultPollMaxAttempts = 5
+	DefaultPollRetention   = 24 * time.Hour
+)
+
+// modifiedDateFields and createdDateFields are tried in order when the date
+// fields aren't set explicitly
+var (
+	modifiedDateFields = []string{"lastActivityDate", "lastModifiedDate", "lastModifiedDateTime", "lastActivityDateTime"}
+	createdDateFields  = []string{"createDate", "createDateTime", "createdDate", "createdDateTime"}
+)
+
+// Poller is a change feed for entities that Autotask can't send webhooks
+// for, such as time entries, projects, tasks and contracts. Each poll queries
+// the entities modified since the last one, compares their fields with a
+// snapshot and passes an Event for each created or changed entity to the
+// handler. Events have the same shape as webhook events: Fields holds the
+// changed fields, named as in webhook payloads, and Entity holds the full
+// typed entity, so a Receiver's Dispatch can serve as the handler.
+//
+// Entities first seen after the poller started are reported as created when
+// their creation date is after the previous poll, and as updated with all
+// their fields otherwise. Deletions can't be detected by polling. Snapshots
+// of entities that haven't changed for longer than the retention are
+// dropped, so an entity that changes after a longer quiet spell is also
+// reported with all its fields.
+type Poller struct {
+	service    autotask.EntityService
+	entityType string
+	handler    Handler
+	logger     *slog.Logger
+
+	interval    time.Duration
+	overlap     time.Duration
+	dateField   string
+	createField string
+	fields      map[string]bool
+	maxAttempts int
+	retention   time.Duration
+
+	mu        sync.Mutex
+	since     time.Time
+	snapshots map[int64]polledSnapshot
+	failures  map[int64]int
+}
+
+// polledSnapshot holds the compared fields of an entity as last seen
+type polledSnapshot struct {
+	fields   map[string]json.RawMessage
+	modified time.Time
+}
+
+// NewPoller returns a poller for service whose events have entityType, such
+// as EntityTimeEntry, and are passed to handler. It reports changes made
+// after it was created; use SetSince to start earlier.
+func NewPoller(service autotask.EntityService, entityType string, handler Handler) *Poller {
+	return &Poller{
+		service:     service,
+		entityType:  entityType,
+		handler:     handler,
+		logger:      slog.Default(),
+		interval:    DefaultPollInterval,
+		overlap:     DefaultPollOverlap,
+		maxAttempts: DefaultPollMaxAttempts,
+		retention:   DefaultPollRetention,
+		since:       time.Now().UTC(),
+		snapshots:   make(map[int64]polledSnapshot),
+		failures:    make(map[int64]int),
+	}
+}
+
+// SetInterval sets the time between polls made by Run
+func (p *Poller) SetInterval(interval time.Duration) {
+	p.interval = interval
+}
+
+// SetOverlap sets how far before the last poll each query starts, so that
+// changes saved while a poll was running aren't missed. Changes seen twice
+// are only reported once.
+func (p *Poller) SetOverlap(overlap time.Duration) {
+	p.overlap = overlap
+}
+
+// SetMaxAttempts sets how many polls in a row may fail to handle a change
+// before it is logged and skipped, so one bad entity can't hold back the
+// feed forever
+func (p *Poller) SetMaxAttempts(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if n < 1 {
+		n = 1
+	}
+	p.maxAttempts = n
+}
+
+// SetRetention sets how long the snapshot of an entity that hasn't changed
+// is kept. A retention shorter than the overlap uses the overlap.
+func (p *Poller) SetRetention(retention time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retention = retention
+}
+
+// SetDateField sets the field holding when an entity last changed. By
+// default the first of lastActivityDate, lastModifiedDate,
+// lastModifiedDateTime and lastActivityDateTime in the entity's field
+// metadata is used.
+func (p *Poller) SetDateField(name string) {
+	p.dateField = name
+}
+
+// SetCreateDateField sets the field holding when an entity was created. By
+// default the first of createDate, createDateTime, createdDate and
+// createdDateTime in the entity's field metadata is used.
+func (p *Poller) SetCreateDateField(name string) {
+	p.createField = name
+}
+
+// SetFields limits the fields compared between polls, like the subscribed
+// fields of a webhook. By default every field is compared.
+func (p *Poller) SetFields(names ...string) {
+	p.fields = make(map[string]bool, len(names))
+	for _, name := range names {
+		p.fields[strings.ToLower(name)] = true
+	}
+}
+
+// SetSince sets the time changes are reported from
+func (p *Poller) SetSince(since time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.since = since.UTC()
+}
+
+// SetLogger sets the logger used to report failed polls
+func (p *Poller) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	p.logger = logger
+}
+
+// Run polls every interval until ctx is done. Failed polls are logged and
+// retried at the next interval.
+func (p *Poller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			p.logger.ErrorContext(ctx, "Change feed poll failed", "entity", p.service.GetEntityName(), "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll queries for changes once and passes an event for each to the handler,
+// oldest first. It returns the number of events handled. Entities whose
+// handler fails are reported again by the next poll, up to the maximum
+// attempts; changes handled after the first failure aren't reported again.
+func (p *Poller) Poll(ctx context.Context) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.resolveDateFields(ctx); err != nil {
+		return 0, err
+	}
+
+	filter := fmt.Sprintf("%s>%s", p.dateField, autotask.NewDateTime(p.since.Add(-p.overlap)))
+	items, err := autotask.FetchAllPages[map[string]json.RawMessage](ctx, p.service, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to poll %s: %w", p.service.GetEntityName(), err)
+	}
+
+	changes := make([]polledChange, 0, len(items))
+	latest := p.since
+	for _, item := range items {
+		change, ok, err := p.diff(item)
+		if err != nil {
+			return 0, err
+		}
+		if change.modified.After(latest) {
+			latest = change.modified
+		}
+		if ok {
+			changes = append(changes, change)
+		} else if _, seen := p.snapshots[change.id]; !seen {
+			// Changed before the poller started; remember it for later diffs
+			p.snapshots[change.id] = polledSnapshot{fields: change.snapshot, modified: change.modified}
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].modified.Before(changes[j].modified) })
+
+	handled := 0
+	var errs []error
+	var firstFailed time.Time
+	for _, change := range changes {
+		event, err := p.event(change)
+		if err == nil {
+			err = p.handler(ctx, event)
+		}
+		if err != nil {
+			p.failures[change.id]++
+			if p.failures[change.id] < p.maxAttempts {
+				errs = append(errs, fmt.Errorf("%s %d: %w", p.entityType, change.id, err))
+				if firstFailed.IsZero() {
+					firstFailed = change.modified
+				}
+				continue
+			}
+			p.logger.ErrorContext(ctx, "Skipping change after repeated failures",
+				"entity", p.service.GetEntityName(),
+				"entity_id", change.id,
+				"attempts", p.failures[change.id],
+				"error", err,
+			)
+		} else {
+			handled++
+		}
+		delete(p.failures, change.id)
+		p.snapshots[change.id] = polledSnapshot{fields: change.snapshot, modified: change.modified}
+	}
+
+	if len(errs) > 0 {
+		// Move the window up to the oldest failed change so it is queried
+		// again, while changes handled before it aren't
+		if since := firstFailed.Add(-time.Millisecond); since.After(p.since) {
+			p.since = since
+		}
+		p.prune()
+		return handled, errors.Join(errs...)
+	}
+	p.since = latest
+	p.prune()
+	return handled, nil
+}
+
+// prune drops the snapshots of entities that haven't changed within the
+// retention, or within the overlap when that is longer
+func (p *Poller) prune() {
+	keep := p.retention
+	if keep < p.overlap {
+		keep = p.overlap
+	}
+	cutoff := p.since.Add(-keep)
+	for id, snapshot := range p.snapshots {
+		if snapshot.modified.Before(cutoff) {
+			delete(p.snapshots, id)
+		}
+	}
+}
+
+// polledChange is a created or changed entity found by a poll
+type polledChange struct {
+	id       int64
+	action   Action
+	modified time.Time
+	changed  map[string]json.RawMessage
+	snapshot map[string]json.RawMessage
+	item     map[string]json.RawMessage
+}
+
+// diff compares a polled entity with its snapshot. It reports false when
+// there is nothing to emit.
+func (p *Poller) diff(item map[string]json.RawMessage) (polledChange, bool, error) {
+	change := polledChange{item: item, snapshot: make(map[string]json.RawMessage, len(item))}
+
+	if err := json.Unmarshal(item["id"], &change.id); err != nil || change.id == 0 {
+		return change, false, fmt.Errorf("polled %s has no id", p.service.GetEntityName())
+	}
+	change.modified = p.dateValue(item, p.dateField)
+
+	for name, value := range item {
+		if name == "id" || (p.fields != nil && !p.fields[strings.ToLower(name)]) {
+			continue
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, value); err != nil {
+			return change, false, fmt.Errorf("invalid %s field %s: %w", p.entityType, name, err)
+		}
+		change.snapshot[name] = compact.Bytes()
+	}
+
+	previous, seen := p.snapshots[change.id]
+	if !seen {
+		if !change.modified.After(p.since) {
+			return change, false, nil
+		}
+		change.action = ActionUpdate
+		if p.createField != "" && p.dateValue(item, p.createField).After(p.since) {
+			change.action = ActionCreate
+		}
+		change.changed = change.snapshot
+		return change, true, nil
+	}
+
+	change.action = ActionUpdate
+	change.changed = make(map[string]json.RawMessage)
+	for name, value := range change.snapshot {
+		if !bytes.Equal(previous.fields[name], value) {
+			change.changed[name] = value
+		}
+	}
+	return change, len(change.changed) > 0, nil
+}
+
+// event builds a webhook-shaped event for a change
+func (p *Poller) event(change polledChange) (*Event, error) {
+	fields := make(map[string]json.RawMessage, len(change.changed))
+	for name, value := range change.changed {
+		fields[payloadFieldName(name)] = value
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"Action":         change.action,
+		"Guid":           newGUID(),
+		"EntityType":     p.entityType,
+		"Id":             change.id,
+		"Fields":         fields,
+		"EventTime":      change.modified.Format(time.RFC3339Nano),
+		"SequenceNumber": change.modified.UnixMilli(),
+		"PersonID":       0,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal polled event: %w", err)
+	}
+
+	event, err := ParseEvent(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if typ, ok := entityTypes[strings.ToLower(p.entityType)]; ok {
+		data, err := json.Marshal(change.item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal polled entity: %w", err)
+		}
+		if event.Entity, err = typ.decode(data); err != nil {
+			return nil, fmt.Errorf("failed to decode polled entity: %w", err)
+		}
+	}
+	return event, nil
+}
+
+// resolveDateFields picks the date fields from the entity's field metadata
+// when they weren't set
+func (p *Poller) resolveDateFields(ctx context.Context) error {
+	if p.dateField != "" && p.createField != "" {
+		return nil
+	}
+
+	fields, err := p.service.GetFieldInfo(ctx)
+	if err != nil {
+		if p.dateField != "" {
+			// The creation date is optional
+			return nil
+		}
+		return fmt.Errorf("failed to find the modification date field for %s: %w", p.service.GetEntityName(), err)
+	}
+
+	if p.dateField == "" {
+		p.dateField = firstField(fields, modifiedDateFields)
+		if p.dateField == "" {
+			return fmt.Errorf("%s has no modification date field; use SetDateField", p.service.GetEntityName())
+		}
+	}
+	if p.createField == "" {
+		p.createField = firstField(fields, createdDateFields)
+	}
+	return nil
+}
+
+// firstField returns the first of names that is in fields, as named in fields
+func firstField(fields []autotask.FieldInfo, names []string) string {
+	for _, name := range names {
+		if field, ok := autotask.FindField(fields, name); ok {
+			return field.Name
+		}
+	}
+	return ""
+}
+
+// dateValue returns a date field of a polled entity, or the zero time
+func (p *Poller) dateValue(item map[string]json.RawMessage, name string) time.Time {
+	for key, value := range item {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		var t autotask.DateTime
+		if json.Unmarshal(value, &t) != nil {
+			return time.Time{}
+		}
+		return t.Time
+	}
+	return time.Time{}
+}
+
+// payloadFieldName converts a REST field name such as lastActivityDate to
+// the form used in webhook payloads, LastActivityDate
+func payloadFieldName(name string) string {
+	if name == "" {
+		return name
+	}
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
@@ -0,0 +1,194 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+// fakeTimeEntries serves time entry queries from a mutable list
+type fakeTimeEntries struct {
+	mu      sync.Mutex
+	entries []map[string]interface{}
+}
+
+// set replaces the entries returned by queries
+func (f *fakeTimeEntries) set(entries ...map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries = entries
+}
+
+func newTimeEntryServer(t *testing.T, fake *fakeTimeEntries) *autotask.MockServer {
+	server := autotask.NewMockServer(t)
+	server.AddHandler("/TimeEntries/entityInformation/fields", func(w http.ResponseWriter, r *http.Request) {
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"fields": []map[string]interface{}{
+			{"name": "id", "dataType": "long"},
+			{"name": "createDate", "dataType": "datetime"},
+			{"name": "lastModifiedDate", "dataType": "datetime"},
+			{"name": "hoursWorked", "dataType": "decimal"},
+		}})
+	})
+	server.AddHandler("/TimeEntries/query", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Query().Get("search"), `"field":"lastModifiedDate"`) {
+			server.RespondWithError(w, http.StatusBadRequest, "expected a lastModifiedDate filter", nil)
+			return
+		}
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		server.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"items": fake.entries, "pageDetails": map[string]interface{}{}})
+	})
+	return server
+}
+
+// timeEntry returns a time entry as the API would
+func timeEntry(id int64, created, modified time.Time, hours float64) map[string]interface{} {
+	return map[string]interface{}{
+		"id":               id,
+		"createDate":       created.Format(time.RFC3339),
+		"lastModifiedDate": modified.Format(time.RFC3339),
+		"hoursWorked":      hours,
+	}
+}
+
+func TestPoller(t *testing.T) {
+	fake := &fakeTimeEntries{}
+	server := newTimeEntryServer(t, fake)
+	defer server.Close()
+
+	start := time.Now().UTC().Truncate(time.Second)
+	var events []*Event
+	var fail bool
+	handler := func(ctx context.Context, event *Event) error {
+		if fail {
+			return errors.New("unavailable")
+		}
+		events = append(events, event)
+		return nil
+	}
+
+	poller := NewPoller(server.NewTestClient().TimeEntries(), EntityTimeEntry, handler)
+	poller.SetSince(start)
+	ctx := context.Background()
+
+	// An entry changed before the poller started is only remembered
+	fake.set(
+		timeEntry(1, start.Add(-2*time.Hour), start.Add(-time.Hour), 1),
+		timeEntry(2, start.Add(time.Minute), start.Add(time.Minute), 2),
+	)
+	n, err := poller.Poll(ctx)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, 1, n, "only the new entry should be reported")
+	autotask.AssertEqual(t, ActionCreate, events[0].Action, "new entry should be reported as created")
+	autotask.AssertEqual(t, int64(2), events[0].EntityID, "event should identify the entry")
+	entry, ok := events[0].Entity.(*autotask.TimeEntry)
+	autotask.AssertTrue(t, ok, "entity should be a *autotask.TimeEntry")
+	autotask.AssertEqual(t, 2.0, entry.HoursWorked, "entity should be decoded")
+
+	// Changing entry 1 reports only the changed fields
+	fake.set(
+		timeEntry(1, start.Add(-2*time.Hour), start.Add(2*time.Minute), 1.5),
+		timeEntry(2, start.Add(time.Minute), start.Add(time.Minute), 2),
+	)
+	events = nil
+	n, err = poller.Poll(ctx)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, 1, n, "only the changed entry should be reported")
+	autotask.AssertEqual(t, ActionUpdate, events[0].Action, "change should be reported as an update")
+	autotask.AssertEqual(t, "HoursWorked,LastModifiedDate", strings.Join(events[0].FieldNames(), ","), "changed fields should be listed as in webhook payloads")
+
+	var hours float64
+	found, err := events[0].Field("hoursWorked", &hours)
+	autotask.AssertTrue(t, found && err == nil, "changed field should be readable")
+	autotask.AssertEqual(t, 1.5, hours, "field should hold the new value")
+
+	// A failed handler sees the change again on the next poll
+	fake.set(timeEntry(1, start.Add(-2*time.Hour), start.Add(3*time.Minute), 3))
+	fail = true
+	_, err = poller.Poll(ctx)
+	autotask.AssertNotNil(t, err, "handler error should be returned")
+
+	fail = false
+	events = nil
+	n, err = poller.Poll(ctx)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, 1, n, "failed change should be reported again")
+
+	// Nothing changed
+	n, err = poller.Poll(ctx)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, 0, n, "unchanged entries should not be reported")
+}
+
+func TestPollerFeedsReceiver(t *testing.T) {
+	fake := &fakeTimeEntries{}
+	server := newTimeEntryServer(t, fake)
+	defer server.Close()
+
+	start := time.Now().UTC().Truncate(time.Second)
+	fake.set(timeEntry(5, start.Add(time.Minute), start.Add(time.Minute), 1))
+
+	receiver := NewReceiver("")
+	var got int64
+	receiver.Handle(EntityTimeEntry, ActionCreate, func(ctx context.Context, event *Event) error {
+		got = event.Entity.(*autotask.TimeEntry).ID
+		return nil
+	})
+
+	poller := NewPoller(server.NewTestClient().TimeEntries(), EntityTimeEntry, receiver.Dispatch)
+	poller.SetSince(start)
+	_, err := poller.Poll(context.Background())
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, int64(5), got, "receiver handlers should see polled events")
+}
+
+func TestPollerLimits(t *testing.T) {
+	fake := &fakeTimeEntries{}
+	server := newTimeEntryServer(t, fake)
+	defer server.Close()
+
+	start := time.Now().UTC().Truncate(time.Second)
+	attempts := map[int64]int{}
+	handler := func(ctx context.Context, event *Event) error {
+		attempts[event.EntityID]++
+		if event.EntityID == 1 {
+			return errors.New("rejected")
+		}
+		return nil
+	}
+
+	poller := NewPoller(server.NewTestClient().TimeEntries(), EntityTimeEntry, handler)
+	poller.SetSince(start)
+	poller.SetOverlap(0)
+	poller.SetRetention(0)
+	poller.SetMaxAttempts(2)
+	ctx := context.Background()
+
+	fake.set(
+		timeEntry(1, start.Add(time.Minute), start.Add(time.Minute), 1),
+		timeEntry(2, start.Add(2*time.Minute), start.Add(2*time.Minute), 2),
+	)
+	n, err := poller.Poll(ctx)
+	autotask.AssertNotNil(t, err, "handler error should be returned")
+	autotask.AssertEqual(t, 1, n, "changes after a failed one should still be handled")
+
+	n, err = poller.Poll(ctx)
+	autotask.AssertNil(t, err, "change should be skipped after the maximum attempts")
+	autotask.AssertEqual(t, 0, n, "handled change should not be reported again")
+	autotask.AssertEqual(t, 2, attempts[1], "failed change should be retried up to the maximum attempts")
+	autotask.AssertEqual(t, 1, attempts[2], "handled change should be reported once")
+
+	n, err = poller.Poll(ctx)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, 0, n, "skipped change should not be reported again")
+
+	_, kept := poller.snapshots[2]
+	autotask.AssertEqual(t, 1, len(poller.snapshots), "snapshots older than the retention should be dropped")
+	autotask.AssertTrue(t, kept, "latest change should be kept")
+}
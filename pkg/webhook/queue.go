@@ -0,0 +1,319 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQueueFull is returned by Push when a queue cannot accept more items
+var ErrQueueFull = errors.New("webhook queue is full")
+
+// Item is a verified webhook body waiting to be processed
+type Item struct {
+	// ID identifies the item in its queue; it is the event GUID when there is one
+	ID string `json:"id"`
+
+	// Body is the raw webhook body
+	Body json.RawMessage `json:"body"`
+
+	// ReceivedAt is when the webhook was received
+	ReceivedAt time.Time `json:"receivedAt"`
+}
+
+// Queue stores webhook items until they have been processed. Pop hands out
+// an item without removing it; the item is only removed by Ack, so a durable
+// queue redelivers items that were in flight when the process stopped.
+type Queue interface {
+	// Push adds an item to the queue
+	Push(ctx context.Context, item Item) error
+
+	// Pop blocks until an item is available or ctx is done
+	Pop(ctx context.Context) (Item, error)
+
+	// Ack removes a processed item from the queue
+	Ack(ctx context.Context, id string) error
+}
+
+// Releaser is implemented by queues that can hand a popped item out again
+// without a restart. The dispatcher releases an item it could neither
+// process nor move to the dead-letter store.
+type Releaser interface {
+	// Release makes a popped item available to Pop again
+	Release(ctx context.Context, id string) error
+}
+
+// BadItemsDir is the subdirectory of a FileQueue where files that can't be
+// read or parsed are moved
+const BadItemsDir = "bad"
+
+// newItemID returns a random item ID for events without a GUID
+func newItemID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// MemoryQueue is a bounded in-memory Queue. Items are lost when the process exits.
+type MemoryQueue struct {
+	items chan Item
+}
+
+// NewMemoryQueue returns an in-memory queue holding up to size items
+func NewMemoryQueue(size int) *MemoryQueue {
+	if size <= 0 {
+		size = 1000
+	}
+	return &MemoryQueue{items: make(chan Item, size)}
+}
+
+// Push adds an item, returning ErrQueueFull when the queue is at capacity
+func (q *MemoryQueue) Push(ctx context.Context, item Item) error {
+	select {
+	case q.items <- item:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Pop blocks until an item is available or ctx is done
+func (q *MemoryQueue) Pop(ctx context.Context) (Item, error) {
+	select {
+	case item := <-q.items:
+		return item, nil
+	case <-ctx.Done():
+		return Item{}, ctx.Err()
+	}
+}
+
+// Ack is a no-op; popped items are already removed from memory
+func (q *MemoryQueue) Ack(ctx context.Context, id string) error {
+	return nil
+}
+
+// Len returns the number of items waiting in the queue
+func (q *MemoryQueue) Len() int {
+	return len(q.items)
+}
+
+// FileQueue is a Queue that stores each item as a JSON file in a directory,
+// so items survive a restart. Items that were popped but not acknowledged
+// are delivered again by the next FileQueue opened on the directory, or
+// straight away once released. Files that can't be read or parsed are
+// moved to the BadItemsDir subdirectory so they don't block later items.
+type FileQueue struct {
+	dir          string
+	pollInterval time.Duration
+
+	mu       sync.Mutex
+	inFlight map[string]bool
+	bad      map[string]bool
+	notify   chan struct{}
+}
+
+// NewFileQueue opens a file-backed queue in dir, creating it if needed
+func NewFileQueue(dir string) (*FileQueue, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create queue directory: %w", err)
+	}
+	return &FileQueue{
+		dir:          dir,
+		pollInterval: time.Second,
+		inFlight:     make(map[string]bool),
+		bad:          make(map[string]bool),
+		notify:       make(chan struct{}, 1),
+	}, nil
+}
+
+// Push writes an item to disk
+func (q *FileQueue) Push(ctx context.Context, item Item) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal queue item: %w", err)
+	}
+
+	name := fmt.Sprintf("%020d-%s.json", item.ReceivedAt.UnixNano(), sanitizeFileName(item.ID))
+	if err := writeFileAtomic(filepath.Join(q.dir, name), data); err != nil {
+		return err
+	}
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Pop returns the oldest item that is not already in flight, waiting for one
+// to be pushed when the queue is empty
+func (q *FileQueue) Pop(ctx context.Context) (Item, error) {
+	for {
+		item, ok, err := q.next()
+		if err != nil || ok {
+			return item, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return Item{}, ctx.Err()
+		case <-q.notify:
+		case <-time.After(q.pollInterval):
+		}
+	}
+}
+
+// next claims the oldest waiting item
+func (q *FileQueue) next() (Item, bool, error) {
+	names, err := q.fileNames()
+	if err != nil {
+		return Item{}, false, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, name := range names {
+		if q.inFlight[name] || q.bad[name] {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(q.dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		var item Item
+		if err == nil {
+			err = json.Unmarshal(data, &item)
+		}
+		if err != nil {
+			q.quarantine(name)
+			continue
+		}
+		q.inFlight[name] = true
+		return item, true, nil
+	}
+	return Item{}, false, nil
+}
+
+// quarantine moves an unreadable item file to BadItemsDir. If it can't be
+// moved, it is skipped until the queue is reopened. It must be called with
+// q.mu held.
+func (q *FileQueue) quarantine(name string) {
+	badDir := filepath.Join(q.dir, BadItemsDir)
+	if err := os.MkdirAll(badDir, 0o700); err == nil {
+		if err := os.Rename(filepath.Join(q.dir, name), filepath.Join(badDir, name)); err == nil {
+			return
+		}
+	}
+	q.bad[name] = true
+}
+
+// Ack deletes a processed item from disk
+func (q *FileQueue) Ack(ctx context.Context, id string) error {
+	suffix := "-" + sanitizeFileName(id) + ".json"
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for name := range q.inFlight {
+		if strings.HasSuffix(name, suffix) {
+			delete(q.inFlight, name)
+			if err := os.Remove(filepath.Join(q.dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to remove queue item: %w", err)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// Release makes a popped item available to Pop again
+func (q *FileQueue) Release(ctx context.Context, id string) error {
+	suffix := "-" + sanitizeFileName(id) + ".json"
+
+	q.mu.Lock()
+	for name := range q.inFlight {
+		if strings.HasSuffix(name, suffix) {
+			delete(q.inFlight, name)
+		}
+	}
+	q.mu.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Len returns the number of items stored, including those in flight
+func (q *FileQueue) Len() (int, error) {
+	names, err := q.fileNames()
+	return len(names), err
+}
+
+// fileNames returns the item files in the queue directory, oldest first
+func (q *FileQueue) fileNames() ([]string, error) {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store file: %w", err)
+	}
+	return nil
+}
+
+// sanitizeFileName makes an item ID safe to use in a file name
+func sanitizeFileName(id string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, id)
+}
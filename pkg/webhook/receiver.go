@@ -0,0 +1,258 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// MaxBodyBytes is the largest webhook body the receiver accepts
+const MaxBodyBytes = 1 << 20
+
+// Handler processes a webhook event
+type Handler func(ctx context.Context, event *Event) error
+
+// Wildcard matches any entity type or action when registering a handler
+const Wildcard = "*"
+
+// route identifies the handlers for an entity type and action
+type route struct {
+	entityType string
+	action     string
+}
+
+// newRoute returns the case-insensitive route for an entity type and action
+func newRoute(entityType string, action Action) route {
+	return route{entityType: strings.ToLower(entityType), action: strings.ToLower(string(action))}
+}
+
+// matches reports whether the route, which may contain wildcards, matches an event
+func (rt route) matches(event route) bool {
+	return (rt.entityType == Wildcard || rt.entityType == event.entityType) &&
+		(rt.action == Wildcard || rt.action == event.action)
+}
+
+// registration is a handler and the route it was registered for
+type registration struct {
+	route   route
+	handler Handler
+}
+
+// Receiver is an http.Handler for Autotask webhook callbacks. It verifies
+// each request, parses the payload and calls the handlers registered for the
+// event's entity type and action. Handlers and middleware may be registered
+// while the receiver is serving.
+type Receiver struct {
+	verifier    Verifier
+	logger      *slog.Logger
+	sequencer   *Sequencer
+	enricher    *Enricher
+	invalidator CacheInvalidator
+
+	mu            sync.RWMutex
+	registrations []registration
+	middleware    []Middleware
+}
+
+// NewReceiver returns a receiver that verifies the X-Hook-Signature header
+// with the webhook's secret key. An empty secret, such as an unset
+// environment variable, rejects every request with ErrNoSecret rather than
+// accepting unsigned ones; call SetVerifier(nil) to opt out of verification.
+func NewReceiver(secret string) *Receiver {
+	return &Receiver{logger: slog.Default(), verifier: HookSignature(secret)}
+}
+
+// SetVerifier replaces the signature check, for example with
+// LegacySignature. A nil verifier disables verification, which is only safe
+// when something else, such as a proxy, authenticates the requests.
+func (r *Receiver) SetVerifier(verifier Verifier) {
+	r.verifier = verifier
+}
+
+// SetLogger sets the logger used to report rejected requests and handler errors
+func (r *Receiver) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	r.logger = logger
+}
+
+// SetSequencer routes events through sequencer before the handlers, so
+// duplicate and stale events are dropped. A nil sequencer disables it.
+func (r *Receiver) SetSequencer(sequencer *Sequencer) {
+	r.sequencer = sequencer
+}
+
+// SetEnricher fetches the full entity for each event before the handlers
+// run. A nil enricher disables it.
+func (r *Receiver) SetEnricher(enricher *Enricher) {
+	r.enricher = enricher
+}
+
+// CacheInvalidator drops cached API responses for an entity. The
+// autotask.Client implements it.
+type CacheInvalidator interface {
+	InvalidateCache(entityName string, id int64)
+}
+
+// SetCacheInvalidator drops the cached responses for each event's entity
+// before it is processed, so handlers and the enricher don't read a stale
+// copy. Pass the client whose cache is enabled with SetCache. A nil
+// invalidator disables it.
+func (r *Receiver) SetCacheInvalidator(invalidator CacheInvalidator) {
+	r.invalidator = invalidator
+}
+
+// Handle registers a handler for events with the given entity type and
+// action. Either may be Wildcard to match anything. Matching ignores case,
+// and handlers run in the order they were registered.
+func (r *Receiver) Handle(entityType string, action Action, handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.registrations = append(r.registrations, registration{route: newRoute(entityType, action), handler: handler})
+}
+
+// HandleAll registers a handler for every event
+func (r *Receiver) HandleAll(handler Handler) {
+	r.Handle(Wildcard, Wildcard, handler)
+}
+
+// Use appends middleware applied around the processing of every event. The
+// first middleware is the outermost and sees the event first.
+func (r *Receiver) Use(middleware ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// Dispatch calls every handler registered for the event and returns their
+// errors joined together. With a sequencer set, duplicate and stale events
+// are dropped first; with an enricher set, the entity is fetched for events
+// that have handlers. Middleware wraps all of it.
+func (r *Receiver) Dispatch(ctx context.Context, event *Event) error {
+	r.mu.RLock()
+	middleware := r.middleware
+	r.mu.RUnlock()
+
+	return chain(r.process, middleware...)(ctx, event)
+}
+
+// process sequences and enriches an event and calls its handlers
+func (r *Receiver) process(ctx context.Context, event *Event) error {
+	r.invalidate(event)
+	if r.sequencer != nil {
+		return r.sequencer.Process(ctx, event, r.enrichAndCall)
+	}
+	return r.enrichAndCall(ctx, event)
+}
+
+// invalidate drops the cached responses for the event's entity
+func (r *Receiver) invalidate(event *Event) {
+	if r.invalidator == nil || event.EntityID == 0 {
+		return
+	}
+	if typ, ok := entityTypes[strings.ToLower(event.EntityType)]; ok {
+		r.invalidator.InvalidateCache(typ.entityName, event.EntityID)
+	}
+}
+
+// enrichAndCall enriches the event when needed and calls its handlers
+func (r *Receiver) enrichAndCall(ctx context.Context, event *Event) error {
+	if r.enricher != nil && r.hasHandlers(event) {
+		return r.enricher.Process(ctx, event, r.callHandlers)
+	}
+	return r.callHandlers(ctx, event)
+}
+
+// hasHandlers reports whether any handler is registered for the event
+func (r *Receiver) hasHandlers(event *Event) bool {
+	return len(r.handlersFor(event)) > 0
+}
+
+// handlersFor returns the handlers whose routes match the event
+func (r *Receiver) handlersFor(event *Event) []Handler {
+	key := newRoute(event.EntityType, event.Action)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var handlers []Handler
+	for _, reg := range r.registrations {
+		if reg.route.matches(key) {
+			handlers = append(handlers, reg.handler)
+		}
+	}
+	return handlers
+}
+
+// callHandlers calls the handlers registered for the event
+func (r *Receiver) callHandlers(ctx context.Context, event *Event) error {
+	handlers := r.handlersFor(event)
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ServeHTTP verifies and dispatches a webhook callback. It responds 401 when
+// the signature doesn't match, 400 when the payload can't be parsed and 500
+// when a handler fails, so that Autotask delivers the event again.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	event, err := r.readEvent(w, req)
+	if err != nil {
+		return
+	}
+
+	if err := r.Dispatch(req.Context(), event); err != nil {
+		r.logger.ErrorContext(req.Context(), "Webhook handler error",
+			"entity_type", event.EntityType,
+			"action", event.Action,
+			"entity_id", event.EntityID,
+			"guid", event.GUID,
+			"error", err,
+		)
+		http.Error(w, "Webhook handler failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// readEvent reads, verifies and parses a webhook request, writing an error
+// response when it fails
+func (r *Receiver) readEvent(w http.ResponseWriter, req *http.Request) (*Event, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, MaxBodyBytes))
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return nil, err
+	}
+
+	if r.verifier != nil {
+		if err := r.verifier.Verify(req, body); err != nil {
+			r.logger.WarnContext(req.Context(), "Rejected webhook", "error", err)
+			http.Error(w, "Invalid signature", http.StatusUnauthorized)
+			return nil, err
+		}
+	}
+
+	event, err := ParseEvent(body)
+	if err != nil {
+		r.logger.WarnContext(req.Context(), "Malformed webhook", "error", err)
+		http.Error(w, "Failed to parse webhook event", http.StatusBadRequest)
+		return nil, err
+	}
+	return event, nil
+}
@@ -0,0 +1,194 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+// samplePayload is a ticket update as posted by Autotask
+const samplePayload = `{
+	"Action": "Update",
+	"Guid": "7c1b4a9e-2f7d-4d1e-9a0b-6b3c2d1e0f9a",
+	"EntityType": "Ticket",
+	"Id": 12345,
+	"Fields": {"Status": 8, "Title": "Printer offline", "LastActivityDate": "2025-03-01T10:15:00Z"},
+	"EventTime": "2025-03-01T10:15:02.1234567Z",
+	"SequenceNumber": 4,
+	"PersonID": 29682885
+}`
+
+func TestParseEvent(t *testing.T) {
+	event, err := ParseEvent([]byte(samplePayload))
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertTrue(t, event.Is(EntityTicket, ActionUpdate), "event should be a ticket update")
+	autotask.AssertEqual(t, int64(12345), event.EntityID, "entity ID should match")
+	autotask.AssertEqual(t, int64(4), event.SequenceNumber, "sequence number should match")
+	autotask.AssertEqual(t, int64(29682885), event.PersonID, "person ID should match")
+	autotask.AssertEqual(t, 2025, event.EventTime.Year(), "event time should be parsed")
+	autotask.AssertEqual(t, "LastActivityDate,Status,Title", strings.Join(event.FieldNames(), ","), "field names should be sorted")
+
+	var status int
+	ok, err := event.Field("status", &status)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertTrue(t, ok, "field should be found ignoring case")
+	autotask.AssertEqual(t, 8, status, "field value should match")
+
+	var ticket autotask.Ticket
+	autotask.AssertNil(t, event.Decode(&ticket), "decode should succeed")
+	autotask.AssertEqual(t, int64(12345), ticket.ID, "ID should be decoded")
+	autotask.AssertEqual(t, "Printer offline", ticket.Title, "fields should be decoded")
+
+	_, err = ParseEvent([]byte(`{"Id": 1}`))
+	autotask.AssertNotNil(t, err, "payload without entity type should be rejected")
+}
+
+func TestHookSignature(t *testing.T) {
+	body := []byte(samplePayload)
+	req := httptest.NewRequest(http.MethodPost, "/webhook", nil)
+
+	verifier := HookSignature("secret")
+	autotask.AssertTrue(t, errors.Is(verifier.Verify(req, body), ErrMissingSignature), "missing signature should be rejected")
+
+	req.Header.Set(HookSignatureHeader, SignatureHeader("secret", body))
+	autotask.AssertNil(t, verifier.Verify(req, body), "valid signature should be accepted")
+
+	req.Header.Set(HookSignatureHeader, strings.TrimPrefix(SignatureHeader("secret", body), "sha1="))
+	autotask.AssertNil(t, verifier.Verify(req, body), "signature without prefix should be accepted")
+
+	req.Header.Set(HookSignatureHeader, SignatureHeader("other", body))
+	autotask.AssertTrue(t, errors.Is(verifier.Verify(req, body), ErrInvalidSignature), "wrong key should be rejected")
+}
+
+func TestReceiver(t *testing.T) {
+	receiver := NewReceiver("secret")
+
+	var got *Event
+	receiver.Handle("ticket", ActionUpdate, func(ctx context.Context, event *Event) error {
+		got = event
+		return nil
+	})
+	receiver.Handle(EntityTicket, ActionCreate, func(ctx context.Context, event *Event) error {
+		t.Error("create handler should not be called for an update")
+		return nil
+	})
+
+	send := func(body, signature string) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		req.Header.Set(HookSignatureHeader, signature)
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	autotask.AssertEqual(t, http.StatusOK, send(samplePayload, SignatureHeader("secret", []byte(samplePayload))), "signed request should be accepted")
+	autotask.AssertNotNil(t, got, "handler should be called")
+	autotask.AssertEqual(t, time.Date(2025, 3, 1, 10, 15, 2, 123456700, time.UTC), got.EventTime, "event should be parsed")
+
+	autotask.AssertEqual(t, http.StatusUnauthorized, send(samplePayload, "sha1=bm9wZQ=="), "bad signature should be rejected")
+	autotask.AssertEqual(t, http.StatusBadRequest, send("not json", SignatureHeader("secret", []byte("not json"))), "malformed payload should be rejected")
+
+	receiver.Handle(EntityTicket, ActionUpdate, func(ctx context.Context, event *Event) error {
+		return errors.New("downstream unavailable")
+	})
+	autotask.AssertEqual(t, http.StatusInternalServerError, send(samplePayload, SignatureHeader("secret", []byte(samplePayload))), "handler failure should be reported")
+}
+
+func TestReceiverEmptySecret(t *testing.T) {
+	receiver := NewReceiver("")
+
+	called := false
+	receiver.HandleAll(func(ctx context.Context, event *Event) error {
+		called = true
+		return nil
+	})
+
+	send := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(samplePayload))
+		req.Header.Set(HookSignatureHeader, SignatureHeader("", []byte(samplePayload)))
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	autotask.AssertEqual(t, http.StatusUnauthorized, send(), "empty secret should reject requests")
+	autotask.AssertFalse(t, called, "handler should not be called")
+
+	receiver.SetVerifier(nil)
+	autotask.AssertEqual(t, http.StatusOK, send(), "disabled verification should accept requests")
+	autotask.AssertTrue(t, called, "handler should be called")
+}
+
+func TestReceiverLegacySignature(t *testing.T) {
+	receiver := NewReceiver("")
+	receiver.SetVerifier(LegacySignature("secret"))
+
+	called := false
+	receiver.Handle(EntityTicket, ActionUpdate, func(ctx context.Context, event *Event) error {
+		called = true
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(samplePayload))
+	req.Header.Set(LegacySignatureHeader, "3f")
+	rec := httptest.NewRecorder()
+	receiver.ServeHTTP(rec, req)
+
+	autotask.AssertEqual(t, http.StatusUnauthorized, rec.Code, "wrong legacy signature should be rejected")
+	autotask.AssertFalse(t, called, "handler should not be called")
+}
+
+func TestReceiverWildcards(t *testing.T) {
+	receiver := NewReceiver("")
+
+	var calls []string
+	receiver.Handle(EntityTicket, ActionUpdate, func(ctx context.Context, event *Event) error {
+		calls = append(calls, "exact")
+		return nil
+	})
+	receiver.Handle(EntityTicket, Wildcard, func(ctx context.Context, event *Event) error {
+		calls = append(calls, "ticket")
+		return nil
+	})
+	receiver.Handle(Wildcard, ActionCreate, func(ctx context.Context, event *Event) error {
+		calls = append(calls, "create")
+		return nil
+	})
+	receiver.HandleAll(func(ctx context.Context, event *Event) error {
+		calls = append(calls, "all")
+		return nil
+	})
+
+	err := receiver.Dispatch(context.Background(), &Event{Action: ActionUpdate, EntityType: "ticket", EntityID: 1})
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, "exact,ticket,all", strings.Join(calls, ","), "matching handlers should run in registration order")
+
+	calls = nil
+	err = receiver.Dispatch(context.Background(), &Event{Action: ActionCreate, EntityType: EntityCompany, EntityID: 1})
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, "create,all", strings.Join(calls, ","), "wildcard handlers should match other entities")
+}
+
+// invalidations records the entities a receiver invalidates
+type invalidations []string
+
+func (i *invalidations) InvalidateCache(entityName string, id int64) {
+	*i = append(*i, fmt.Sprintf("%s %d", entityName, id))
+}
+
+func TestReceiverCacheInvalidator(t *testing.T) {
+	receiver := NewReceiver("")
+	var got invalidations
+	receiver.SetCacheInvalidator(&got)
+
+	err := receiver.Dispatch(context.Background(), &Event{Action: ActionUpdate, EntityType: EntityTicket, EntityID: 7})
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, "Tickets 7", strings.Join(got, ","), "event should invalidate the entity")
+}
@@ -0,0 +1,259 @@
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// recordedHeaders are the request headers kept in a Recording
+var recordedHeaders = []string{"Content-Type", HookSignatureHeader, LegacySignatureHeader}
+
+// Recording is a webhook request captured by a Recorder. Recordings are
+// stored one per line as JSON. The body is kept byte for byte, base64
+// encoded, so recorded signatures still match it when replayed.
+type Recording struct {
+	ReceivedAt time.Time         `json:"receivedAt"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       []byte            `json:"body"`
+}
+
+// Recorder is an http.Handler that writes each webhook request it receives
+// to a JSONL stream before passing it on to next. With a nil next it
+// responds 200.
+type Recorder struct {
+	next http.Handler
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewRecorder returns a recorder writing to out
+func NewRecorder(out io.Writer, next http.Handler) *Recorder {
+	return &Recorder{out: out, next: next}
+}
+
+// ServeHTTP records the request and passes it on
+func (rec *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, MaxBodyBytes))
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	if json.Valid(body) {
+		recording := Recording{ReceivedAt: time.Now().UTC(), Headers: make(map[string]string), Body: body}
+		for _, name := range recordedHeaders {
+			if value := req.Header.Get(name); value != "" {
+				recording.Headers[name] = value
+			}
+		}
+		if err := rec.write(recording); err != nil {
+			http.Error(w, "Failed to record webhook", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if rec.next == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	rec.next.ServeHTTP(w, req)
+}
+
+// write appends a recording to the output as one line
+func (rec *Recorder) write(recording Recording) error {
+	line, err := json.Marshal(recording)
+	if err != nil {
+		return fmt.Errorf("failed to marshal recording: %w", err)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if _, err := rec.out.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
+
+// ReadRecordings reads JSONL recordings written by a Recorder, skipping blank lines
+func ReadRecordings(r io.Reader) ([]Recording, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), MaxBodyBytes*2)
+
+	var recordings []Recording
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var recording Recording
+		if err := json.Unmarshal(text, &recording); err != nil {
+			return nil, fmt.Errorf("failed to parse recording on line %d: %w", line, err)
+		}
+		recordings = append(recordings, recording)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recordings: %w", err)
+	}
+	return recordings, nil
+}
+
+// Sender delivers webhook bodies to a URL or an http.Handler, signing them
+// like Autotask does, so handlers can be exercised without a live tenant
+type Sender struct {
+	url        string
+	handler    http.Handler
+	httpClient *http.Client
+	secret     string
+}
+
+// NewSender returns a sender that POSTs to url
+func NewSender(url string) *Sender {
+	return &Sender{url: url, httpClient: http.DefaultClient}
+}
+
+// NewHandlerSender returns a sender that calls handler directly
+func NewHandlerSender(handler http.Handler) *Sender {
+	return &Sender{handler: handler}
+}
+
+// SetSecret sets the secret used to sign bodies with the X-Hook-Signature
+// header. Without one, bodies are sent unsigned and recordings keep their
+// original signature headers.
+func (s *Sender) SetSecret(secret string) {
+	s.secret = secret
+}
+
+// SetHTTPClient sets the HTTP client used to POST to the URL
+func (s *Sender) SetHTTPClient(httpClient *http.Client) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	s.httpClient = httpClient
+}
+
+// Send delivers a webhook body and returns the response status code
+func (s *Sender) Send(ctx context.Context, body []byte) (int, error) {
+	return s.SendRecording(ctx, Recording{Body: body})
+}
+
+// SendRecording delivers a recorded webhook and returns the response status code
+func (s *Sender) SendRecording(ctx context.Context, recording Recording) (int, error) {
+	header := make(http.Header)
+	for name, value := range recording.Headers {
+		header.Set(name, value)
+	}
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "application/json")
+	}
+	if s.secret != "" {
+		header.Del(LegacySignatureHeader)
+		header.Set(HookSignatureHeader, SignatureHeader(s.secret, recording.Body))
+	}
+
+	if s.handler != nil {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(recording.Body)).WithContext(ctx)
+		req.Header = header
+		rec := httptest.NewRecorder()
+		s.handler.ServeHTTP(rec, req)
+		return rec.Code, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(recording.Body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header = header
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// ReplayResult is the outcome of sending one recording
+type ReplayResult struct {
+	Recording  Recording
+	StatusCode int
+	Err        error
+}
+
+// Replay sends recordings in order. With a speed above zero it waits between
+// them for the time that passed between the original requests divided by
+// speed, so 1 replays in real time and 10 ten times faster; zero sends them
+// back to back. It stops early only when ctx is done.
+func (s *Sender) Replay(ctx context.Context, recordings []Recording, speed float64) ([]ReplayResult, error) {
+	results := make([]ReplayResult, 0, len(recordings))
+	for i, recording := range recordings {
+		if i > 0 && speed > 0 {
+			gap := recording.ReceivedAt.Sub(recordings[i-1].ReceivedAt)
+			if gap > 0 {
+				select {
+				case <-ctx.Done():
+					return results, ctx.Err()
+				case <-time.After(time.Duration(float64(gap) / speed)):
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		status, err := s.SendRecording(ctx, recording)
+		results = append(results, ReplayResult{Recording: recording, StatusCode: status, Err: err})
+	}
+	return results, nil
+}
+
+// sampleFields are the fields included in sample payloads for each entity type
+var sampleFields = map[string]map[string]interface{}{
+	strings.ToLower(EntityCompany):           {"CompanyName": "Sample Company", "CompanyType": 1, "IsActive": true},
+	strings.ToLower(EntityContact):           {"FirstName": "Sam", "LastName": "Sample", "EmailAddress": "sam@example.com", "IsActive": 1},
+	strings.ToLower(EntityTicket):            {"Title": "Sample ticket", "Status": 1, "Priority": 2, "QueueID": 5},
+	strings.ToLower(EntityTicketNote):        {"TicketID": 1, "Title": "Sample note", "Description": "Sample note text", "NoteType": 1, "Publish": 1},
+	strings.ToLower(EntityConfigurationItem): {"ReferenceTitle": "Sample device", "SerialNumber": "SN-0001", "IsActive": true},
+}
+
+// SamplePayload returns a webhook body in Autotask's format for an entity
+// type and action, with plausible fields for the entity types this package
+// names. Delete payloads carry no fields.
+func SamplePayload(entityType string, action Action, id int64) ([]byte, error) {
+	fields := map[string]interface{}{}
+	if action != ActionDelete {
+		if sample, ok := sampleFields[strings.ToLower(entityType)]; ok {
+			fields = sample
+		}
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"Action":         action,
+		"Guid":           newGUID(),
+		"EntityType":     entityType,
+		"Id":             id,
+		"Fields":         fields,
+		"EventTime":      time.Now().UTC().Format(time.RFC3339Nano),
+		"SequenceNumber": 1,
+		"PersonID":       0,
+	})
+}
+
+// newGUID returns a random GUID in the format Autotask uses
+func newGUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := NewRecorder(&buf, nil)
+
+	// Record two webhooks signed with the original secret
+	for _, action := range []Action{ActionCreate, ActionUpdate} {
+		body, err := SamplePayload(EntityTicket, action, 42)
+		autotask.AssertNil(t, err, "error should be nil")
+		autotask.AssertEqual(t, http.StatusOK, post(recorder, "original", string(body)), "recorder should accept the webhook")
+	}
+
+	recordings, err := ReadRecordings(&buf)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, 2, len(recordings), "both webhooks should be recorded")
+	autotask.AssertTrue(t, recordings[0].Headers[HookSignatureHeader] != "", "signature header should be recorded")
+
+	receiver := NewReceiver("original")
+	var actions []Action
+	receiver.OnTicketCreated(func(ctx context.Context, event *TicketEvent) error {
+		actions = append(actions, event.Action)
+		autotask.AssertEqual(t, "Sample ticket", event.Ticket.Title, "sample fields should decode")
+		return nil
+	})
+	receiver.OnTicketUpdated(func(ctx context.Context, event *TicketEvent) error {
+		actions = append(actions, event.Action)
+		return nil
+	})
+
+	// Recorded signatures are replayed as they were
+	results, err := NewHandlerSender(receiver).Replay(context.Background(), recordings, 0)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, 2, len(results), "every recording should be replayed")
+	autotask.AssertEqual(t, http.StatusOK, results[1].StatusCode, "replayed webhook should verify")
+	autotask.AssertEqual(t, 2, len(actions), "handlers should run for each recording")
+	autotask.AssertEqual(t, ActionCreate, actions[0], "recordings should replay in order")
+
+	// A different secret re-signs the bodies
+	resigned := NewHandlerSender(NewReceiver("other"))
+	resigned.SetSecret("other")
+	results, err = resigned.Replay(context.Background(), recordings, 0)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, http.StatusOK, results[0].StatusCode, "re-signed webhook should verify")
+}
+
+func TestRecordKeepsBodyBytes(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := NewRecorder(&buf, nil)
+
+	// Pretty-printed HTML is changed by compacting or escaping the JSON
+	body := `{
+  "Action": "Update",
+  "Guid": "7c1b4a9e-2f7d-4d1e-9a0b-6b3c2d1e0f9a",
+  "EntityType": "TicketNote",
+  "Id": 42,
+  "Fields": {"Description": "<p>Printer & scanner</p>"}
+}`
+	autotask.AssertEqual(t, http.StatusOK, post(recorder, "secret", body), "recorder should accept the webhook")
+
+	recordings, err := ReadRecordings(&buf)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, body, string(recordings[0].Body), "body should be recorded byte for byte")
+
+	receiver := NewReceiver("secret")
+	var description string
+	receiver.HandleAll(func(ctx context.Context, event *Event) error {
+		_, err := event.Field("Description", &description)
+		return err
+	})
+
+	results, err := NewHandlerSender(receiver).Replay(context.Background(), recordings, 0)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, http.StatusOK, results[0].StatusCode, "recorded signature should verify")
+	autotask.AssertEqual(t, "<p>Printer & scanner</p>", description, "handler should receive the event")
+}
+
+func TestReplaySpeed(t *testing.T) {
+	start := time.Now()
+	recordings := []Recording{
+		{ReceivedAt: start, Body: []byte(`{}`)},
+		{ReceivedAt: start.Add(200 * time.Millisecond), Body: []byte(`{}`)},
+	}
+
+	var count int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { count++ })
+
+	began := time.Now()
+	_, err := NewHandlerSender(handler).Replay(context.Background(), recordings, 10)
+	autotask.AssertNil(t, err, "error should be nil")
+	elapsed := time.Since(began)
+	autotask.AssertTrue(t, elapsed >= 20*time.Millisecond && elapsed < 150*time.Millisecond, "gap should be scaled by speed")
+	autotask.AssertEqual(t, 2, count, "both recordings should be sent")
+}
+
+func TestSenderPostsToURL(t *testing.T) {
+	receiver := NewReceiver("secret")
+	var got int64
+	receiver.HandleAll(func(ctx context.Context, event *Event) error {
+		got = event.EntityID
+		return nil
+	})
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	body, err := SamplePayload(EntityCompany, ActionUpdate, 7)
+	autotask.AssertNil(t, err, "error should be nil")
+
+	sender := NewSender(server.URL)
+	sender.SetSecret("secret")
+	status, err := sender.Send(context.Background(), body)
+	autotask.AssertNil(t, err, "error should be nil")
+	autotask.AssertEqual(t, http.StatusOK, status, "signed webhook should be accepted")
+	autotask.AssertEqual(t, int64(7), got, "handler should receive the event")
+}
+
+func TestSamplePayloadsDecode(t *testing.T) {
+	receiver := NewReceiver("")
+	receiver.OnCompanyUpdated(func(ctx context.Context, event *CompanyEvent) error { return nil })
+	receiver.OnContactUpdated(func(ctx context.Context, event *ContactEvent) error { return nil })
+	receiver.OnTicketUpdated(func(ctx context.Context, event *TicketEvent) error { return nil })
+	receiver.OnTicketNoteUpdated(func(ctx context.Context, event *TicketNoteEvent) error { return nil })
+	receiver.OnConfigurationItemUpdated(func(ctx context.Context, event *ConfigurationItemEvent) error { return nil })
+
+	for _, entityType := range []string{EntityCompany, EntityContact, EntityTicket, EntityTicketNote, EntityConfigurationItem} {
+		body, err := SamplePayload(entityType, ActionUpdate, 1)
+		autotask.AssertNil(t, err, "error should be nil")
+		event, err := ParseEvent(body)
+		autotask.AssertNil(t, err, "sample payload should parse")
+		autotask.AssertNil(t, receiver.Dispatch(context.Background(), event), entityType+" sample should decode into its type")
+	}
+}
@@ -0,0 +1,247 @@
+package webhook
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSequencerWindow is how long a Sequencer remembers event GUIDs and
+// sequence numbers
+const DefaultSequencerWindow = time.Hour
+
+// Sequencer drops duplicate and stale webhook events and can deliver each
+// entity's events in order.
+//
+// An event is a duplicate when an event with the same GUID was delivered
+// within the window, and stale when a newer SequenceNumber for the same
+// entity has already been delivered. Both are dropped without calling the
+// handler. Events for the same entity are never delivered concurrently.
+//
+// With a buffer set, events for an entity are held briefly so that ones
+// arriving out of order can be sorted by SequenceNumber before delivery.
+type Sequencer struct {
+	window time.Duration
+	buffer time.Duration
+
+	mu         sync.Mutex
+	seen       map[string]time.Time
+	sequences  map[string]sequenceEntry
+	locks      map[string]*entityLock
+	pending    map[string]*pendingBatch
+	lastPruned time.Time
+}
+
+// sequenceEntry is the latest sequence number delivered for an entity
+type sequenceEntry struct {
+	number int64
+	at     time.Time
+}
+
+// entityLock serializes delivery for one entity
+type entityLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// pendingBatch holds buffered events for one entity
+type pendingBatch struct {
+	events []*pendingEvent
+}
+
+// pendingEvent is a buffered event waiting for delivery
+type pendingEvent struct {
+	ctx    context.Context
+	event  *Event
+	result chan error
+}
+
+// NewSequencer returns a sequencer with the default window and no buffering
+func NewSequencer() *Sequencer {
+	return &Sequencer{
+		window:    DefaultSequencerWindow,
+		seen:      make(map[string]time.Time),
+		sequences: make(map[string]sequenceEntry),
+		locks:     make(map[string]*entityLock),
+		pending:   make(map[string]*pendingBatch),
+	}
+}
+
+// SetWindow sets how long GUIDs and sequence numbers are remembered
+func (s *Sequencer) SetWindow(window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.window = window
+}
+
+// SetBuffer sets how long events are held to restore per-entity order.
+// Zero, the default, delivers events as they arrive.
+func (s *Sequencer) SetBuffer(buffer time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buffer = buffer
+}
+
+// Process delivers event to next unless it is a duplicate or stale. When
+// buffering is enabled it waits for the event's batch to be delivered and
+// returns the handler's result.
+func (s *Sequencer) Process(ctx context.Context, event *Event, next Handler) error {
+	key := entityKey(event)
+
+	s.mu.Lock()
+	s.prune()
+	if s.isDuplicate(event) || s.isStale(key, event) {
+		s.mu.Unlock()
+		return nil
+	}
+	if event.GUID != "" {
+		// Claim the GUID so concurrent duplicates are dropped
+		s.seen[event.GUID] = time.Now()
+	}
+
+	if s.buffer <= 0 || event.SequenceNumber == 0 {
+		s.mu.Unlock()
+		return s.deliver(ctx, key, event, next)
+	}
+
+	pending := &pendingEvent{ctx: ctx, event: event, result: make(chan error, 1)}
+	batch, ok := s.pending[key]
+	if !ok {
+		batch = &pendingBatch{}
+		s.pending[key] = batch
+		time.AfterFunc(s.buffer, func() { s.flush(key, next) })
+	}
+	batch.events = append(batch.events, pending)
+	s.mu.Unlock()
+
+	select {
+	case err := <-pending.result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flush delivers a buffered batch in sequence order
+func (s *Sequencer) flush(key string, next Handler) {
+	s.mu.Lock()
+	batch := s.pending[key]
+	delete(s.pending, key)
+	s.mu.Unlock()
+
+	if batch == nil {
+		return
+	}
+	sort.SliceStable(batch.events, func(i, j int) bool {
+		return batch.events[i].event.SequenceNumber < batch.events[j].event.SequenceNumber
+	})
+	for _, pending := range batch.events {
+		pending.result <- s.deliver(pending.ctx, key, pending.event, next)
+	}
+}
+
+// deliver calls next for an event while holding its entity's lock, and
+// records the event as delivered when next succeeds
+func (s *Sequencer) deliver(ctx context.Context, key string, event *Event, next Handler) error {
+	lock := s.lockEntity(key)
+	defer s.unlockEntity(key, lock)
+
+	// Another event for the entity may have been delivered while we waited
+	s.mu.Lock()
+	stale := s.isStale(key, event)
+	s.mu.Unlock()
+	if stale {
+		return nil
+	}
+
+	err := next(ctx, event)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		// Let Autotask's redelivery of this event through
+		delete(s.seen, event.GUID)
+		return err
+	}
+	if event.SequenceNumber > 0 && key != "" {
+		s.sequences[key] = sequenceEntry{number: event.SequenceNumber, at: time.Now()}
+	}
+	return nil
+}
+
+// isDuplicate reports whether an event with the same GUID was seen within the window
+func (s *Sequencer) isDuplicate(event *Event) bool {
+	if event.GUID == "" {
+		return false
+	}
+	at, ok := s.seen[event.GUID]
+	return ok && time.Since(at) < s.window
+}
+
+// isStale reports whether a newer event for the same entity was delivered
+func (s *Sequencer) isStale(key string, event *Event) bool {
+	if key == "" || event.SequenceNumber == 0 {
+		return false
+	}
+	latest, ok := s.sequences[key]
+	return ok && time.Since(latest.at) < s.window && event.SequenceNumber <= latest.number
+}
+
+// lockEntity acquires the delivery lock for an entity
+func (s *Sequencer) lockEntity(key string) *entityLock {
+	s.mu.Lock()
+	lock, ok := s.locks[key]
+	if !ok {
+		lock = &entityLock{}
+		s.locks[key] = lock
+	}
+	lock.refs++
+	s.mu.Unlock()
+
+	lock.mu.Lock()
+	return lock
+}
+
+// unlockEntity releases the delivery lock for an entity
+func (s *Sequencer) unlockEntity(key string, lock *entityLock) {
+	lock.mu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lock.refs--
+	if lock.refs == 0 {
+		delete(s.locks, key)
+	}
+}
+
+// prune forgets GUIDs and sequence numbers older than the window. It runs
+// at most once per half window and must be called with s.mu held.
+func (s *Sequencer) prune() {
+	now := time.Now()
+	if now.Sub(s.lastPruned) < s.window/2 {
+		return
+	}
+	s.lastPruned = now
+
+	for guid, at := range s.seen {
+		if now.Sub(at) >= s.window {
+			delete(s.seen, guid)
+		}
+	}
+	for key, entry := range s.sequences {
+		if now.Sub(entry.at) >= s.window {
+			delete(s.sequences, key)
+		}
+	}
+}
+
+// entityKey identifies the entity an event is about
+func entityKey(event *Event) string {
+	if event.EntityID == 0 {
+		return ""
+	}
+	return strings.ToLower(event.EntityType) + ":" + strconv.FormatInt(event.EntityID, 10)
+}
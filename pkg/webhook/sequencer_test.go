@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+// sequencedEvent returns a ticket update event for tests
+func sequencedEvent(guid string, id, sequence int64) *Event {
+	return &Event{Action: ActionUpdate, GUID: guid, EntityType: EntityTicket, EntityID: id, SequenceNumber: sequence}
+}
+
+func TestSequencerDropsDuplicatesAndStale(t *testing.T) {
+	sequencer := NewSequencer()
+	ctx := context.Background()
+
+	var delivered []int64
+	handler := func(ctx context.Context, event *Event) error {
+		delivered = append(delivered, event.SequenceNumber)
+		return nil
+	}
+
+	autotask.AssertNil(t, sequencer.Process(ctx, sequencedEvent("a", 1, 2), handler), "error should be nil")
+	autotask.AssertNil(t, sequencer.Process(ctx, sequencedEvent("a", 1, 2), handler), "duplicate should be dropped")
+	autotask.AssertNil(t, sequencer.Process(ctx, sequencedEvent("b", 1, 1), handler), "stale event should be dropped")
+	autotask.AssertNil(t, sequencer.Process(ctx, sequencedEvent("c", 2, 1), handler), "other entity should be delivered")
+	autotask.AssertNil(t, sequencer.Process(ctx, sequencedEvent("d", 1, 3), handler), "newer event should be delivered")
+
+	autotask.AssertEqual(t, 3, len(delivered), "three events should be delivered")
+	autotask.AssertEqual(t, int64(3), delivered[2], "newest event should be delivered last")
+}
+
+func TestSequencerRedeliversFailedEvent(t *testing.T) {
+	sequencer := NewSequencer()
+	ctx := context.Background()
+
+	calls := 0
+	handler := func(ctx context.Context, event *Event) error {
+		calls++
+		if calls == 1 {
+			return errors.New("temporarily unavailable")
+		}
+		return nil
+	}
+
+	err := sequencer.Process(ctx, sequencedEvent("a", 1, 1), handler)
+	autotask.AssertNotNil(t, err, "handler error should be returned")
+	autotask.AssertNil(t, sequencer.Process(ctx, sequencedEvent("a", 1, 1), handler), "retry should be delivered")
+	autotask.AssertEqual(t, 2, calls, "failed event should not count as seen")
+}
+
+func TestSequencerWindowExpires(t *testing.T) {
+	sequencer := NewSequencer()
+	sequencer.SetWindow(10 * time.Millisecond)
+	ctx := context.Background()
+
+	calls := 0
+	handler := func(ctx context.Context, event *Event) error {
+		calls++
+		return nil
+	}
+
+	autotask.AssertNil(t, sequencer.Process(ctx, sequencedEvent("a", 1, 1), handler), "error should be nil")
+	time.Sleep(20 * time.Millisecond)
+	autotask.AssertNil(t, sequencer.Process(ctx, sequencedEvent("a", 1, 1), handler), "error should be nil")
+	autotask.AssertEqual(t, 2, calls, "GUID should be forgotten after the window")
+}
+
+func TestSequencerBufferOrdersEvents(t *testing.T) {
+	sequencer := NewSequencer()
+	sequencer.SetBuffer(50 * time.Millisecond)
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var delivered []int64
+	handler := func(ctx context.Context, event *Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		delivered = append(delivered, event.SequenceNumber)
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for _, n := range []int64{3, 1, 2} {
+		wg.Add(1)
+		go func(n int64) {
+			defer wg.Done()
+			if err := sequencer.Process(ctx, sequencedEvent(string(rune('a'+n)), 1, n), handler); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(n)
+		time.Sleep(5 * time.Millisecond)
+	}
+	wg.Wait()
+
+	autotask.AssertEqual(t, 3, len(delivered), "all buffered events should be delivered")
+	for i, n := range delivered {
+		autotask.AssertEqual(t, int64(i+1), n, "events should be delivered in sequence order")
+	}
+}
+
+func TestReceiverSequencer(t *testing.T) {
+	receiver := NewReceiver("secret")
+	receiver.SetSequencer(NewSequencer())
+
+	calls := 0
+	receiver.Handle(EntityTicket, ActionUpdate, func(ctx context.Context, event *Event) error {
+		calls++
+		return nil
+	})
+
+	autotask.AssertEqual(t, http.StatusOK, post(receiver, "secret", samplePayload), "webhook should be accepted")
+	autotask.AssertEqual(t, http.StatusOK, post(receiver, "secret", samplePayload), "duplicate should be accepted")
+	autotask.AssertEqual(t, 1, calls, "duplicate should not reach the handler")
+}
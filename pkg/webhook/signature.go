@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Signature headers
+const (
+	// HookSignatureHeader carries Autotask's base64 HMAC-SHA1 signature
+	HookSignatureHeader = "X-Hook-Signature"
+
+	// LegacySignatureHeader carries the hex HMAC-SHA256 signature used by
+	// autotask.WebhookService
+	LegacySignatureHeader = "X-Autotask-Signature"
+)
+
+// Signature verification errors
+var (
+	ErrMissingSignature = errors.New("missing webhook signature")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrNoSecret         = errors.New("no webhook secret configured")
+)
+
+// Verifier checks that a webhook request was sent by Autotask
+type Verifier interface {
+	Verify(r *http.Request, body []byte) error
+}
+
+// VerifierFunc adapts a function to the Verifier interface
+type VerifierFunc func(r *http.Request, body []byte) error
+
+// Verify calls f(r, body)
+func (f VerifierFunc) Verify(r *http.Request, body []byte) error {
+	return f(r, body)
+}
+
+// HookSignature returns a Verifier for the X-Hook-Signature header Autotask
+// sends: a base64 HMAC-SHA1 of the raw body keyed with the webhook's secret
+// key, optionally prefixed with "sha1=". With an empty secret, which anyone
+// could sign with, every request is rejected with ErrNoSecret.
+func HookSignature(secret string) Verifier {
+	return VerifierFunc(func(r *http.Request, body []byte) error {
+		if secret == "" {
+			return ErrNoSecret
+		}
+		signature := r.Header.Get(HookSignatureHeader)
+		if signature == "" {
+			return ErrMissingSignature
+		}
+		signature = strings.TrimPrefix(signature, "sha1=")
+
+		provided, err := base64.StdEncoding.DecodeString(signature)
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		if !hmac.Equal(provided, Sign(secret, body)) {
+			return ErrInvalidSignature
+		}
+		return nil
+	})
+}
+
+// LegacySignature returns a Verifier for the hex HMAC-SHA256
+// X-Autotask-Signature header checked by autotask.WebhookService. Like
+// HookSignature, it rejects every request when the secret is empty.
+func LegacySignature(secret string) Verifier {
+	return VerifierFunc(func(r *http.Request, body []byte) error {
+		if secret == "" {
+			return ErrNoSecret
+		}
+		signature := r.Header.Get(LegacySignatureHeader)
+		if signature == "" {
+			return ErrMissingSignature
+		}
+
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		expected := hex.EncodeToString(mac.Sum(nil))
+		if !hmac.Equal([]byte(strings.ToLower(signature)), []byte(expected)) {
+			return ErrInvalidSignature
+		}
+		return nil
+	})
+}
+
+// Sign returns the HMAC-SHA1 of body keyed with secret
+func Sign(secret string, body []byte) []byte {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// SignatureHeader returns the X-Hook-Signature value Autotask would send for body
+func SignatureHeader(secret string, body []byte) string {
+	return "sha1=" + base64.StdEncoding.EncodeToString(Sign(secret, body))
+}
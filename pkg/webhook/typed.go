@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+// entityOf returns the typed entity for an event: the enriched entity when
+// there is one, and otherwise the payload fields decoded into a new T
+func entityOf[T any](event *Event) (*T, error) {
+	if entity, ok := event.Entity.(*T); ok {
+		return entity, nil
+	}
+	entity := new(T)
+	if err := event.Decode(entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// typed adapts a handler that takes a typed entity to a Handler
+func typed[T any](handler func(ctx context.Context, event *Event, entity *T) error) Handler {
+	return func(ctx context.Context, event *Event) error {
+		entity, err := entityOf[T](event)
+		if err != nil {
+			return err
+		}
+		return handler(ctx, event, entity)
+	}
+}
+
+// CompanyEvent is a company webhook event with the company it describes.
+// Company holds the full company when the receiver has an Enricher, and
+// otherwise only the fields sent in the payload.
+type CompanyEvent struct {
+	*Event
+	Company *autotask.Company
+}
+
+// ContactEvent is a contact webhook event with the contact it describes.
+// Contact holds the full contact when the receiver has an Enricher, and
+// otherwise only the fields sent in the payload.
+type ContactEvent struct {
+	*Event
+	Contact *autotask.Contact
+}
+
+// TicketEvent is a ticket webhook event with the ticket it describes.
+// Ticket holds the full ticket when the receiver has an Enricher, and
+// otherwise only the fields sent in the payload.
+type TicketEvent struct {
+	*Event
+	Ticket *autotask.Ticket
+}
+
+// TicketNoteEvent is a ticket note webhook event with the note it describes.
+// TicketNote holds the full note when the receiver has an Enricher, and
+// otherwise only the fields sent in the payload.
+type TicketNoteEvent struct {
+	*Event
+	TicketNote *autotask.TicketNote
+}
+
+// ConfigurationItemEvent is a configuration item webhook event with the item
+// it describes. ConfigurationItem holds the full item when the receiver has
+// an Enricher, and otherwise only the fields sent in the payload.
+type ConfigurationItemEvent struct {
+	*Event
+	ConfigurationItem *autotask.ConfigurationItem
+}
+
+// companyHandler adapts a CompanyEvent handler
+func companyHandler(handler func(ctx context.Context, event *CompanyEvent) error) Handler {
+	return typed(func(ctx context.Context, event *Event, company *autotask.Company) error {
+		return handler(ctx, &CompanyEvent{Event: event, Company: company})
+	})
+}
+
+// contactHandler adapts a ContactEvent handler
+func contactHandler(handler func(ctx context.Context, event *ContactEvent) error) Handler {
+	return typed(func(ctx context.Context, event *Event, contact *autotask.Contact) error {
+		return handler(ctx, &ContactEvent{Event: event, Contact: contact})
+	})
+}
+
+// ticketHandler adapts a TicketEvent handler
+func ticketHandler(handler func(ctx context.Context, event *TicketEvent) error) Handler {
+	return typed(func(ctx context.Context, event *Event, ticket *autotask.Ticket) error {
+		return handler(ctx, &TicketEvent{Event: event, Ticket: ticket})
+	})
+}
+
+// ticketNoteHandler adapts a TicketNoteEvent handler
+func ticketNoteHandler(handler func(ctx context.Context, event *TicketNoteEvent) error) Handler {
+	return typed(func(ctx context.Context, event *Event, note *autotask.TicketNote) error {
+		return handler(ctx, &TicketNoteEvent{Event: event, TicketNote: note})
+	})
+}
+
+// configurationItemHandler adapts a ConfigurationItemEvent handler
+func configurationItemHandler(handler func(ctx context.Context, event *ConfigurationItemEvent) error) Handler {
+	return typed(func(ctx context.Context, event *Event, item *autotask.ConfigurationItem) error {
+		return handler(ctx, &ConfigurationItemEvent{Event: event, ConfigurationItem: item})
+	})
+}
+
+// OnCompanyCreated registers a handler for new companies
+func (r *Receiver) OnCompanyCreated(handler func(ctx context.Context, event *CompanyEvent) error) {
+	r.Handle(EntityCompany, ActionCreate, companyHandler(handler))
+}
+
+// OnCompanyUpdated registers a handler for company updates
+func (r *Receiver) OnCompanyUpdated(handler func(ctx context.Context, event *CompanyEvent) error) {
+	r.Handle(EntityCompany, ActionUpdate, companyHandler(handler))
+}
+
+// OnCompanyDeleted registers a handler for deleted companies
+func (r *Receiver) OnCompanyDeleted(handler func(ctx context.Context, event *CompanyEvent) error) {
+	r.Handle(EntityCompany, ActionDelete, companyHandler(handler))
+}
+
+// OnContactCreated registers a handler for new contacts
+func (r *Receiver) OnContactCreated(handler func(ctx context.Context, event *ContactEvent) error) {
+	r.Handle(EntityContact, ActionCreate, contactHandler(handler))
+}
+
+// OnContactUpdated registers a handler for contact updates
+func (r *Receiver) OnContactUpdated(handler func(ctx context.Context, event *ContactEvent) error) {
+	r.Handle(EntityContact, ActionUpdate, contactHandler(handler))
+}
+
+// OnContactDeleted registers a handler for deleted contacts
+func (r *Receiver) OnContactDeleted(handler func(ctx context.Context, event *ContactEvent) error) {
+	r.Handle(EntityContact, ActionDelete, contactHandler(handler))
+}
+
+// OnTicketCreated registers a handler for new tickets
+func (r *Receiver) OnTicketCreated(handler func(ctx context.Context, event *TicketEvent) error) {
+	r.Handle(EntityTicket, ActionCreate, ticketHandler(handler))
+}
+
+// OnTicketUpdated registers a handler for ticket updates
+func (r *Receiver) OnTicketUpdated(handler func(ctx context.Context, event *TicketEvent) error) {
+	r.Handle(EntityTicket, ActionUpdate, ticketHandler(handler))
+}
+
+// OnTicketDeleted registers a handler for deleted tickets
+func (r *Receiver) OnTicketDeleted(handler func(ctx context.Context, event *TicketEvent) error) {
+	r.Handle(EntityTicket, ActionDelete, ticketHandler(handler))
+}
+
+// OnTicketNoteCreated registers a handler for new ticket notes
+func (r *Receiver) OnTicketNoteCreated(handler func(ctx context.Context, event *TicketNoteEvent) error) {
+	r.Handle(EntityTicketNote, ActionCreate, ticketNoteHandler(handler))
+}
+
+// OnTicketNoteUpdated registers a handler for ticket note updates
+func (r *Receiver) OnTicketNoteUpdated(handler func(ctx context.Context, event *TicketNoteEvent) error) {
+	r.Handle(EntityTicketNote, ActionUpdate, ticketNoteHandler(handler))
+}
+
+// OnConfigurationItemCreated registers a handler for new configuration items
+func (r *Receiver) OnConfigurationItemCreated(handler func(ctx context.Context, event *ConfigurationItemEvent) error) {
+	r.Handle(EntityConfigurationItem, ActionCreate, configurationItemHandler(handler))
+}
+
+// OnConfigurationItemUpdated registers a handler for configuration item updates
+func (r *Receiver) OnConfigurationItemUpdated(handler func(ctx context.Context, event *ConfigurationItemEvent) error) {
+	r.Handle(EntityConfigurationItem, ActionUpdate, configurationItemHandler(handler))
+}
+
+// OnConfigurationItemDeleted registers a handler for deleted configuration items
+func (r *Receiver) OnConfigurationItemDeleted(handler func(ctx context.Context, event *ConfigurationItemEvent) error) {
+	r.Handle(EntityConfigurationItem, ActionDelete, configurationItemHandler(handler))
+}
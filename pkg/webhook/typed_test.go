@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/asachs01/autotask-go/pkg/autotask"
+)
+
+func TestOnTicketUpdated(t *testing.T) {
+	receiver := NewReceiver("secret")
+
+	var got *TicketEvent
+	receiver.OnTicketUpdated(func(ctx context.Context, event *TicketEvent) error {
+		got = event
+		return nil
+	})
+	receiver.OnTicketCreated(func(ctx context.Context, event *TicketEvent) error {
+		t.Error("create handler should not run for an update")
+		return nil
+	})
+
+	autotask.AssertEqual(t, http.StatusOK, post(receiver, "secret", samplePayload), "webhook should be accepted")
+	autotask.AssertNotNil(t, got, "typed handler should run")
+	autotask.AssertEqual(t, int64(12345), got.Ticket.ID, "ticket ID should come from the event")
+	autotask.AssertEqual(t, "Printer offline", got.Ticket.Title, "payload fields should be decoded")
+	autotask.AssertEqual(t, int64(4), got.SequenceNumber, "event fields should be available")
+}
+
+func TestTypedHandlerUsesEnrichedEntity(t *testing.T) {
+	receiver := NewReceiver("")
+
+	var title string
+	receiver.OnTicketUpdated(func(ctx context.Context, event *TicketEvent) error {
+		title = event.Ticket.Title
+		return nil
+	})
+
+	event := &Event{Action: ActionUpdate, EntityType: EntityTicket, EntityID: 1, Entity: &autotask.Ticket{ID: 1, Title: "Full ticket"}}
+	autotask.AssertNil(t, receiver.Dispatch(context.Background(), event), "error should be nil")
+	autotask.AssertEqual(t, "Full ticket", title, "enriched ticket should be used")
+}